@@ -93,6 +93,7 @@ type GenericResponse struct {
 	Id            *wrapperspb.StringValue `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
 	Entity        *structpb.Struct        `protobuf:"bytes,4,opt,name=entity,proto3" json:"entity,omitempty"`
 	Timestamp     *timestamppb.Timestamp  `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Errors        []*ErrorDetail          `protobuf:"bytes,6,rep,name=errors,proto3" json:"errors,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -162,6 +163,13 @@ func (x *GenericResponse) GetTimestamp() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *GenericResponse) GetErrors() []*ErrorDetail {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
 // Tạo nhiều entity
 type BatchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -385,18 +393,24 @@ func (x *GetByIdRequest) GetMeta() *structpb.Struct {
 
 // Truy vấn linh hoạt: thay thế GetList & Search
 type QueryRequest struct {
-	state         protoimpl.MessageState     `protogen:"open.v1"`
-	EntityType    string                     `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
-	Page          int32                      `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                      `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	Query         string                     `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
-	SearchFields  []string                   `protobuf:"bytes,5,rep,name=search_fields,json=searchFields,proto3" json:"search_fields,omitempty"`
-	Filters       map[string]*structpb.Value `protobuf:"bytes,6,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Fields        []string                   `protobuf:"bytes,7,rep,name=fields,proto3" json:"fields,omitempty"`
-	Pipeline      []*structpb.Struct         `protobuf:"bytes,8,rep,name=pipeline,proto3" json:"pipeline,omitempty"`
-	Meta          *structpb.Struct           `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState     `protogen:"open.v1"`
+	EntityType   string                     `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	Page         int32                      `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize     int32                      `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Query        string                     `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	SearchFields []string                   `protobuf:"bytes,5,rep,name=search_fields,json=searchFields,proto3" json:"search_fields,omitempty"`
+	Filters      map[string]*structpb.Value `protobuf:"bytes,6,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Fields       []string                   `protobuf:"bytes,7,rep,name=fields,proto3" json:"fields,omitempty"`
+	Pipeline     []*structpb.Struct         `protobuf:"bytes,8,rep,name=pipeline,proto3" json:"pipeline,omitempty"`
+	// "auto" (mặc định): dùng $text nếu có text index, ngược lại dùng regex
+	// "text": bắt buộc dùng text index, "regex": luôn dùng regex
+	SearchMode string `protobuf:"bytes,9,opt,name=search_mode,json=searchMode,proto3" json:"search_mode,omitempty"`
+	// Mặc định tìm kiếm không phân biệt dấu tiếng Việt
+	AccentSensitive bool             `protobuf:"varint,10,opt,name=accent_sensitive,json=accentSensitive,proto3" json:"accent_sensitive,omitempty"`
+	Highlight       bool             `protobuf:"varint,11,opt,name=highlight,proto3" json:"highlight,omitempty"`
+	Meta            *structpb.Struct `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *QueryRequest) Reset() {
@@ -485,6 +499,27 @@ func (x *QueryRequest) GetPipeline() []*structpb.Struct {
 	return nil
 }
 
+func (x *QueryRequest) GetSearchMode() string {
+	if x != nil {
+		return x.SearchMode
+	}
+	return ""
+}
+
+func (x *QueryRequest) GetAccentSensitive() bool {
+	if x != nil {
+		return x.AccentSensitive
+	}
+	return false
+}
+
+func (x *QueryRequest) GetHighlight() bool {
+	if x != nil {
+		return x.Highlight
+	}
+	return false
+}
+
 func (x *QueryRequest) GetMeta() *structpb.Struct {
 	if x != nil {
 		return x.Meta
@@ -499,8 +534,11 @@ type QueryResponse struct {
 	Entities        []*structpb.Struct     `protobuf:"bytes,3,rep,name=entities,proto3" json:"entities,omitempty"`
 	Pagination      *Pagination            `protobuf:"bytes,4,opt,name=pagination,proto3" json:"pagination,omitempty"`
 	ExecutionTimeMs int64                  `protobuf:"varint,5,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// Cùng thứ tự với entities, chỉ có khi query != ""
+	Hits          []*SearchHit `protobuf:"bytes,6,rep,name=hits,proto3" json:"hits,omitempty"`
+	SearchMode    string       `protobuf:"bytes,7,opt,name=search_mode,json=searchMode,proto3" json:"search_mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *QueryResponse) Reset() {
@@ -568,6 +606,140 @@ func (x *QueryResponse) GetExecutionTimeMs() int64 {
 	return 0
 }
 
+func (x *QueryResponse) GetHits() []*SearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+func (x *QueryResponse) GetSearchMode() string {
+	if x != nil {
+		return x.SearchMode
+	}
+	return ""
+}
+
+type SearchHit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	Highlights    []*Highlight           `protobuf:"bytes,3,rep,name=highlights,proto3" json:"highlights,omitempty"`
+	EntityType    string                 `protobuf:"bytes,4,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchHit) Reset() {
+	*x = SearchHit{}
+	mi := &file_proto_common_common_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHit) ProtoMessage() {}
+
+func (x *SearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHit.ProtoReflect.Descriptor instead.
+func (*SearchHit) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SearchHit) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SearchHit) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SearchHit) GetHighlights() []*Highlight {
+	if x != nil {
+		return x.Highlights
+	}
+	return nil
+}
+
+func (x *SearchHit) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+type Highlight struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Snippets      []string               `protobuf:"bytes,2,rep,name=snippets,proto3" json:"snippets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Highlight) Reset() {
+	*x = Highlight{}
+	mi := &file_proto_common_common_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Highlight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Highlight) ProtoMessage() {}
+
+func (x *Highlight) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Highlight.ProtoReflect.Descriptor instead.
+func (*Highlight) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Highlight) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Highlight) GetSnippets() []string {
+	if x != nil {
+		return x.Snippets
+	}
+	return nil
+}
+
 type Pagination struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	CurrentPage   int32                  `protobuf:"varint,1,opt,name=current_page,json=currentPage,proto3" json:"current_page,omitempty"`
@@ -580,7 +752,7 @@ type Pagination struct {
 
 func (x *Pagination) Reset() {
 	*x = Pagination{}
-	mi := &file_proto_common_common_proto_msgTypes[7]
+	mi := &file_proto_common_common_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -592,7 +764,7 @@ func (x *Pagination) String() string {
 func (*Pagination) ProtoMessage() {}
 
 func (x *Pagination) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_common_common_proto_msgTypes[7]
+	mi := &file_proto_common_common_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -605,7 +777,7 @@ func (x *Pagination) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Pagination.ProtoReflect.Descriptor instead.
 func (*Pagination) Descriptor() ([]byte, []int) {
-	return file_proto_common_common_proto_rawDescGZIP(), []int{7}
+	return file_proto_common_common_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Pagination) GetCurrentPage() int32 {
@@ -649,7 +821,7 @@ type UpdateRequest struct {
 
 func (x *UpdateRequest) Reset() {
 	*x = UpdateRequest{}
-	mi := &file_proto_common_common_proto_msgTypes[8]
+	mi := &file_proto_common_common_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -661,7 +833,7 @@ func (x *UpdateRequest) String() string {
 func (*UpdateRequest) ProtoMessage() {}
 
 func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_common_common_proto_msgTypes[8]
+	mi := &file_proto_common_common_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -674,7 +846,7 @@ func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
 func (*UpdateRequest) Descriptor() ([]byte, []int) {
-	return file_proto_common_common_proto_rawDescGZIP(), []int{8}
+	return file_proto_common_common_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *UpdateRequest) GetEntityType() string {
@@ -713,17 +885,19 @@ func (x *UpdateRequest) GetMeta() *structpb.Struct {
 }
 
 type DeleteRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	EntityType    string                 `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
-	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
-	Meta          *structpb.Struct       `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	EntityType string                 `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	Id         string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// Chỉ trả về các bản ghi phụ thuộc bị ảnh hưởng, không xóa
+	DryRun        bool             `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	Meta          *structpb.Struct `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteRequest) Reset() {
 	*x = DeleteRequest{}
-	mi := &file_proto_common_common_proto_msgTypes[9]
+	mi := &file_proto_common_common_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -735,7 +909,7 @@ func (x *DeleteRequest) String() string {
 func (*DeleteRequest) ProtoMessage() {}
 
 func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_common_common_proto_msgTypes[9]
+	mi := &file_proto_common_common_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -748,7 +922,7 @@ func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
 func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return file_proto_common_common_proto_rawDescGZIP(), []int{9}
+	return file_proto_common_common_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *DeleteRequest) GetEntityType() string {
@@ -765,6 +939,13 @@ func (x *DeleteRequest) GetId() string {
 	return ""
 }
 
+func (x *DeleteRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
 func (x *DeleteRequest) GetMeta() *structpb.Struct {
 	if x != nil {
 		return x.Meta
@@ -777,13 +958,14 @@ type DeleteResponse struct {
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	DeletedCount  int32                  `protobuf:"varint,3,opt,name=deleted_count,json=deletedCount,proto3" json:"deleted_count,omitempty"`
+	Dependents    []*DependentEffect     `protobuf:"bytes,4,rep,name=dependents,proto3" json:"dependents,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteResponse) Reset() {
 	*x = DeleteResponse{}
-	mi := &file_proto_common_common_proto_msgTypes[10]
+	mi := &file_proto_common_common_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -795,7 +977,7 @@ func (x *DeleteResponse) String() string {
 func (*DeleteResponse) ProtoMessage() {}
 
 func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_common_common_proto_msgTypes[10]
+	mi := &file_proto_common_common_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -808,7 +990,7 @@ func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_common_common_proto_rawDescGZIP(), []int{10}
+	return file_proto_common_common_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *DeleteResponse) GetSuccess() bool {
@@ -832,11 +1014,19 @@ func (x *DeleteResponse) GetDeletedCount() int32 {
 	return 0
 }
 
+func (x *DeleteResponse) GetDependents() []*DependentEffect {
+	if x != nil {
+		return x.Dependents
+	}
+	return nil
+}
+
 type DeleteManyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	EntityType    string                 `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
 	Ids           []string               `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
 	Pipeline      []*structpb.Struct     `protobuf:"bytes,3,rep,name=pipeline,proto3" json:"pipeline,omitempty"`
+	DryRun        bool                   `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
 	Meta          *structpb.Struct       `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -844,7 +1034,7 @@ type DeleteManyRequest struct {
 
 func (x *DeleteManyRequest) Reset() {
 	*x = DeleteManyRequest{}
-	mi := &file_proto_common_common_proto_msgTypes[11]
+	mi := &file_proto_common_common_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -856,7 +1046,7 @@ func (x *DeleteManyRequest) String() string {
 func (*DeleteManyRequest) ProtoMessage() {}
 
 func (x *DeleteManyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_common_common_proto_msgTypes[11]
+	mi := &file_proto_common_common_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -869,7 +1059,7 @@ func (x *DeleteManyRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteManyRequest.ProtoReflect.Descriptor instead.
 func (*DeleteManyRequest) Descriptor() ([]byte, []int) {
-	return file_proto_common_common_proto_rawDescGZIP(), []int{11}
+	return file_proto_common_common_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *DeleteManyRequest) GetEntityType() string {
@@ -893,6 +1083,13 @@ func (x *DeleteManyRequest) GetPipeline() []*structpb.Struct {
 	return nil
 }
 
+func (x *DeleteManyRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
 func (x *DeleteManyRequest) GetMeta() *structpb.Struct {
 	if x != nil {
 		return x.Meta
@@ -906,13 +1103,15 @@ type DeleteManyResponse struct {
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	DeletedCount  int32                  `protobuf:"varint,3,opt,name=deleted_count,json=deletedCount,proto3" json:"deleted_count,omitempty"`
 	FailedIds     []string               `protobuf:"bytes,4,rep,name=failed_ids,json=failedIds,proto3" json:"failed_ids,omitempty"`
+	DeletedIds    []string               `protobuf:"bytes,5,rep,name=deleted_ids,json=deletedIds,proto3" json:"deleted_ids,omitempty"`
+	Dependents    []*DependentEffect     `protobuf:"bytes,6,rep,name=dependents,proto3" json:"dependents,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *DeleteManyResponse) Reset() {
 	*x = DeleteManyResponse{}
-	mi := &file_proto_common_common_proto_msgTypes[12]
+	mi := &file_proto_common_common_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -924,7 +1123,7 @@ func (x *DeleteManyResponse) String() string {
 func (*DeleteManyResponse) ProtoMessage() {}
 
 func (x *DeleteManyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_common_common_proto_msgTypes[12]
+	mi := &file_proto_common_common_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -937,7 +1136,7 @@ func (x *DeleteManyResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteManyResponse.ProtoReflect.Descriptor instead.
 func (*DeleteManyResponse) Descriptor() ([]byte, []int) {
-	return file_proto_common_common_proto_rawDescGZIP(), []int{12}
+	return file_proto_common_common_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *DeleteManyResponse) GetSuccess() bool {
@@ -968,32 +1167,48 @@ func (x *DeleteManyResponse) GetFailedIds() []string {
 	return nil
 }
 
-type AggregateRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	EntityType    string                 `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
-	Pipeline      []*structpb.Struct     `protobuf:"bytes,2,rep,name=pipeline,proto3" json:"pipeline,omitempty"`
-	AllowDiskUse  bool                   `protobuf:"varint,3,opt,name=allow_disk_use,json=allowDiskUse,proto3" json:"allow_disk_use,omitempty"`
-	MaxTimeMs     int32                  `protobuf:"varint,4,opt,name=max_time_ms,json=maxTimeMs,proto3" json:"max_time_ms,omitempty"`
-	Meta          *structpb.Struct       `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+func (x *DeleteManyResponse) GetDeletedIds() []string {
+	if x != nil {
+		return x.DeletedIds
+	}
+	return nil
+}
+
+func (x *DeleteManyResponse) GetDependents() []*DependentEffect {
+	if x != nil {
+		return x.Dependents
+	}
+	return nil
+}
+
+// Ảnh hưởng của việc xóa lên các bản ghi tham chiếu tới nó
+type DependentEffect struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	EntityType string                 `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	Field      string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	// restrict, cascade hoặc set_null
+	Action        string   `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Count         int32    `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	Ids           []string `protobuf:"bytes,5,rep,name=ids,proto3" json:"ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AggregateRequest) Reset() {
-	*x = AggregateRequest{}
-	mi := &file_proto_common_common_proto_msgTypes[13]
+func (x *DependentEffect) Reset() {
+	*x = DependentEffect{}
+	mi := &file_proto_common_common_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AggregateRequest) String() string {
+func (x *DependentEffect) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AggregateRequest) ProtoMessage() {}
+func (*DependentEffect) ProtoMessage() {}
 
-func (x *AggregateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_common_common_proto_msgTypes[13]
+func (x *DependentEffect) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1004,71 +1219,79 @@ func (x *AggregateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AggregateRequest.ProtoReflect.Descriptor instead.
-func (*AggregateRequest) Descriptor() ([]byte, []int) {
-	return file_proto_common_common_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use DependentEffect.ProtoReflect.Descriptor instead.
+func (*DependentEffect) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *AggregateRequest) GetEntityType() string {
+func (x *DependentEffect) GetEntityType() string {
 	if x != nil {
 		return x.EntityType
 	}
 	return ""
 }
 
-func (x *AggregateRequest) GetPipeline() []*structpb.Struct {
+func (x *DependentEffect) GetField() string {
 	if x != nil {
-		return x.Pipeline
+		return x.Field
 	}
-	return nil
+	return ""
 }
 
-func (x *AggregateRequest) GetAllowDiskUse() bool {
+func (x *DependentEffect) GetAction() string {
 	if x != nil {
-		return x.AllowDiskUse
+		return x.Action
 	}
-	return false
+	return ""
 }
 
-func (x *AggregateRequest) GetMaxTimeMs() int32 {
+func (x *DependentEffect) GetCount() int32 {
 	if x != nil {
-		return x.MaxTimeMs
+		return x.Count
 	}
 	return 0
 }
 
-func (x *AggregateRequest) GetMeta() *structpb.Struct {
+func (x *DependentEffect) GetIds() []string {
 	if x != nil {
-		return x.Meta
+		return x.Ids
 	}
 	return nil
 }
 
-type AggregateResponse struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Results         []*structpb.Struct     `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
-	ExecutionTimeMs int64                  `protobuf:"varint,4,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Mặc định tìm trong theses và archived_theses
+	EntityTypes []string `protobuf:"bytes,2,rep,name=entity_types,json=entityTypes,proto3" json:"entity_types,omitempty"`
+	Page        int32    `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize    int32    `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Số ký tự sai khác tối đa cho mỗi từ (0-2), mặc định tự động theo độ dài từ
+	Fuzziness *wrapperspb.Int32Value `protobuf:"bytes,5,opt,name=fuzziness,proto3" json:"fuzziness,omitempty"`
+	// entity_type, major, year
+	Facets        []string          `protobuf:"bytes,6,rep,name=facets,proto3" json:"facets,omitempty"`
+	Filters       map[string]string `protobuf:"bytes,7,rep,name=filters,proto3" json:"filters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Highlight     bool              `protobuf:"varint,8,opt,name=highlight,proto3" json:"highlight,omitempty"`
+	Meta          *structpb.Struct  `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AggregateResponse) Reset() {
-	*x = AggregateResponse{}
-	mi := &file_proto_common_common_proto_msgTypes[14]
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AggregateResponse) String() string {
+func (x *SearchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AggregateResponse) ProtoMessage() {}
+func (*SearchRequest) ProtoMessage() {}
 
-func (x *AggregateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_common_common_proto_msgTypes[14]
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1079,63 +1302,3951 @@ func (x *AggregateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AggregateResponse.ProtoReflect.Descriptor instead.
-func (*AggregateResponse) Descriptor() ([]byte, []int) {
-	return file_proto_common_common_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *AggregateResponse) GetSuccess() bool {
+func (x *SearchRequest) GetQuery() string {
 	if x != nil {
-		return x.Success
+		return x.Query
 	}
-	return false
+	return ""
 }
 
-func (x *AggregateResponse) GetMessage() string {
+func (x *SearchRequest) GetEntityTypes() []string {
 	if x != nil {
-		return x.Message
+		return x.EntityTypes
 	}
-	return ""
+	return nil
+}
+
+func (x *SearchRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetFuzziness() *wrapperspb.Int32Value {
+	if x != nil {
+		return x.Fuzziness
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetFacets() []string {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetFilters() map[string]string {
+	if x != nil {
+		return x.Filters
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetHighlight() bool {
+	if x != nil {
+		return x.Highlight
+	}
+	return false
+}
+
+func (x *SearchRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type SearchResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Hits    []*SearchHit           `protobuf:"bytes,3,rep,name=hits,proto3" json:"hits,omitempty"`
+	// Cùng thứ tự với hits
+	Entities        []*structpb.Struct `protobuf:"bytes,4,rep,name=entities,proto3" json:"entities,omitempty"`
+	Pagination      *Pagination        `protobuf:"bytes,5,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	Facets          []*Facet           `protobuf:"bytes,6,rep,name=facets,proto3" json:"facets,omitempty"`
+	ExecutionTimeMs int64              `protobuf:"varint,7,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SearchResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SearchResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SearchResponse) GetHits() []*SearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetEntities() []*structpb.Struct {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetPagination() *Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetFacets() []*Facet {
+	if x != nil {
+		return x.Facets
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetExecutionTimeMs() int64 {
+	if x != nil {
+		return x.ExecutionTimeMs
+	}
+	return 0
+}
+
+type Facet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Terms         []*FacetTerm           `protobuf:"bytes,2,rep,name=terms,proto3" json:"terms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Facet) Reset() {
+	*x = Facet{}
+	mi := &file_proto_common_common_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Facet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Facet) ProtoMessage() {}
+
+func (x *Facet) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Facet.ProtoReflect.Descriptor instead.
+func (*Facet) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Facet) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Facet) GetTerms() []*FacetTerm {
+	if x != nil {
+		return x.Terms
+	}
+	return nil
+}
+
+type FacetTerm struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Term          string                 `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FacetTerm) Reset() {
+	*x = FacetTerm{}
+	mi := &file_proto_common_common_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FacetTerm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FacetTerm) ProtoMessage() {}
+
+func (x *FacetTerm) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FacetTerm.ProtoReflect.Descriptor instead.
+func (*FacetTerm) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FacetTerm) GetTerm() string {
+	if x != nil {
+		return x.Term
+	}
+	return ""
+}
+
+func (x *FacetTerm) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type AggregateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntityType    string                 `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	Pipeline      []*structpb.Struct     `protobuf:"bytes,2,rep,name=pipeline,proto3" json:"pipeline,omitempty"`
+	AllowDiskUse  bool                   `protobuf:"varint,3,opt,name=allow_disk_use,json=allowDiskUse,proto3" json:"allow_disk_use,omitempty"`
+	MaxTimeMs     int32                  `protobuf:"varint,4,opt,name=max_time_ms,json=maxTimeMs,proto3" json:"max_time_ms,omitempty"`
+	Meta          *structpb.Struct       `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AggregateRequest) Reset() {
+	*x = AggregateRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AggregateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateRequest) ProtoMessage() {}
+
+func (x *AggregateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateRequest.ProtoReflect.Descriptor instead.
+func (*AggregateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AggregateRequest) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *AggregateRequest) GetPipeline() []*structpb.Struct {
+	if x != nil {
+		return x.Pipeline
+	}
+	return nil
+}
+
+func (x *AggregateRequest) GetAllowDiskUse() bool {
+	if x != nil {
+		return x.AllowDiskUse
+	}
+	return false
+}
+
+func (x *AggregateRequest) GetMaxTimeMs() int32 {
+	if x != nil {
+		return x.MaxTimeMs
+	}
+	return 0
+}
+
+func (x *AggregateRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type AggregateResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Results         []*structpb.Struct     `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+	ExecutionTimeMs int64                  `protobuf:"varint,4,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AggregateResponse) Reset() {
+	*x = AggregateResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AggregateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AggregateResponse) ProtoMessage() {}
+
+func (x *AggregateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AggregateResponse.ProtoReflect.Descriptor instead.
+func (*AggregateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AggregateResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AggregateResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AggregateResponse) GetResults() []*structpb.Struct {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *AggregateResponse) GetExecutionTimeMs() int64 {
+	if x != nil {
+		return x.ExecutionTimeMs
+	}
+	return 0
+}
+
+type ErrorDetail struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Code    string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Field   string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
+	Message string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// Với lỗi DUPLICATE: giá trị bị trùng và id của bản ghi đang giữ giá trị đó
+	Value      string `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	ExistingId string `protobuf:"bytes,5,opt,name=existing_id,json=existingId,proto3" json:"existing_id,omitempty"`
+	// Vị trí của entity trong batch
+	Index         int32 `protobuf:"varint,6,opt,name=index,proto3" json:"index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorDetail) Reset() {
+	*x = ErrorDetail{}
+	mi := &file_proto_common_common_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorDetail) ProtoMessage() {}
+
+func (x *ErrorDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorDetail.ProtoReflect.Descriptor instead.
+func (*ErrorDetail) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ErrorDetail) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetExistingId() string {
+	if x != nil {
+		return x.ExistingId
+	}
+	return ""
+}
+
+func (x *ErrorDetail) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+type FileMetadata struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	FileName string                 `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	MimeType string                 `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	// Bỏ qua: người sở hữu luôn là người gọi, lấy từ access token
+	OwnerId string `protobuf:"bytes,3,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// Entity sở hữu file, ví dụ submissions
+	EntityType string `protobuf:"bytes,4,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	EntityId   string `protobuf:"bytes,5,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	// SHA-256 (hex) do client tính, nếu có sẽ được kiểm tra sau khi upload
+	Checksum      string `protobuf:"bytes,6,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileMetadata) Reset() {
+	*x = FileMetadata{}
+	mi := &file_proto_common_common_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileMetadata) ProtoMessage() {}
+
+func (x *FileMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileMetadata.ProtoReflect.Descriptor instead.
+func (*FileMetadata) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *FileMetadata) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+type FileInfo struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	FileName   string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	MimeType   string                 `protobuf:"bytes,3,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	Size       int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Checksum   string                 `protobuf:"bytes,5,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	OwnerId    string                 `protobuf:"bytes,6,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	EntityType string                 `protobuf:"bytes,7,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	EntityId   string                 `protobuf:"bytes,8,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Storage    string                 `protobuf:"bytes,10,opt,name=storage,proto3" json:"storage,omitempty"`
+	// ok, missing, corrupt; rỗng nếu chưa kiểm tra
+	Integrity     string `protobuf:"bytes,11,opt,name=integrity,proto3" json:"integrity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileInfo) Reset() {
+	*x = FileInfo{}
+	mi := &file_proto_common_common_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileInfo) ProtoMessage() {}
+
+func (x *FileInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileInfo.ProtoReflect.Descriptor instead.
+func (*FileInfo) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *FileInfo) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FileInfo) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *FileInfo) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *FileInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileInfo) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *FileInfo) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *FileInfo) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *FileInfo) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *FileInfo) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *FileInfo) GetStorage() string {
+	if x != nil {
+		return x.Storage
+	}
+	return ""
+}
+
+func (x *FileInfo) GetIntegrity() string {
+	if x != nil {
+		return x.Integrity
+	}
+	return ""
+}
+
+type UploadRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*UploadRequest_Metadata
+	//	*UploadRequest_Chunk
+	Data          isUploadRequest_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadRequest) Reset() {
+	*x = UploadRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadRequest) ProtoMessage() {}
+
+func (x *UploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadRequest.ProtoReflect.Descriptor instead.
+func (*UploadRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *UploadRequest) GetData() isUploadRequest_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UploadRequest) GetMetadata() *FileMetadata {
+	if x != nil {
+		if x, ok := x.Data.(*UploadRequest_Metadata); ok {
+			return x.Metadata
+		}
+	}
+	return nil
+}
+
+func (x *UploadRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Data.(*UploadRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadRequest_Data interface {
+	isUploadRequest_Data()
+}
+
+type UploadRequest_Metadata struct {
+	Metadata *FileMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+}
+
+type UploadRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadRequest_Metadata) isUploadRequest_Data() {}
+
+func (*UploadRequest_Chunk) isUploadRequest_Data() {}
+
+type UploadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	File          *FileInfo              `protobuf:"bytes,3,opt,name=file,proto3" json:"file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadResponse) Reset() {
+	*x = UploadResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadResponse) ProtoMessage() {}
+
+func (x *UploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadResponse.ProtoReflect.Descriptor instead.
+func (*UploadResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *UploadResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UploadResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UploadResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+type DownloadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Meta          *structpb.Struct       `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadRequest) Reset() {
+	*x = DownloadRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadRequest) ProtoMessage() {}
+
+func (x *DownloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadRequest.ProtoReflect.Descriptor instead.
+func (*DownloadRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *DownloadRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DownloadRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type DownloadResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*DownloadResponse_Info
+	//	*DownloadResponse_Chunk
+	Data          isDownloadResponse_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadResponse) Reset() {
+	*x = DownloadResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadResponse) ProtoMessage() {}
+
+func (x *DownloadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadResponse.ProtoReflect.Descriptor instead.
+func (*DownloadResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DownloadResponse) GetData() isDownloadResponse_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *DownloadResponse) GetInfo() *FileInfo {
+	if x != nil {
+		if x, ok := x.Data.(*DownloadResponse_Info); ok {
+			return x.Info
+		}
+	}
+	return nil
+}
+
+func (x *DownloadResponse) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Data.(*DownloadResponse_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isDownloadResponse_Data interface {
+	isDownloadResponse_Data()
+}
+
+type DownloadResponse_Info struct {
+	Info *FileInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"`
+}
+
+type DownloadResponse_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*DownloadResponse_Info) isDownloadResponse_Data() {}
+
+func (*DownloadResponse_Chunk) isDownloadResponse_Data() {}
+
+type GetFileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Meta          *structpb.Struct       `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFileRequest) Reset() {
+	*x = GetFileRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFileRequest) ProtoMessage() {}
+
+func (x *GetFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFileRequest.ProtoReflect.Descriptor instead.
+func (*GetFileRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetFileRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetFileRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type FileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	File          *FileInfo              `protobuf:"bytes,3,opt,name=file,proto3" json:"file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileResponse) Reset() {
+	*x = FileResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileResponse) ProtoMessage() {}
+
+func (x *FileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileResponse.ProtoReflect.Descriptor instead.
+func (*FileResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *FileResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *FileResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *FileResponse) GetFile() *FileInfo {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+type ListFilesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntityType    string                 `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	EntityId      string                 `protobuf:"bytes,2,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	Meta          *structpb.Struct       `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFilesRequest) Reset() {
+	*x = ListFilesRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFilesRequest) ProtoMessage() {}
+
+func (x *ListFilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFilesRequest.ProtoReflect.Descriptor instead.
+func (*ListFilesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListFilesRequest) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *ListFilesRequest) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *ListFilesRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type ListFilesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Files         []*FileInfo            `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFilesResponse) Reset() {
+	*x = ListFilesResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFilesResponse) ProtoMessage() {}
+
+func (x *ListFilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFilesResponse.ProtoReflect.Descriptor instead.
+func (*ListFilesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListFilesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListFilesResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListFilesResponse) GetFiles() []*FileInfo {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type DownloadURLRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Mặc định 15 phút, tối đa 24 giờ
+	TtlSeconds    int32 `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadURLRequest) Reset() {
+	*x = DownloadURLRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadURLRequest) ProtoMessage() {}
+
+func (x *DownloadURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadURLRequest.ProtoReflect.Descriptor instead.
+func (*DownloadURLRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *DownloadURLRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DownloadURLRequest) GetTtlSeconds() int32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type DownloadURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadURLResponse) Reset() {
+	*x = DownloadURLResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadURLResponse) ProtoMessage() {}
+
+func (x *DownloadURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadURLResponse.ProtoReflect.Descriptor instead.
+func (*DownloadURLResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *DownloadURLResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DownloadURLResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DownloadURLResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *DownloadURLResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type NextSequenceRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Số giá trị liên tiếp cần lấy, mặc định 1
+	Count         int32            `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Meta          *structpb.Struct `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NextSequenceRequest) Reset() {
+	*x = NextSequenceRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NextSequenceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextSequenceRequest) ProtoMessage() {}
+
+func (x *NextSequenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextSequenceRequest.ProtoReflect.Descriptor instead.
+func (*NextSequenceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *NextSequenceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NextSequenceRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *NextSequenceRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type NextSequenceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Values        []int64                `protobuf:"varint,3,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NextSequenceResponse) Reset() {
+	*x = NextSequenceResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NextSequenceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextSequenceResponse) ProtoMessage() {}
+
+func (x *NextSequenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextSequenceResponse.ProtoReflect.Descriptor instead.
+func (*NextSequenceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *NextSequenceResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *NextSequenceResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *NextSequenceResponse) GetValues() []int64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type VersionInfo struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Version int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	// update, delete hoặc revert: thao tác đã thay thế phiên bản này
+	Operation     string                 `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	Meta          *structpb.Struct       `protobuf:"bytes,3,opt,name=meta,proto3" json:"meta,omitempty"`
+	RevertedTo    int64                  `protobuf:"varint,4,opt,name=reverted_to,json=revertedTo,proto3" json:"reverted_to,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VersionInfo) Reset() {
+	*x = VersionInfo{}
+	mi := &file_proto_common_common_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionInfo) ProtoMessage() {}
+
+func (x *VersionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionInfo.ProtoReflect.Descriptor instead.
+func (*VersionInfo) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *VersionInfo) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *VersionInfo) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *VersionInfo) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *VersionInfo) GetRevertedTo() int64 {
+	if x != nil {
+		return x.RevertedTo
+	}
+	return 0
+}
+
+func (x *VersionInfo) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListVersionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntityType    string                 `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	EntityId      string                 `protobuf:"bytes,2,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Meta          *structpb.Struct       `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVersionsRequest) Reset() {
+	*x = ListVersionsRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersionsRequest) ProtoMessage() {}
+
+func (x *ListVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersionsRequest.ProtoReflect.Descriptor instead.
+func (*ListVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListVersionsRequest) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *ListVersionsRequest) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *ListVersionsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListVersionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListVersionsRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type ListVersionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Versions      []*VersionInfo         `protobuf:"bytes,3,rep,name=versions,proto3" json:"versions,omitempty"`
+	Total         int64                  `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVersionsResponse) Reset() {
+	*x = ListVersionsResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersionsResponse) ProtoMessage() {}
+
+func (x *ListVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersionsResponse.ProtoReflect.Descriptor instead.
+func (*ListVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListVersionsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListVersionsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListVersionsResponse) GetVersions() []*VersionInfo {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *ListVersionsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntityType    string                 `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	EntityId      string                 `protobuf:"bytes,2,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Fields        []string               `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty"`
+	Meta          *structpb.Struct       `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVersionRequest) Reset() {
+	*x = GetVersionRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersionRequest) ProtoMessage() {}
+
+func (x *GetVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersionRequest.ProtoReflect.Descriptor instead.
+func (*GetVersionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetVersionRequest) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *GetVersionRequest) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *GetVersionRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *GetVersionRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *GetVersionRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type VersionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Version       *VersionInfo           `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Entity        *structpb.Struct       `protobuf:"bytes,4,opt,name=entity,proto3" json:"entity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VersionResponse) Reset() {
+	*x = VersionResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionResponse) ProtoMessage() {}
+
+func (x *VersionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionResponse.ProtoReflect.Descriptor instead.
+func (*VersionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *VersionResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *VersionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *VersionResponse) GetVersion() *VersionInfo {
+	if x != nil {
+		return x.Version
+	}
+	return nil
+}
+
+func (x *VersionResponse) GetEntity() *structpb.Struct {
+	if x != nil {
+		return x.Entity
+	}
+	return nil
+}
+
+type DiffVersionsRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	EntityType  string                 `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	EntityId    string                 `protobuf:"bytes,2,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	FromVersion int64                  `protobuf:"varint,3,opt,name=from_version,json=fromVersion,proto3" json:"from_version,omitempty"`
+	// 0 là bản hiện tại của entity
+	ToVersion     int64            `protobuf:"varint,4,opt,name=to_version,json=toVersion,proto3" json:"to_version,omitempty"`
+	Meta          *structpb.Struct `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffVersionsRequest) Reset() {
+	*x = DiffVersionsRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffVersionsRequest) ProtoMessage() {}
+
+func (x *DiffVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffVersionsRequest.ProtoReflect.Descriptor instead.
+func (*DiffVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *DiffVersionsRequest) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *DiffVersionsRequest) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *DiffVersionsRequest) GetFromVersion() int64 {
+	if x != nil {
+		return x.FromVersion
+	}
+	return 0
+}
+
+func (x *DiffVersionsRequest) GetToVersion() int64 {
+	if x != nil {
+		return x.ToVersion
+	}
+	return 0
+}
+
+func (x *DiffVersionsRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type FieldChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Đường dẫn dạng a.b cho document lồng nhau
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// added, removed, changed
+	Kind          string          `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	From          *structpb.Value `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To            *structpb.Value `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FieldChange) Reset() {
+	*x = FieldChange{}
+	mi := &file_proto_common_common_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FieldChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldChange) ProtoMessage() {}
+
+func (x *FieldChange) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldChange.ProtoReflect.Descriptor instead.
+func (*FieldChange) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *FieldChange) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FieldChange) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *FieldChange) GetFrom() *structpb.Value {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *FieldChange) GetTo() *structpb.Value {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type DiffVersionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Changes       []*FieldChange         `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffVersionsResponse) Reset() {
+	*x = DiffVersionsResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffVersionsResponse) ProtoMessage() {}
+
+func (x *DiffVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffVersionsResponse.ProtoReflect.Descriptor instead.
+func (*DiffVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *DiffVersionsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DiffVersionsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DiffVersionsResponse) GetChanges() []*FieldChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type RevertToVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EntityType    string                 `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	EntityId      string                 `protobuf:"bytes,2,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Meta          *structpb.Struct       `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevertToVersionRequest) Reset() {
+	*x = RevertToVersionRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevertToVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevertToVersionRequest) ProtoMessage() {}
+
+func (x *RevertToVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevertToVersionRequest.ProtoReflect.Descriptor instead.
+func (*RevertToVersionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RevertToVersionRequest) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *RevertToVersionRequest) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *RevertToVersionRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RevertToVersionRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type TransitionThesisRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ThesisId string                 `protobuf:"bytes,1,opt,name=thesis_id,json=thesisId,proto3" json:"thesis_id,omitempty"`
+	// Key trạng thái đích: proposed, approved, in_progress, submitted,
+	// under_review, scheduled_for_defense, defended, archived
+	ToState string `protobuf:"bytes,2,opt,name=to_state,json=toState,proto3" json:"to_state,omitempty"`
+	Reason  string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Người thực hiện lấy từ access token, user_id/roles trong meta bị bỏ qua
+	Meta          *structpb.Struct `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitionThesisRequest) Reset() {
+	*x = TransitionThesisRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitionThesisRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitionThesisRequest) ProtoMessage() {}
+
+func (x *TransitionThesisRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitionThesisRequest.ProtoReflect.Descriptor instead.
+func (*TransitionThesisRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *TransitionThesisRequest) GetThesisId() string {
+	if x != nil {
+		return x.ThesisId
+	}
+	return ""
+}
+
+func (x *TransitionThesisRequest) GetToState() string {
+	if x != nil {
+		return x.ToState
+	}
+	return ""
+}
+
+func (x *TransitionThesisRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *TransitionThesisRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type TransitionThesisResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Success   bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message   string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	FromState string                 `protobuf:"bytes,3,opt,name=from_state,json=fromState,proto3" json:"from_state,omitempty"`
+	ToState   string                 `protobuf:"bytes,4,opt,name=to_state,json=toState,proto3" json:"to_state,omitempty"`
+	Entity    *structpb.Struct       `protobuf:"bytes,5,opt,name=entity,proto3" json:"entity,omitempty"`
+	Errors    []*ErrorDetail         `protobuf:"bytes,6,rep,name=errors,proto3" json:"errors,omitempty"`
+	// Các trạng thái có thể chuyển tới từ trạng thái hiện tại
+	AllowedStates []string `protobuf:"bytes,7,rep,name=allowed_states,json=allowedStates,proto3" json:"allowed_states,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransitionThesisResponse) Reset() {
+	*x = TransitionThesisResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransitionThesisResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransitionThesisResponse) ProtoMessage() {}
+
+func (x *TransitionThesisResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransitionThesisResponse.ProtoReflect.Descriptor instead.
+func (*TransitionThesisResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *TransitionThesisResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TransitionThesisResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TransitionThesisResponse) GetFromState() string {
+	if x != nil {
+		return x.FromState
+	}
+	return ""
+}
+
+func (x *TransitionThesisResponse) GetToState() string {
+	if x != nil {
+		return x.ToState
+	}
+	return ""
+}
+
+func (x *TransitionThesisResponse) GetEntity() *structpb.Struct {
+	if x != nil {
+		return x.Entity
+	}
+	return nil
+}
+
+func (x *TransitionThesisResponse) GetErrors() []*ErrorDetail {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *TransitionThesisResponse) GetAllowedStates() []string {
+	if x != nil {
+		return x.AllowedStates
+	}
+	return nil
+}
+
+type SupervisorCapacity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SupervisorId  string                 `protobuf:"bytes,1,opt,name=supervisor_id,json=supervisorId,proto3" json:"supervisor_id,omitempty"`
+	MaxPrimary    int32                  `protobuf:"varint,2,opt,name=max_primary,json=maxPrimary,proto3" json:"max_primary,omitempty"`
+	MaxCo         int32                  `protobuf:"varint,3,opt,name=max_co,json=maxCo,proto3" json:"max_co,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SupervisorCapacity) Reset() {
+	*x = SupervisorCapacity{}
+	mi := &file_proto_common_common_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SupervisorCapacity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SupervisorCapacity) ProtoMessage() {}
+
+func (x *SupervisorCapacity) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SupervisorCapacity.ProtoReflect.Descriptor instead.
+func (*SupervisorCapacity) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SupervisorCapacity) GetSupervisorId() string {
+	if x != nil {
+		return x.SupervisorId
+	}
+	return ""
+}
+
+func (x *SupervisorCapacity) GetMaxPrimary() int32 {
+	if x != nil {
+		return x.MaxPrimary
+	}
+	return 0
+}
+
+func (x *SupervisorCapacity) GetMaxCo() int32 {
+	if x != nil {
+		return x.MaxCo
+	}
+	return 0
+}
+
+type AssignSupervisorsRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	AcademicYear string                 `protobuf:"bytes,1,opt,name=academic_year,json=academicYear,proto3" json:"academic_year,omitempty"`
+	Semester     int32                  `protobuf:"varint,2,opt,name=semester,proto3" json:"semester,omitempty"`
+	// Rỗng: mọi luận văn chưa có người hướng dẫn trong học kỳ
+	ThesisIds []string `protobuf:"bytes,3,rep,name=thesis_ids,json=thesisIds,proto3" json:"thesis_ids,omitempty"`
+	// Rỗng: mọi giảng viên đang hoạt động
+	SupervisorIds []string `protobuf:"bytes,4,rep,name=supervisor_ids,json=supervisorIds,proto3" json:"supervisor_ids,omitempty"`
+	// Ghi đè max_theses / max_co_supervised của giảng viên
+	Capacities []*SupervisorCapacity `protobuf:"bytes,5,rep,name=capacities,proto3" json:"capacities,omitempty"`
+	// Mặc định 5 luận văn mỗi học kỳ
+	DefaultCapacity      int32 `protobuf:"varint,6,opt,name=default_capacity,json=defaultCapacity,proto3" json:"default_capacity,omitempty"`
+	WithCoSupervisors    bool  `protobuf:"varint,7,opt,name=with_co_supervisors,json=withCoSupervisors,proto3" json:"with_co_supervisors,omitempty"`
+	AllowCrossDepartment bool  `protobuf:"varint,8,opt,name=allow_cross_department,json=allowCrossDepartment,proto3" json:"allow_cross_department,omitempty"`
+	// Chỉ tính toán, không ghi
+	Preview       bool             `protobuf:"varint,9,opt,name=preview,proto3" json:"preview,omitempty"`
+	Meta          *structpb.Struct `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignSupervisorsRequest) Reset() {
+	*x = AssignSupervisorsRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignSupervisorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignSupervisorsRequest) ProtoMessage() {}
+
+func (x *AssignSupervisorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignSupervisorsRequest.ProtoReflect.Descriptor instead.
+func (*AssignSupervisorsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *AssignSupervisorsRequest) GetAcademicYear() string {
+	if x != nil {
+		return x.AcademicYear
+	}
+	return ""
+}
+
+func (x *AssignSupervisorsRequest) GetSemester() int32 {
+	if x != nil {
+		return x.Semester
+	}
+	return 0
+}
+
+func (x *AssignSupervisorsRequest) GetThesisIds() []string {
+	if x != nil {
+		return x.ThesisIds
+	}
+	return nil
+}
+
+func (x *AssignSupervisorsRequest) GetSupervisorIds() []string {
+	if x != nil {
+		return x.SupervisorIds
+	}
+	return nil
+}
+
+func (x *AssignSupervisorsRequest) GetCapacities() []*SupervisorCapacity {
+	if x != nil {
+		return x.Capacities
+	}
+	return nil
+}
+
+func (x *AssignSupervisorsRequest) GetDefaultCapacity() int32 {
+	if x != nil {
+		return x.DefaultCapacity
+	}
+	return 0
+}
+
+func (x *AssignSupervisorsRequest) GetWithCoSupervisors() bool {
+	if x != nil {
+		return x.WithCoSupervisors
+	}
+	return false
+}
+
+func (x *AssignSupervisorsRequest) GetAllowCrossDepartment() bool {
+	if x != nil {
+		return x.AllowCrossDepartment
+	}
+	return false
+}
+
+func (x *AssignSupervisorsRequest) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+func (x *AssignSupervisorsRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type ProposedAssignment struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	ThesisId     string                 `protobuf:"bytes,1,opt,name=thesis_id,json=thesisId,proto3" json:"thesis_id,omitempty"`
+	SupervisorId string                 `protobuf:"bytes,2,opt,name=supervisor_id,json=supervisorId,proto3" json:"supervisor_id,omitempty"`
+	// primary hoặc co_supervisor
+	Role    string   `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	Score   float64  `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`
+	Reasons []string `protobuf:"bytes,5,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	// Id của supervisor_assignments sau khi ghi
+	AssignmentId  string `protobuf:"bytes,6,opt,name=assignment_id,json=assignmentId,proto3" json:"assignment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProposedAssignment) Reset() {
+	*x = ProposedAssignment{}
+	mi := &file_proto_common_common_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProposedAssignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposedAssignment) ProtoMessage() {}
+
+func (x *ProposedAssignment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposedAssignment.ProtoReflect.Descriptor instead.
+func (*ProposedAssignment) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ProposedAssignment) GetThesisId() string {
+	if x != nil {
+		return x.ThesisId
+	}
+	return ""
+}
+
+func (x *ProposedAssignment) GetSupervisorId() string {
+	if x != nil {
+		return x.SupervisorId
+	}
+	return ""
+}
+
+func (x *ProposedAssignment) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ProposedAssignment) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ProposedAssignment) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *ProposedAssignment) GetAssignmentId() string {
+	if x != nil {
+		return x.AssignmentId
+	}
+	return ""
+}
+
+type UnassignedThesis struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThesisId      string                 `protobuf:"bytes,1,opt,name=thesis_id,json=thesisId,proto3" json:"thesis_id,omitempty"`
+	Role          string                 `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnassignedThesis) Reset() {
+	*x = UnassignedThesis{}
+	mi := &file_proto_common_common_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnassignedThesis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnassignedThesis) ProtoMessage() {}
+
+func (x *UnassignedThesis) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnassignedThesis.ProtoReflect.Descriptor instead.
+func (*UnassignedThesis) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *UnassignedThesis) GetThesisId() string {
+	if x != nil {
+		return x.ThesisId
+	}
+	return ""
+}
+
+func (x *UnassignedThesis) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *UnassignedThesis) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SupervisorLoad struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SupervisorId  string                 `protobuf:"bytes,1,opt,name=supervisor_id,json=supervisorId,proto3" json:"supervisor_id,omitempty"`
+	Primary       int32                  `protobuf:"varint,2,opt,name=primary,proto3" json:"primary,omitempty"`
+	Co            int32                  `protobuf:"varint,3,opt,name=co,proto3" json:"co,omitempty"`
+	MaxPrimary    int32                  `protobuf:"varint,4,opt,name=max_primary,json=maxPrimary,proto3" json:"max_primary,omitempty"`
+	MaxCo         int32                  `protobuf:"varint,5,opt,name=max_co,json=maxCo,proto3" json:"max_co,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SupervisorLoad) Reset() {
+	*x = SupervisorLoad{}
+	mi := &file_proto_common_common_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SupervisorLoad) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SupervisorLoad) ProtoMessage() {}
+
+func (x *SupervisorLoad) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SupervisorLoad.ProtoReflect.Descriptor instead.
+func (*SupervisorLoad) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *SupervisorLoad) GetSupervisorId() string {
+	if x != nil {
+		return x.SupervisorId
+	}
+	return ""
+}
+
+func (x *SupervisorLoad) GetPrimary() int32 {
+	if x != nil {
+		return x.Primary
+	}
+	return 0
+}
+
+func (x *SupervisorLoad) GetCo() int32 {
+	if x != nil {
+		return x.Co
+	}
+	return 0
+}
+
+func (x *SupervisorLoad) GetMaxPrimary() int32 {
+	if x != nil {
+		return x.MaxPrimary
+	}
+	return 0
+}
+
+func (x *SupervisorLoad) GetMaxCo() int32 {
+	if x != nil {
+		return x.MaxCo
+	}
+	return 0
+}
+
+type AssignSupervisorsResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Preview bool                   `protobuf:"varint,3,opt,name=preview,proto3" json:"preview,omitempty"`
+	// Id của lần phân công, ghi trong event_logs và supervisor_assignments
+	RunId         string                `protobuf:"bytes,4,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	Assignments   []*ProposedAssignment `protobuf:"bytes,5,rep,name=assignments,proto3" json:"assignments,omitempty"`
+	Unassigned    []*UnassignedThesis   `protobuf:"bytes,6,rep,name=unassigned,proto3" json:"unassigned,omitempty"`
+	Loads         []*SupervisorLoad     `protobuf:"bytes,7,rep,name=loads,proto3" json:"loads,omitempty"`
+	Errors        []*ErrorDetail        `protobuf:"bytes,8,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignSupervisorsResponse) Reset() {
+	*x = AssignSupervisorsResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignSupervisorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignSupervisorsResponse) ProtoMessage() {}
+
+func (x *AssignSupervisorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignSupervisorsResponse.ProtoReflect.Descriptor instead.
+func (*AssignSupervisorsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *AssignSupervisorsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AssignSupervisorsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AssignSupervisorsResponse) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+func (x *AssignSupervisorsResponse) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *AssignSupervisorsResponse) GetAssignments() []*ProposedAssignment {
+	if x != nil {
+		return x.Assignments
+	}
+	return nil
+}
+
+func (x *AssignSupervisorsResponse) GetUnassigned() []*UnassignedThesis {
+	if x != nil {
+		return x.Unassigned
+	}
+	return nil
+}
+
+func (x *AssignSupervisorsResponse) GetLoads() []*SupervisorLoad {
+	if x != nil {
+		return x.Loads
+	}
+	return nil
+}
+
+func (x *AssignSupervisorsResponse) GetErrors() []*ErrorDetail {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type TimeWindow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeWindow) Reset() {
+	*x = TimeWindow{}
+	mi := &file_proto_common_common_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeWindow) ProtoMessage() {}
+
+func (x *TimeWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeWindow.ProtoReflect.Descriptor instead.
+func (*TimeWindow) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *TimeWindow) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *TimeWindow) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type DefenseRoom struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Location      string                 `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	Building      string                 `protobuf:"bytes,2,opt,name=building,proto3" json:"building,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DefenseRoom) Reset() {
+	*x = DefenseRoom{}
+	mi := &file_proto_common_common_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DefenseRoom) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DefenseRoom) ProtoMessage() {}
+
+func (x *DefenseRoom) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DefenseRoom.ProtoReflect.Descriptor instead.
+func (*DefenseRoom) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *DefenseRoom) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *DefenseRoom) GetBuilding() string {
+	if x != nil {
+		return x.Building
+	}
+	return ""
+}
+
+type MemberAvailability struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MemberId      string                 `protobuf:"bytes,1,opt,name=member_id,json=memberId,proto3" json:"member_id,omitempty"`
+	Unavailable   []*TimeWindow          `protobuf:"bytes,2,rep,name=unavailable,proto3" json:"unavailable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MemberAvailability) Reset() {
+	*x = MemberAvailability{}
+	mi := &file_proto_common_common_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemberAvailability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemberAvailability) ProtoMessage() {}
+
+func (x *MemberAvailability) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemberAvailability.ProtoReflect.Descriptor instead.
+func (*MemberAvailability) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *MemberAvailability) GetMemberId() string {
+	if x != nil {
+		return x.MemberId
+	}
+	return ""
+}
+
+func (x *MemberAvailability) GetUnavailable() []*TimeWindow {
+	if x != nil {
+		return x.Unavailable
+	}
+	return nil
+}
+
+type ScheduleDefensesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Rỗng: các luận văn của học kỳ chưa có lịch bảo vệ
+	ThesisIds    []string      `protobuf:"bytes,1,rep,name=thesis_ids,json=thesisIds,proto3" json:"thesis_ids,omitempty"`
+	AcademicYear string        `protobuf:"bytes,2,opt,name=academic_year,json=academicYear,proto3" json:"academic_year,omitempty"`
+	Semester     int32         `protobuf:"varint,3,opt,name=semester,proto3" json:"semester,omitempty"`
+	Windows      []*TimeWindow `protobuf:"bytes,4,rep,name=windows,proto3" json:"windows,omitempty"`
+	// Mặc định 60 phút
+	SlotMinutes int32          `protobuf:"varint,5,opt,name=slot_minutes,json=slotMinutes,proto3" json:"slot_minutes,omitempty"`
+	Rooms       []*DefenseRoom `protobuf:"bytes,6,rep,name=rooms,proto3" json:"rooms,omitempty"`
+	// Rỗng: mọi giảng viên đang hoạt động
+	MemberIds []string `protobuf:"bytes,7,rep,name=member_ids,json=memberIds,proto3" json:"member_ids,omitempty"`
+	// Bổ sung cho lịch bận trong defense_availability
+	Availability []*MemberAvailability `protobuf:"bytes,8,rep,name=availability,proto3" json:"availability,omitempty"`
+	// Mặc định 3: chủ tịch, thư ký, ủy viên
+	CommitteeSize int32 `protobuf:"varint,9,opt,name=committee_size,json=committeeSize,proto3" json:"committee_size,omitempty"`
+	// Giảng viên hướng dẫn ngồi hội đồng với vai trò ủy viên
+	SupervisorInCommittee bool             `protobuf:"varint,10,opt,name=supervisor_in_committee,json=supervisorInCommittee,proto3" json:"supervisor_in_committee,omitempty"`
+	Preview               bool             `protobuf:"varint,11,opt,name=preview,proto3" json:"preview,omitempty"`
+	Meta                  *structpb.Struct `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *ScheduleDefensesRequest) Reset() {
+	*x = ScheduleDefensesRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleDefensesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleDefensesRequest) ProtoMessage() {}
+
+func (x *ScheduleDefensesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleDefensesRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleDefensesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ScheduleDefensesRequest) GetThesisIds() []string {
+	if x != nil {
+		return x.ThesisIds
+	}
+	return nil
+}
+
+func (x *ScheduleDefensesRequest) GetAcademicYear() string {
+	if x != nil {
+		return x.AcademicYear
+	}
+	return ""
+}
+
+func (x *ScheduleDefensesRequest) GetSemester() int32 {
+	if x != nil {
+		return x.Semester
+	}
+	return 0
+}
+
+func (x *ScheduleDefensesRequest) GetWindows() []*TimeWindow {
+	if x != nil {
+		return x.Windows
+	}
+	return nil
+}
+
+func (x *ScheduleDefensesRequest) GetSlotMinutes() int32 {
+	if x != nil {
+		return x.SlotMinutes
+	}
+	return 0
+}
+
+func (x *ScheduleDefensesRequest) GetRooms() []*DefenseRoom {
+	if x != nil {
+		return x.Rooms
+	}
+	return nil
+}
+
+func (x *ScheduleDefensesRequest) GetMemberIds() []string {
+	if x != nil {
+		return x.MemberIds
+	}
+	return nil
+}
+
+func (x *ScheduleDefensesRequest) GetAvailability() []*MemberAvailability {
+	if x != nil {
+		return x.Availability
+	}
+	return nil
+}
+
+func (x *ScheduleDefensesRequest) GetCommitteeSize() int32 {
+	if x != nil {
+		return x.CommitteeSize
+	}
+	return 0
+}
+
+func (x *ScheduleDefensesRequest) GetSupervisorInCommittee() bool {
+	if x != nil {
+		return x.SupervisorInCommittee
+	}
+	return false
+}
+
+func (x *ScheduleDefensesRequest) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+func (x *ScheduleDefensesRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type CommitteeSeat struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	MemberId string                 `protobuf:"bytes,1,opt,name=member_id,json=memberId,proto3" json:"member_id,omitempty"`
+	// chair, secretary, member
+	Role          string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitteeSeat) Reset() {
+	*x = CommitteeSeat{}
+	mi := &file_proto_common_common_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitteeSeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitteeSeat) ProtoMessage() {}
+
+func (x *CommitteeSeat) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitteeSeat.ProtoReflect.Descriptor instead.
+func (*CommitteeSeat) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *CommitteeSeat) GetMemberId() string {
+	if x != nil {
+		return x.MemberId
+	}
+	return ""
+}
+
+func (x *CommitteeSeat) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type ProposedDefense struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ThesisId  string                 `protobuf:"bytes,1,opt,name=thesis_id,json=thesisId,proto3" json:"thesis_id,omitempty"`
+	Start     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	Location  string                 `protobuf:"bytes,4,opt,name=location,proto3" json:"location,omitempty"`
+	Building  string                 `protobuf:"bytes,5,opt,name=building,proto3" json:"building,omitempty"`
+	Committee []*CommitteeSeat       `protobuf:"bytes,6,rep,name=committee,proto3" json:"committee,omitempty"`
+	// Id của defense_schedules sau khi ghi
+	ScheduleId    string `protobuf:"bytes,7,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProposedDefense) Reset() {
+	*x = ProposedDefense{}
+	mi := &file_proto_common_common_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProposedDefense) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposedDefense) ProtoMessage() {}
+
+func (x *ProposedDefense) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposedDefense.ProtoReflect.Descriptor instead.
+func (*ProposedDefense) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ProposedDefense) GetThesisId() string {
+	if x != nil {
+		return x.ThesisId
+	}
+	return ""
+}
+
+func (x *ProposedDefense) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *ProposedDefense) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *ProposedDefense) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *ProposedDefense) GetBuilding() string {
+	if x != nil {
+		return x.Building
+	}
+	return ""
+}
+
+func (x *ProposedDefense) GetCommittee() []*CommitteeSeat {
+	if x != nil {
+		return x.Committee
+	}
+	return nil
+}
+
+func (x *ProposedDefense) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+type UnscheduledThesis struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ThesisId      string                 `protobuf:"bytes,1,opt,name=thesis_id,json=thesisId,proto3" json:"thesis_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnscheduledThesis) Reset() {
+	*x = UnscheduledThesis{}
+	mi := &file_proto_common_common_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnscheduledThesis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnscheduledThesis) ProtoMessage() {}
+
+func (x *UnscheduledThesis) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnscheduledThesis.ProtoReflect.Descriptor instead.
+func (*UnscheduledThesis) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *UnscheduledThesis) GetThesisId() string {
+	if x != nil {
+		return x.ThesisId
+	}
+	return ""
+}
+
+func (x *UnscheduledThesis) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ScheduleDefensesResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Success     bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message     string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Preview     bool                   `protobuf:"varint,3,opt,name=preview,proto3" json:"preview,omitempty"`
+	Schedules   []*ProposedDefense     `protobuf:"bytes,4,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	Unscheduled []*UnscheduledThesis   `protobuf:"bytes,5,rep,name=unscheduled,proto3" json:"unscheduled,omitempty"`
+	// false khi bộ giải dừng vì giới hạn bước, lịch vẫn hợp lệ nhưng có thể chưa tối ưu
+	Complete      bool           `protobuf:"varint,6,opt,name=complete,proto3" json:"complete,omitempty"`
+	Errors        []*ErrorDetail `protobuf:"bytes,7,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleDefensesResponse) Reset() {
+	*x = ScheduleDefensesResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleDefensesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleDefensesResponse) ProtoMessage() {}
+
+func (x *ScheduleDefensesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleDefensesResponse.ProtoReflect.Descriptor instead.
+func (*ScheduleDefensesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ScheduleDefensesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ScheduleDefensesResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ScheduleDefensesResponse) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+func (x *ScheduleDefensesResponse) GetSchedules() []*ProposedDefense {
+	if x != nil {
+		return x.Schedules
+	}
+	return nil
+}
+
+func (x *ScheduleDefensesResponse) GetUnscheduled() []*UnscheduledThesis {
+	if x != nil {
+		return x.Unscheduled
+	}
+	return nil
+}
+
+func (x *ScheduleDefensesResponse) GetComplete() bool {
+	if x != nil {
+		return x.Complete
+	}
+	return false
+}
+
+func (x *ScheduleDefensesResponse) GetErrors() []*ErrorDetail {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ComputeFinalGradeRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ThesisId string                 `protobuf:"bytes,1,opt,name=thesis_id,json=thesisId,proto3" json:"thesis_id,omitempty"`
+	// Công bố và khóa điểm sau khi tính, chỉ giáo vụ
+	Publish bool `protobuf:"varint,2,opt,name=publish,proto3" json:"publish,omitempty"`
+	// Chỉ tính, không lưu
+	Preview       bool             `protobuf:"varint,3,opt,name=preview,proto3" json:"preview,omitempty"`
+	Meta          *structpb.Struct `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputeFinalGradeRequest) Reset() {
+	*x = ComputeFinalGradeRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputeFinalGradeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeFinalGradeRequest) ProtoMessage() {}
+
+func (x *ComputeFinalGradeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeFinalGradeRequest.ProtoReflect.Descriptor instead.
+func (*ComputeFinalGradeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *ComputeFinalGradeRequest) GetThesisId() string {
+	if x != nil {
+		return x.ThesisId
+	}
+	return ""
+}
+
+func (x *ComputeFinalGradeRequest) GetPublish() bool {
+	if x != nil {
+		return x.Publish
+	}
+	return false
+}
+
+func (x *ComputeFinalGradeRequest) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+func (x *ComputeFinalGradeRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type SourceGrade struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// committee, reviewer, supervisor
+	Source        string  `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	Weight        float64 `protobuf:"fixed64,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	Average       float64 `protobuf:"fixed64,3,opt,name=average,proto3" json:"average,omitempty"`
+	Scorers       int32   `protobuf:"varint,4,opt,name=scorers,proto3" json:"scorers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SourceGrade) Reset() {
+	*x = SourceGrade{}
+	mi := &file_proto_common_common_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SourceGrade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SourceGrade) ProtoMessage() {}
+
+func (x *SourceGrade) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SourceGrade.ProtoReflect.Descriptor instead.
+func (*SourceGrade) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *SourceGrade) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *SourceGrade) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *SourceGrade) GetAverage() float64 {
+	if x != nil {
+		return x.Average
+	}
+	return 0
+}
+
+func (x *SourceGrade) GetScorers() int32 {
+	if x != nil {
+		return x.Scorers
+	}
+	return 0
+}
+
+type ScorerGrade struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ScorerId string                 `protobuf:"bytes,1,opt,name=scorer_id,json=scorerId,proto3" json:"scorer_id,omitempty"`
+	Source   string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Total    float64                `protobuf:"fixed64,3,opt,name=total,proto3" json:"total,omitempty"`
+	// Lệch so với trung vị của cùng nguồn
+	Deviation float64 `protobuf:"fixed64,4,opt,name=deviation,proto3" json:"deviation,omitempty"`
+	Outlier   bool    `protobuf:"varint,5,opt,name=outlier,proto3" json:"outlier,omitempty"`
+	// Bị loại khỏi điểm trung bình vì lệch quá ngưỡng
+	Excluded      bool               `protobuf:"varint,6,opt,name=excluded,proto3" json:"excluded,omitempty"`
+	Criteria      map[string]float64 `protobuf:"bytes,7,rep,name=criteria,proto3" json:"criteria,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScorerGrade) Reset() {
+	*x = ScorerGrade{}
+	mi := &file_proto_common_common_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScorerGrade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScorerGrade) ProtoMessage() {}
+
+func (x *ScorerGrade) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScorerGrade.ProtoReflect.Descriptor instead.
+func (*ScorerGrade) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ScorerGrade) GetScorerId() string {
+	if x != nil {
+		return x.ScorerId
+	}
+	return ""
+}
+
+func (x *ScorerGrade) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ScorerGrade) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ScorerGrade) GetDeviation() float64 {
+	if x != nil {
+		return x.Deviation
+	}
+	return 0
+}
+
+func (x *ScorerGrade) GetOutlier() bool {
+	if x != nil {
+		return x.Outlier
+	}
+	return false
+}
+
+func (x *ScorerGrade) GetExcluded() bool {
+	if x != nil {
+		return x.Excluded
+	}
+	return false
+}
+
+func (x *ScorerGrade) GetCriteria() map[string]float64 {
+	if x != nil {
+		return x.Criteria
+	}
+	return nil
+}
+
+type ComputeFinalGradeResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Success    bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message    string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Preview    bool                   `protobuf:"varint,3,opt,name=preview,proto3" json:"preview,omitempty"`
+	FinalGrade float64                `protobuf:"fixed64,4,opt,name=final_grade,json=finalGrade,proto3" json:"final_grade,omitempty"`
+	// Trước khi làm tròn
+	RawGrade float64 `protobuf:"fixed64,5,opt,name=raw_grade,json=rawGrade,proto3" json:"raw_grade,omitempty"`
+	// draft, published
+	Status        string         `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Sources       []*SourceGrade `protobuf:"bytes,7,rep,name=sources,proto3" json:"sources,omitempty"`
+	Scorers       []*ScorerGrade `protobuf:"bytes,8,rep,name=scorers,proto3" json:"scorers,omitempty"`
+	Warnings      []string       `protobuf:"bytes,9,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	Rubric        string         `protobuf:"bytes,10,opt,name=rubric,proto3" json:"rubric,omitempty"`
+	GradeId       string         `protobuf:"bytes,11,opt,name=grade_id,json=gradeId,proto3" json:"grade_id,omitempty"`
+	ComputationId string         `protobuf:"bytes,12,opt,name=computation_id,json=computationId,proto3" json:"computation_id,omitempty"`
+	Errors        []*ErrorDetail `protobuf:"bytes,13,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputeFinalGradeResponse) Reset() {
+	*x = ComputeFinalGradeResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputeFinalGradeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeFinalGradeResponse) ProtoMessage() {}
+
+func (x *ComputeFinalGradeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeFinalGradeResponse.ProtoReflect.Descriptor instead.
+func (*ComputeFinalGradeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *ComputeFinalGradeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ComputeFinalGradeResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ComputeFinalGradeResponse) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
+func (x *ComputeFinalGradeResponse) GetFinalGrade() float64 {
+	if x != nil {
+		return x.FinalGrade
+	}
+	return 0
+}
+
+func (x *ComputeFinalGradeResponse) GetRawGrade() float64 {
+	if x != nil {
+		return x.RawGrade
+	}
+	return 0
+}
+
+func (x *ComputeFinalGradeResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ComputeFinalGradeResponse) GetSources() []*SourceGrade {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *ComputeFinalGradeResponse) GetScorers() []*ScorerGrade {
+	if x != nil {
+		return x.Scorers
+	}
+	return nil
+}
+
+func (x *ComputeFinalGradeResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *ComputeFinalGradeResponse) GetRubric() string {
+	if x != nil {
+		return x.Rubric
+	}
+	return ""
+}
+
+func (x *ComputeFinalGradeResponse) GetGradeId() string {
+	if x != nil {
+		return x.GradeId
+	}
+	return ""
+}
+
+func (x *ComputeFinalGradeResponse) GetComputationId() string {
+	if x != nil {
+		return x.ComputationId
+	}
+	return ""
+}
+
+func (x *ComputeFinalGradeResponse) GetErrors() []*ErrorDetail {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type CheckSimilarityRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// submissions (mặc định) hoặc archived_theses
+	EntityType    string           `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	Id            string           `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Meta          *structpb.Struct `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckSimilarityRequest) Reset() {
+	*x = CheckSimilarityRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckSimilarityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckSimilarityRequest) ProtoMessage() {}
+
+func (x *CheckSimilarityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckSimilarityRequest.ProtoReflect.Descriptor instead.
+func (*CheckSimilarityRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *CheckSimilarityRequest) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *CheckSimilarityRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CheckSimilarityRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+type GetSimilarityReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SubmissionId  string                 `protobuf:"bytes,1,opt,name=submission_id,json=submissionId,proto3" json:"submission_id,omitempty"`
+	Meta          *structpb.Struct       `protobuf:"bytes,99,opt,name=meta,proto3" json:"meta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSimilarityReportRequest) Reset() {
+	*x = GetSimilarityReportRequest{}
+	mi := &file_proto_common_common_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSimilarityReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSimilarityReportRequest) ProtoMessage() {}
+
+func (x *GetSimilarityReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (x *AggregateResponse) GetResults() []*structpb.Struct {
+// Deprecated: Use GetSimilarityReportRequest.ProtoReflect.Descriptor instead.
+func (*GetSimilarityReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *GetSimilarityReportRequest) GetSubmissionId() string {
 	if x != nil {
-		return x.Results
+		return x.SubmissionId
+	}
+	return ""
+}
+
+func (x *GetSimilarityReportRequest) GetMeta() *structpb.Struct {
+	if x != nil {
+		return x.Meta
 	}
 	return nil
 }
 
-func (x *AggregateResponse) GetExecutionTimeMs() int64 {
+type OverlapPassage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Vị trí theo từ trong bài nộp và trong tài liệu trùng
+	Start         int32  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           int32  `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	MatchStart    int32  `protobuf:"varint,3,opt,name=match_start,json=matchStart,proto3" json:"match_start,omitempty"`
+	MatchEnd      int32  `protobuf:"varint,4,opt,name=match_end,json=matchEnd,proto3" json:"match_end,omitempty"`
+	Text          string `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OverlapPassage) Reset() {
+	*x = OverlapPassage{}
+	mi := &file_proto_common_common_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OverlapPassage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OverlapPassage) ProtoMessage() {}
+
+func (x *OverlapPassage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[68]
 	if x != nil {
-		return x.ExecutionTimeMs
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OverlapPassage.ProtoReflect.Descriptor instead.
+func (*OverlapPassage) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *OverlapPassage) GetStart() int32 {
+	if x != nil {
+		return x.Start
 	}
 	return 0
 }
 
-type ErrorDetail struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
-	Field         string                 `protobuf:"bytes,2,opt,name=field,proto3" json:"field,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+func (x *OverlapPassage) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *OverlapPassage) GetMatchStart() int32 {
+	if x != nil {
+		return x.MatchStart
+	}
+	return 0
+}
+
+func (x *OverlapPassage) GetMatchEnd() int32 {
+	if x != nil {
+		return x.MatchEnd
+	}
+	return 0
+}
+
+func (x *OverlapPassage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type SimilarityMatch struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// submissions hoặc archived_theses
+	Source     string  `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	SourceId   string  `protobuf:"bytes,2,opt,name=source_id,json=sourceId,proto3" json:"source_id,omitempty"`
+	ThesisId   string  `protobuf:"bytes,3,opt,name=thesis_id,json=thesisId,proto3" json:"thesis_id,omitempty"`
+	Title      string  `protobuf:"bytes,4,opt,name=title,proto3" json:"title,omitempty"`
+	Similarity float64 `protobuf:"fixed64,5,opt,name=similarity,proto3" json:"similarity,omitempty"`
+	// Tỉ lệ bài nộp xuất hiện trong tài liệu này
+	Containment   float64           `protobuf:"fixed64,6,opt,name=containment,proto3" json:"containment,omitempty"`
+	Passages      []*OverlapPassage `protobuf:"bytes,7,rep,name=passages,proto3" json:"passages,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ErrorDetail) Reset() {
-	*x = ErrorDetail{}
-	mi := &file_proto_common_common_proto_msgTypes[15]
+func (x *SimilarityMatch) Reset() {
+	*x = SimilarityMatch{}
+	mi := &file_proto_common_common_proto_msgTypes[69]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ErrorDetail) String() string {
+func (x *SimilarityMatch) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ErrorDetail) ProtoMessage() {}
+func (*SimilarityMatch) ProtoMessage() {}
 
-func (x *ErrorDetail) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_common_common_proto_msgTypes[15]
+func (x *SimilarityMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[69]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1146,32 +5257,229 @@ func (x *ErrorDetail) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ErrorDetail.ProtoReflect.Descriptor instead.
-func (*ErrorDetail) Descriptor() ([]byte, []int) {
-	return file_proto_common_common_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use SimilarityMatch.ProtoReflect.Descriptor instead.
+func (*SimilarityMatch) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{69}
 }
 
-func (x *ErrorDetail) GetCode() string {
+func (x *SimilarityMatch) GetSource() string {
 	if x != nil {
-		return x.Code
+		return x.Source
 	}
 	return ""
 }
 
-func (x *ErrorDetail) GetField() string {
+func (x *SimilarityMatch) GetSourceId() string {
 	if x != nil {
-		return x.Field
+		return x.SourceId
 	}
 	return ""
 }
 
-func (x *ErrorDetail) GetMessage() string {
+func (x *SimilarityMatch) GetThesisId() string {
+	if x != nil {
+		return x.ThesisId
+	}
+	return ""
+}
+
+func (x *SimilarityMatch) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SimilarityMatch) GetSimilarity() float64 {
+	if x != nil {
+		return x.Similarity
+	}
+	return 0
+}
+
+func (x *SimilarityMatch) GetContainment() float64 {
+	if x != nil {
+		return x.Containment
+	}
+	return 0
+}
+
+func (x *SimilarityMatch) GetPassages() []*OverlapPassage {
+	if x != nil {
+		return x.Passages
+	}
+	return nil
+}
+
+type SimilarityReport struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	SubmissionId string                 `protobuf:"bytes,1,opt,name=submission_id,json=submissionId,proto3" json:"submission_id,omitempty"`
+	ThesisId     string                 `protobuf:"bytes,2,opt,name=thesis_id,json=thesisId,proto3" json:"thesis_id,omitempty"`
+	// pending, completed, no_text, failed
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Score         float64                `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`
+	Flagged       bool                   `protobuf:"varint,5,opt,name=flagged,proto3" json:"flagged,omitempty"`
+	Words         int32                  `protobuf:"varint,6,opt,name=words,proto3" json:"words,omitempty"`
+	Matches       []*SimilarityMatch     `protobuf:"bytes,7,rep,name=matches,proto3" json:"matches,omitempty"`
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	CheckedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimilarityReport) Reset() {
+	*x = SimilarityReport{}
+	mi := &file_proto_common_common_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimilarityReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarityReport) ProtoMessage() {}
+
+func (x *SimilarityReport) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarityReport.ProtoReflect.Descriptor instead.
+func (*SimilarityReport) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *SimilarityReport) GetSubmissionId() string {
+	if x != nil {
+		return x.SubmissionId
+	}
+	return ""
+}
+
+func (x *SimilarityReport) GetThesisId() string {
+	if x != nil {
+		return x.ThesisId
+	}
+	return ""
+}
+
+func (x *SimilarityReport) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SimilarityReport) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SimilarityReport) GetFlagged() bool {
+	if x != nil {
+		return x.Flagged
+	}
+	return false
+}
+
+func (x *SimilarityReport) GetWords() int32 {
+	if x != nil {
+		return x.Words
+	}
+	return 0
+}
+
+func (x *SimilarityReport) GetMatches() []*SimilarityMatch {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+func (x *SimilarityReport) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SimilarityReport) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+type SimilarityReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Report        *SimilarityReport      `protobuf:"bytes,3,opt,name=report,proto3" json:"report,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SimilarityReportResponse) Reset() {
+	*x = SimilarityReportResponse{}
+	mi := &file_proto_common_common_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SimilarityReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SimilarityReportResponse) ProtoMessage() {}
+
+func (x *SimilarityReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_common_common_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SimilarityReportResponse.ProtoReflect.Descriptor instead.
+func (*SimilarityReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_common_common_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *SimilarityReportResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SimilarityReportResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
+func (x *SimilarityReportResponse) GetReport() *SimilarityReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
 var File_proto_common_common_proto protoreflect.FileDescriptor
 
 const file_proto_common_common_proto_rawDesc = "" +
@@ -1181,13 +5489,14 @@ const file_proto_common_common_proto_rawDesc = "" +
 	"\ventity_type\x18\x01 \x01(\tR\n" +
 	"entityType\x12+\n" +
 	"\x04data\x18\x02 \x01(\v2\x17.google.protobuf.StructR\x04data\x12+\n" +
-	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\xde\x01\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\x8b\x02\n" +
 	"\x0fGenericResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12,\n" +
 	"\x02id\x18\x03 \x01(\v2\x1c.google.protobuf.StringValueR\x02id\x12/\n" +
 	"\x06entity\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x06entity\x128\n" +
-	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xab\x01\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12+\n" +
+	"\x06errors\x18\x06 \x03(\v2\x13.common.ErrorDetailR\x06errors\"\xab\x01\n" +
 	"\fBatchRequest\x12\x1f\n" +
 	"\ventity_type\x18\x01 \x01(\tR\n" +
 	"entityType\x123\n" +
@@ -1206,7 +5515,7 @@ const file_proto_common_common_proto_rawDesc = "" +
 	"entityType\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\tR\x02id\x12\x16\n" +
 	"\x06fields\x18\x03 \x03(\tR\x06fields\x12+\n" +
-	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\xa6\x03\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\x90\x04\n" +
 	"\fQueryRequest\x12\x1f\n" +
 	"\ventity_type\x18\x01 \x01(\tR\n" +
 	"entityType\x12\x12\n" +
@@ -1216,11 +5525,16 @@ const file_proto_common_common_proto_rawDesc = "" +
 	"\rsearch_fields\x18\x05 \x03(\tR\fsearchFields\x12;\n" +
 	"\afilters\x18\x06 \x03(\v2!.common.QueryRequest.FiltersEntryR\afilters\x12\x16\n" +
 	"\x06fields\x18\a \x03(\tR\x06fields\x123\n" +
-	"\bpipeline\x18\b \x03(\v2\x17.google.protobuf.StructR\bpipeline\x12+\n" +
+	"\bpipeline\x18\b \x03(\v2\x17.google.protobuf.StructR\bpipeline\x12\x1f\n" +
+	"\vsearch_mode\x18\t \x01(\tR\n" +
+	"searchMode\x12)\n" +
+	"\x10accent_sensitive\x18\n" +
+	" \x01(\bR\x0faccentSensitive\x12\x1c\n" +
+	"\thighlight\x18\v \x01(\bR\thighlight\x12+\n" +
 	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\x1aR\n" +
 	"\fFiltersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
-	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value:\x028\x01\"\xd8\x01\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x05value:\x028\x01\"\xa0\x02\n" +
 	"\rQueryResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x123\n" +
@@ -1228,7 +5542,21 @@ const file_proto_common_common_proto_rawDesc = "" +
 	"\n" +
 	"pagination\x18\x04 \x01(\v2\x12.common.PaginationR\n" +
 	"pagination\x12*\n" +
-	"\x11execution_time_ms\x18\x05 \x01(\x03R\x0fexecutionTimeMs\"\x8e\x01\n" +
+	"\x11execution_time_ms\x18\x05 \x01(\x03R\x0fexecutionTimeMs\x12%\n" +
+	"\x04hits\x18\x06 \x03(\v2\x11.common.SearchHitR\x04hits\x12\x1f\n" +
+	"\vsearch_mode\x18\a \x01(\tR\n" +
+	"searchMode\"\x85\x01\n" +
+	"\tSearchHit\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x121\n" +
+	"\n" +
+	"highlights\x18\x03 \x03(\v2\x11.common.HighlightR\n" +
+	"highlights\x12\x1f\n" +
+	"\ventity_type\x18\x04 \x01(\tR\n" +
+	"entityType\"=\n" +
+	"\tHighlight\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x1a\n" +
+	"\bsnippets\x18\x02 \x03(\tR\bsnippets\"\x8e\x01\n" +
 	"\n" +
 	"Pagination\x12!\n" +
 	"\fcurrent_page\x18\x01 \x01(\x05R\vcurrentPage\x12\x1b\n" +
@@ -1243,28 +5571,74 @@ const file_proto_common_common_proto_rawDesc = "" +
 	"\x02id\x18\x02 \x01(\tR\x02id\x12+\n" +
 	"\x04data\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x04data\x12%\n" +
 	"\x0epartial_update\x18\x04 \x01(\bR\rpartialUpdate\x12+\n" +
-	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"m\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\x86\x01\n" +
 	"\rDeleteRequest\x12\x1f\n" +
 	"\ventity_type\x18\x01 \x01(\tR\n" +
 	"entityType\x12\x0e\n" +
-	"\x02id\x18\x02 \x01(\tR\x02id\x12+\n" +
-	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"i\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\xa2\x01\n" +
 	"\x0eDeleteResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12#\n" +
-	"\rdeleted_count\x18\x03 \x01(\x05R\fdeletedCount\"\xa8\x01\n" +
+	"\rdeleted_count\x18\x03 \x01(\x05R\fdeletedCount\x127\n" +
+	"\n" +
+	"dependents\x18\x04 \x03(\v2\x17.common.DependentEffectR\n" +
+	"dependents\"\xc1\x01\n" +
 	"\x11DeleteManyRequest\x12\x1f\n" +
 	"\ventity_type\x18\x01 \x01(\tR\n" +
 	"entityType\x12\x10\n" +
 	"\x03ids\x18\x02 \x03(\tR\x03ids\x123\n" +
-	"\bpipeline\x18\x03 \x03(\v2\x17.google.protobuf.StructR\bpipeline\x12+\n" +
-	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\x8c\x01\n" +
+	"\bpipeline\x18\x03 \x03(\v2\x17.google.protobuf.StructR\bpipeline\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\xe6\x01\n" +
 	"\x12DeleteManyResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12#\n" +
 	"\rdeleted_count\x18\x03 \x01(\x05R\fdeletedCount\x12\x1d\n" +
 	"\n" +
-	"failed_ids\x18\x04 \x03(\tR\tfailedIds\"\xdb\x01\n" +
+	"failed_ids\x18\x04 \x03(\tR\tfailedIds\x12\x1f\n" +
+	"\vdeleted_ids\x18\x05 \x03(\tR\n" +
+	"deletedIds\x127\n" +
+	"\n" +
+	"dependents\x18\x06 \x03(\v2\x17.common.DependentEffectR\n" +
+	"dependents\"\x88\x01\n" +
+	"\x0fDependentEffect\x12\x1f\n" +
+	"\ventity_type\x18\x01 \x01(\tR\n" +
+	"entityType\x12\x14\n" +
+	"\x05field\x18\x02 \x01(\tR\x05field\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x05R\x05count\x12\x10\n" +
+	"\x03ids\x18\x05 \x03(\tR\x03ids\"\x91\x03\n" +
+	"\rSearchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12!\n" +
+	"\fentity_types\x18\x02 \x03(\tR\ventityTypes\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x129\n" +
+	"\tfuzziness\x18\x05 \x01(\v2\x1b.google.protobuf.Int32ValueR\tfuzziness\x12\x16\n" +
+	"\x06facets\x18\x06 \x03(\tR\x06facets\x12<\n" +
+	"\afilters\x18\a \x03(\v2\".common.SearchRequest.FiltersEntryR\afilters\x12\x1c\n" +
+	"\thighlight\x18\b \x01(\bR\thighlight\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\x1a:\n" +
+	"\fFiltersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa7\x02\n" +
+	"\x0eSearchResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12%\n" +
+	"\x04hits\x18\x03 \x03(\v2\x11.common.SearchHitR\x04hits\x123\n" +
+	"\bentities\x18\x04 \x03(\v2\x17.google.protobuf.StructR\bentities\x122\n" +
+	"\n" +
+	"pagination\x18\x05 \x01(\v2\x12.common.PaginationR\n" +
+	"pagination\x12%\n" +
+	"\x06facets\x18\x06 \x03(\v2\r.common.FacetR\x06facets\x12*\n" +
+	"\x11execution_time_ms\x18\a \x01(\x03R\x0fexecutionTimeMs\"D\n" +
+	"\x05Facet\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12'\n" +
+	"\x05terms\x18\x02 \x03(\v2\x11.common.FacetTermR\x05terms\"5\n" +
+	"\tFacetTerm\x12\x12\n" +
+	"\x04term\x18\x01 \x01(\tR\x04term\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xdb\x01\n" +
 	"\x10AggregateRequest\x12\x1f\n" +
 	"\ventity_type\x18\x01 \x01(\tR\n" +
 	"entityType\x123\n" +
@@ -1276,11 +5650,330 @@ const file_proto_common_common_proto_rawDesc = "" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x121\n" +
 	"\aresults\x18\x03 \x03(\v2\x17.google.protobuf.StructR\aresults\x12*\n" +
-	"\x11execution_time_ms\x18\x04 \x01(\x03R\x0fexecutionTimeMs\"Q\n" +
+	"\x11execution_time_ms\x18\x04 \x01(\x03R\x0fexecutionTimeMs\"\x9e\x01\n" +
 	"\vErrorDetail\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\tR\x04code\x12\x14\n" +
 	"\x05field\x18\x02 \x01(\tR\x05field\x12\x18\n" +
-	"\amessage\x18\x03 \x01(\tR\amessage2\xf1\x03\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\tR\x05value\x12\x1f\n" +
+	"\vexisting_id\x18\x05 \x01(\tR\n" +
+	"existingId\x12\x14\n" +
+	"\x05index\x18\x06 \x01(\x05R\x05index\"\xbd\x01\n" +
+	"\fFileMetadata\x12\x1b\n" +
+	"\tfile_name\x18\x01 \x01(\tR\bfileName\x12\x1b\n" +
+	"\tmime_type\x18\x02 \x01(\tR\bmimeType\x12\x19\n" +
+	"\bowner_id\x18\x03 \x01(\tR\aownerId\x12\x1f\n" +
+	"\ventity_type\x18\x04 \x01(\tR\n" +
+	"entityType\x12\x1b\n" +
+	"\tentity_id\x18\x05 \x01(\tR\bentityId\x12\x1a\n" +
+	"\bchecksum\x18\x06 \x01(\tR\bchecksum\"\xd0\x02\n" +
+	"\bFileInfo\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x1b\n" +
+	"\tmime_type\x18\x03 \x01(\tR\bmimeType\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x12\x1a\n" +
+	"\bchecksum\x18\x05 \x01(\tR\bchecksum\x12\x19\n" +
+	"\bowner_id\x18\x06 \x01(\tR\aownerId\x12\x1f\n" +
+	"\ventity_type\x18\a \x01(\tR\n" +
+	"entityType\x12\x1b\n" +
+	"\tentity_id\x18\b \x01(\tR\bentityId\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x18\n" +
+	"\astorage\x18\n" +
+	" \x01(\tR\astorage\x12\x1c\n" +
+	"\tintegrity\x18\v \x01(\tR\tintegrity\"c\n" +
+	"\rUploadRequest\x122\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x14.common.FileMetadataH\x00R\bmetadata\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04data\"j\n" +
+	"\x0eUploadResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12$\n" +
+	"\x04file\x18\x03 \x01(\v2\x10.common.FileInfoR\x04file\"N\n" +
+	"\x0fDownloadRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"Z\n" +
+	"\x10DownloadResponse\x12&\n" +
+	"\x04info\x18\x01 \x01(\v2\x10.common.FileInfoH\x00R\x04info\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04data\"M\n" +
+	"\x0eGetFileRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"h\n" +
+	"\fFileResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12$\n" +
+	"\x04file\x18\x03 \x01(\v2\x10.common.FileInfoR\x04file\"}\n" +
+	"\x10ListFilesRequest\x12\x1f\n" +
+	"\ventity_type\x18\x01 \x01(\tR\n" +
+	"entityType\x12\x1b\n" +
+	"\tentity_id\x18\x02 \x01(\tR\bentityId\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"o\n" +
+	"\x11ListFilesResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12&\n" +
+	"\x05files\x18\x03 \x03(\v2\x10.common.FileInfoR\x05files\"E\n" +
+	"\x12DownloadURLRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x05R\n" +
+	"ttlSeconds\"\x96\x01\n" +
+	"\x13DownloadURLResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"l\n" +
+	"\x13NextSequenceRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"b\n" +
+	"\x14NextSequenceResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
+	"\x06values\x18\x03 \x03(\x03R\x06values\"\xce\x01\n" +
+	"\vVersionInfo\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\x12\x1c\n" +
+	"\toperation\x18\x02 \x01(\tR\toperation\x12+\n" +
+	"\x04meta\x18\x03 \x01(\v2\x17.google.protobuf.StructR\x04meta\x12\x1f\n" +
+	"\vreverted_to\x18\x04 \x01(\x03R\n" +
+	"revertedTo\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xb1\x01\n" +
+	"\x13ListVersionsRequest\x12\x1f\n" +
+	"\ventity_type\x18\x01 \x01(\tR\n" +
+	"entityType\x12\x1b\n" +
+	"\tentity_id\x18\x02 \x01(\tR\bentityId\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\x91\x01\n" +
+	"\x14ListVersionsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12/\n" +
+	"\bversions\x18\x03 \x03(\v2\x13.common.VersionInfoR\bversions\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x03R\x05total\"\xb0\x01\n" +
+	"\x11GetVersionRequest\x12\x1f\n" +
+	"\ventity_type\x18\x01 \x01(\tR\n" +
+	"entityType\x12\x1b\n" +
+	"\tentity_id\x18\x02 \x01(\tR\bentityId\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\x12\x16\n" +
+	"\x06fields\x18\x04 \x03(\tR\x06fields\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\xa5\x01\n" +
+	"\x0fVersionResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
+	"\aversion\x18\x03 \x01(\v2\x13.common.VersionInfoR\aversion\x12/\n" +
+	"\x06entity\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x06entity\"\xc2\x01\n" +
+	"\x13DiffVersionsRequest\x12\x1f\n" +
+	"\ventity_type\x18\x01 \x01(\tR\n" +
+	"entityType\x12\x1b\n" +
+	"\tentity_id\x18\x02 \x01(\tR\bentityId\x12!\n" +
+	"\ffrom_version\x18\x03 \x01(\x03R\vfromVersion\x12\x1d\n" +
+	"\n" +
+	"to_version\x18\x04 \x01(\x03R\ttoVersion\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\x89\x01\n" +
+	"\vFieldChange\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12*\n" +
+	"\x04from\x18\x03 \x01(\v2\x16.google.protobuf.ValueR\x04from\x12&\n" +
+	"\x02to\x18\x04 \x01(\v2\x16.google.protobuf.ValueR\x02to\"y\n" +
+	"\x14DiffVersionsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
+	"\achanges\x18\x03 \x03(\v2\x13.common.FieldChangeR\achanges\"\x9d\x01\n" +
+	"\x16RevertToVersionRequest\x12\x1f\n" +
+	"\ventity_type\x18\x01 \x01(\tR\n" +
+	"entityType\x12\x1b\n" +
+	"\tentity_id\x18\x02 \x01(\tR\bentityId\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\x96\x01\n" +
+	"\x17TransitionThesisRequest\x12\x1b\n" +
+	"\tthesis_id\x18\x01 \x01(\tR\bthesisId\x12\x19\n" +
+	"\bto_state\x18\x02 \x01(\tR\atoState\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\x8d\x02\n" +
+	"\x18TransitionThesisResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"from_state\x18\x03 \x01(\tR\tfromState\x12\x19\n" +
+	"\bto_state\x18\x04 \x01(\tR\atoState\x12/\n" +
+	"\x06entity\x18\x05 \x01(\v2\x17.google.protobuf.StructR\x06entity\x12+\n" +
+	"\x06errors\x18\x06 \x03(\v2\x13.common.ErrorDetailR\x06errors\x12%\n" +
+	"\x0eallowed_states\x18\a \x03(\tR\rallowedStates\"q\n" +
+	"\x12SupervisorCapacity\x12#\n" +
+	"\rsupervisor_id\x18\x01 \x01(\tR\fsupervisorId\x12\x1f\n" +
+	"\vmax_primary\x18\x02 \x01(\x05R\n" +
+	"maxPrimary\x12\x15\n" +
+	"\x06max_co\x18\x03 \x01(\x05R\x05maxCo\"\xb5\x03\n" +
+	"\x18AssignSupervisorsRequest\x12#\n" +
+	"\racademic_year\x18\x01 \x01(\tR\facademicYear\x12\x1a\n" +
+	"\bsemester\x18\x02 \x01(\x05R\bsemester\x12\x1d\n" +
+	"\n" +
+	"thesis_ids\x18\x03 \x03(\tR\tthesisIds\x12%\n" +
+	"\x0esupervisor_ids\x18\x04 \x03(\tR\rsupervisorIds\x12:\n" +
+	"\n" +
+	"capacities\x18\x05 \x03(\v2\x1a.common.SupervisorCapacityR\n" +
+	"capacities\x12)\n" +
+	"\x10default_capacity\x18\x06 \x01(\x05R\x0fdefaultCapacity\x12.\n" +
+	"\x13with_co_supervisors\x18\a \x01(\bR\x11withCoSupervisors\x124\n" +
+	"\x16allow_cross_department\x18\b \x01(\bR\x14allowCrossDepartment\x12\x18\n" +
+	"\apreview\x18\t \x01(\bR\apreview\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\xbf\x01\n" +
+	"\x12ProposedAssignment\x12\x1b\n" +
+	"\tthesis_id\x18\x01 \x01(\tR\bthesisId\x12#\n" +
+	"\rsupervisor_id\x18\x02 \x01(\tR\fsupervisorId\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\x12\x18\n" +
+	"\areasons\x18\x05 \x03(\tR\areasons\x12#\n" +
+	"\rassignment_id\x18\x06 \x01(\tR\fassignmentId\"[\n" +
+	"\x10UnassignedThesis\x12\x1b\n" +
+	"\tthesis_id\x18\x01 \x01(\tR\bthesisId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x97\x01\n" +
+	"\x0eSupervisorLoad\x12#\n" +
+	"\rsupervisor_id\x18\x01 \x01(\tR\fsupervisorId\x12\x18\n" +
+	"\aprimary\x18\x02 \x01(\x05R\aprimary\x12\x0e\n" +
+	"\x02co\x18\x03 \x01(\x05R\x02co\x12\x1f\n" +
+	"\vmax_primary\x18\x04 \x01(\x05R\n" +
+	"maxPrimary\x12\x15\n" +
+	"\x06max_co\x18\x05 \x01(\x05R\x05maxCo\"\xd3\x02\n" +
+	"\x19AssignSupervisorsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x18\n" +
+	"\apreview\x18\x03 \x01(\bR\apreview\x12\x15\n" +
+	"\x06run_id\x18\x04 \x01(\tR\x05runId\x12<\n" +
+	"\vassignments\x18\x05 \x03(\v2\x1a.common.ProposedAssignmentR\vassignments\x128\n" +
+	"\n" +
+	"unassigned\x18\x06 \x03(\v2\x18.common.UnassignedThesisR\n" +
+	"unassigned\x12,\n" +
+	"\x05loads\x18\a \x03(\v2\x16.common.SupervisorLoadR\x05loads\x12+\n" +
+	"\x06errors\x18\b \x03(\v2\x13.common.ErrorDetailR\x06errors\"l\n" +
+	"\n" +
+	"TimeWindow\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\"E\n" +
+	"\vDefenseRoom\x12\x1a\n" +
+	"\blocation\x18\x01 \x01(\tR\blocation\x12\x1a\n" +
+	"\bbuilding\x18\x02 \x01(\tR\bbuilding\"g\n" +
+	"\x12MemberAvailability\x12\x1b\n" +
+	"\tmember_id\x18\x01 \x01(\tR\bmemberId\x124\n" +
+	"\vunavailable\x18\x02 \x03(\v2\x12.common.TimeWindowR\vunavailable\"\xfa\x03\n" +
+	"\x17ScheduleDefensesRequest\x12\x1d\n" +
+	"\n" +
+	"thesis_ids\x18\x01 \x03(\tR\tthesisIds\x12#\n" +
+	"\racademic_year\x18\x02 \x01(\tR\facademicYear\x12\x1a\n" +
+	"\bsemester\x18\x03 \x01(\x05R\bsemester\x12,\n" +
+	"\awindows\x18\x04 \x03(\v2\x12.common.TimeWindowR\awindows\x12!\n" +
+	"\fslot_minutes\x18\x05 \x01(\x05R\vslotMinutes\x12)\n" +
+	"\x05rooms\x18\x06 \x03(\v2\x13.common.DefenseRoomR\x05rooms\x12\x1d\n" +
+	"\n" +
+	"member_ids\x18\a \x03(\tR\tmemberIds\x12>\n" +
+	"\favailability\x18\b \x03(\v2\x1a.common.MemberAvailabilityR\favailability\x12%\n" +
+	"\x0ecommittee_size\x18\t \x01(\x05R\rcommitteeSize\x126\n" +
+	"\x17supervisor_in_committee\x18\n" +
+	" \x01(\bR\x15supervisorInCommittee\x12\x18\n" +
+	"\apreview\x18\v \x01(\bR\apreview\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"@\n" +
+	"\rCommitteeSeat\x12\x1b\n" +
+	"\tmember_id\x18\x01 \x01(\tR\bmemberId\x12\x12\n" +
+	"\x04role\x18\x02 \x01(\tR\x04role\"\x9c\x02\n" +
+	"\x0fProposedDefense\x12\x1b\n" +
+	"\tthesis_id\x18\x01 \x01(\tR\bthesisId\x120\n" +
+	"\x05start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x12,\n" +
+	"\x03end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x03end\x12\x1a\n" +
+	"\blocation\x18\x04 \x01(\tR\blocation\x12\x1a\n" +
+	"\bbuilding\x18\x05 \x01(\tR\bbuilding\x123\n" +
+	"\tcommittee\x18\x06 \x03(\v2\x15.common.CommitteeSeatR\tcommittee\x12\x1f\n" +
+	"\vschedule_id\x18\a \x01(\tR\n" +
+	"scheduleId\"H\n" +
+	"\x11UnscheduledThesis\x12\x1b\n" +
+	"\tthesis_id\x18\x01 \x01(\tR\bthesisId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xa5\x02\n" +
+	"\x18ScheduleDefensesResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x18\n" +
+	"\apreview\x18\x03 \x01(\bR\apreview\x125\n" +
+	"\tschedules\x18\x04 \x03(\v2\x17.common.ProposedDefenseR\tschedules\x12;\n" +
+	"\vunscheduled\x18\x05 \x03(\v2\x19.common.UnscheduledThesisR\vunscheduled\x12\x1a\n" +
+	"\bcomplete\x18\x06 \x01(\bR\bcomplete\x12+\n" +
+	"\x06errors\x18\a \x03(\v2\x13.common.ErrorDetailR\x06errors\"\x98\x01\n" +
+	"\x18ComputeFinalGradeRequest\x12\x1b\n" +
+	"\tthesis_id\x18\x01 \x01(\tR\bthesisId\x12\x18\n" +
+	"\apublish\x18\x02 \x01(\bR\apublish\x12\x18\n" +
+	"\apreview\x18\x03 \x01(\bR\apreview\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"q\n" +
+	"\vSourceGrade\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x16\n" +
+	"\x06weight\x18\x02 \x01(\x01R\x06weight\x12\x18\n" +
+	"\aaverage\x18\x03 \x01(\x01R\aaverage\x12\x18\n" +
+	"\ascorers\x18\x04 \x01(\x05R\ascorers\"\xa8\x02\n" +
+	"\vScorerGrade\x12\x1b\n" +
+	"\tscorer_id\x18\x01 \x01(\tR\bscorerId\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x01R\x05total\x12\x1c\n" +
+	"\tdeviation\x18\x04 \x01(\x01R\tdeviation\x12\x18\n" +
+	"\aoutlier\x18\x05 \x01(\bR\aoutlier\x12\x1a\n" +
+	"\bexcluded\x18\x06 \x01(\bR\bexcluded\x12=\n" +
+	"\bcriteria\x18\a \x03(\v2!.common.ScorerGrade.CriteriaEntryR\bcriteria\x1a;\n" +
+	"\rCriteriaEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\xc0\x03\n" +
+	"\x19ComputeFinalGradeResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x18\n" +
+	"\apreview\x18\x03 \x01(\bR\apreview\x12\x1f\n" +
+	"\vfinal_grade\x18\x04 \x01(\x01R\n" +
+	"finalGrade\x12\x1b\n" +
+	"\traw_grade\x18\x05 \x01(\x01R\brawGrade\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12-\n" +
+	"\asources\x18\a \x03(\v2\x13.common.SourceGradeR\asources\x12-\n" +
+	"\ascorers\x18\b \x03(\v2\x13.common.ScorerGradeR\ascorers\x12\x1a\n" +
+	"\bwarnings\x18\t \x03(\tR\bwarnings\x12\x16\n" +
+	"\x06rubric\x18\n" +
+	" \x01(\tR\x06rubric\x12\x19\n" +
+	"\bgrade_id\x18\v \x01(\tR\agradeId\x12%\n" +
+	"\x0ecomputation_id\x18\f \x01(\tR\rcomputationId\x12+\n" +
+	"\x06errors\x18\r \x03(\v2\x13.common.ErrorDetailR\x06errors\"v\n" +
+	"\x16CheckSimilarityRequest\x12\x1f\n" +
+	"\ventity_type\x18\x01 \x01(\tR\n" +
+	"entityType\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"n\n" +
+	"\x1aGetSimilarityReportRequest\x12#\n" +
+	"\rsubmission_id\x18\x01 \x01(\tR\fsubmissionId\x12+\n" +
+	"\x04meta\x18c \x01(\v2\x17.google.protobuf.StructR\x04meta\"\x8a\x01\n" +
+	"\x0eOverlapPassage\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\x05R\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\x05R\x03end\x12\x1f\n" +
+	"\vmatch_start\x18\x03 \x01(\x05R\n" +
+	"matchStart\x12\x1b\n" +
+	"\tmatch_end\x18\x04 \x01(\x05R\bmatchEnd\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\"\xef\x01\n" +
+	"\x0fSimilarityMatch\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x1b\n" +
+	"\tsource_id\x18\x02 \x01(\tR\bsourceId\x12\x1b\n" +
+	"\tthesis_id\x18\x03 \x01(\tR\bthesisId\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x1e\n" +
+	"\n" +
+	"similarity\x18\x05 \x01(\x01R\n" +
+	"similarity\x12 \n" +
+	"\vcontainment\x18\x06 \x01(\x01R\vcontainment\x122\n" +
+	"\bpassages\x18\a \x03(\v2\x16.common.OverlapPassageR\bpassages\"\xb6\x02\n" +
+	"\x10SimilarityReport\x12#\n" +
+	"\rsubmission_id\x18\x01 \x01(\tR\fsubmissionId\x12\x1b\n" +
+	"\tthesis_id\x18\x02 \x01(\tR\bthesisId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\x12\x18\n" +
+	"\aflagged\x18\x05 \x01(\bR\aflagged\x12\x14\n" +
+	"\x05words\x18\x06 \x01(\x05R\x05words\x121\n" +
+	"\amatches\x18\a \x03(\v2\x17.common.SimilarityMatchR\amatches\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"checked_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\"\x80\x01\n" +
+	"\x18SimilarityReportResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x120\n" +
+	"\x06report\x18\x03 \x01(\v2\x18.common.SimilarityReportR\x06report2\xad\v\n" +
 	"\rCommonService\x129\n" +
 	"\x06Create\x12\x16.common.GenericRequest\x1a\x17.common.GenericResponse\x129\n" +
 	"\n" +
@@ -1291,7 +5984,28 @@ const file_proto_common_common_proto_rawDesc = "" +
 	"\x06Delete\x12\x15.common.DeleteRequest\x1a\x16.common.DeleteResponse\x12C\n" +
 	"\n" +
 	"DeleteMany\x12\x19.common.DeleteManyRequest\x1a\x1a.common.DeleteManyResponse\x12@\n" +
-	"\tAggregate\x12\x18.common.AggregateRequest\x1a\x19.common.AggregateResponseB\x13Z\x11github.com/commonb\x06proto3"
+	"\tAggregate\x12\x18.common.AggregateRequest\x1a\x19.common.AggregateResponse\x127\n" +
+	"\x06Search\x12\x15.common.SearchRequest\x1a\x16.common.SearchResponse\x12I\n" +
+	"\fNextSequence\x12\x1b.common.NextSequenceRequest\x1a\x1c.common.NextSequenceResponse\x12I\n" +
+	"\fListVersions\x12\x1b.common.ListVersionsRequest\x1a\x1c.common.ListVersionsResponse\x12@\n" +
+	"\n" +
+	"GetVersion\x12\x19.common.GetVersionRequest\x1a\x17.common.VersionResponse\x12I\n" +
+	"\fDiffVersions\x12\x1b.common.DiffVersionsRequest\x1a\x1c.common.DiffVersionsResponse\x12J\n" +
+	"\x0fRevertToVersion\x12\x1e.common.RevertToVersionRequest\x1a\x17.common.GenericResponse\x12U\n" +
+	"\x10TransitionThesis\x12\x1f.common.TransitionThesisRequest\x1a .common.TransitionThesisResponse\x12X\n" +
+	"\x11AssignSupervisors\x12 .common.AssignSupervisorsRequest\x1a!.common.AssignSupervisorsResponse\x12U\n" +
+	"\x10ScheduleDefenses\x12\x1f.common.ScheduleDefensesRequest\x1a .common.ScheduleDefensesResponse\x12X\n" +
+	"\x11ComputeFinalGrade\x12 .common.ComputeFinalGradeRequest\x1a!.common.ComputeFinalGradeResponse\x12S\n" +
+	"\x0fCheckSimilarity\x12\x1e.common.CheckSimilarityRequest\x1a .common.SimilarityReportResponse\x12[\n" +
+	"\x13GetSimilarityReport\x12\".common.GetSimilarityReportRequest\x1a .common.SimilarityReportResponse2\x8d\x03\n" +
+	"\vFileService\x129\n" +
+	"\x06Upload\x12\x15.common.UploadRequest\x1a\x16.common.UploadResponse(\x01\x12?\n" +
+	"\bDownload\x12\x17.common.DownloadRequest\x1a\x18.common.DownloadResponse0\x01\x127\n" +
+	"\aGetFile\x12\x16.common.GetFileRequest\x1a\x14.common.FileResponse\x12@\n" +
+	"\tListFiles\x12\x18.common.ListFilesRequest\x1a\x19.common.ListFilesResponse\x12<\n" +
+	"\n" +
+	"DeleteFile\x12\x16.common.GetFileRequest\x1a\x16.common.DeleteResponse\x12I\n" +
+	"\x0eGetDownloadURL\x12\x1a.common.DownloadURLRequest\x1a\x1b.common.DownloadURLResponseB\x13Z\x11github.com/commonb\x06proto3"
 
 var (
 	file_proto_common_common_proto_rawDescOnce sync.Once
@@ -1305,76 +6019,240 @@ func file_proto_common_common_proto_rawDescGZIP() []byte {
 	return file_proto_common_common_proto_rawDescData
 }
 
-var file_proto_common_common_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_proto_common_common_proto_msgTypes = make([]protoimpl.MessageInfo, 75)
 var file_proto_common_common_proto_goTypes = []any{
-	(*GenericRequest)(nil),         // 0: common.GenericRequest
-	(*GenericResponse)(nil),        // 1: common.GenericResponse
-	(*BatchRequest)(nil),           // 2: common.BatchRequest
-	(*BatchResponse)(nil),          // 3: common.BatchResponse
-	(*GetByIdRequest)(nil),         // 4: common.GetByIdRequest
-	(*QueryRequest)(nil),           // 5: common.QueryRequest
-	(*QueryResponse)(nil),          // 6: common.QueryResponse
-	(*Pagination)(nil),             // 7: common.Pagination
-	(*UpdateRequest)(nil),          // 8: common.UpdateRequest
-	(*DeleteRequest)(nil),          // 9: common.DeleteRequest
-	(*DeleteResponse)(nil),         // 10: common.DeleteResponse
-	(*DeleteManyRequest)(nil),      // 11: common.DeleteManyRequest
-	(*DeleteManyResponse)(nil),     // 12: common.DeleteManyResponse
-	(*AggregateRequest)(nil),       // 13: common.AggregateRequest
-	(*AggregateResponse)(nil),      // 14: common.AggregateResponse
-	(*ErrorDetail)(nil),            // 15: common.ErrorDetail
-	nil,                            // 16: common.QueryRequest.FiltersEntry
-	(*structpb.Struct)(nil),        // 17: google.protobuf.Struct
-	(*wrapperspb.StringValue)(nil), // 18: google.protobuf.StringValue
-	(*timestamppb.Timestamp)(nil),  // 19: google.protobuf.Timestamp
-	(*structpb.Value)(nil),         // 20: google.protobuf.Value
+	(*GenericRequest)(nil),             // 0: common.GenericRequest
+	(*GenericResponse)(nil),            // 1: common.GenericResponse
+	(*BatchRequest)(nil),               // 2: common.BatchRequest
+	(*BatchResponse)(nil),              // 3: common.BatchResponse
+	(*GetByIdRequest)(nil),             // 4: common.GetByIdRequest
+	(*QueryRequest)(nil),               // 5: common.QueryRequest
+	(*QueryResponse)(nil),              // 6: common.QueryResponse
+	(*SearchHit)(nil),                  // 7: common.SearchHit
+	(*Highlight)(nil),                  // 8: common.Highlight
+	(*Pagination)(nil),                 // 9: common.Pagination
+	(*UpdateRequest)(nil),              // 10: common.UpdateRequest
+	(*DeleteRequest)(nil),              // 11: common.DeleteRequest
+	(*DeleteResponse)(nil),             // 12: common.DeleteResponse
+	(*DeleteManyRequest)(nil),          // 13: common.DeleteManyRequest
+	(*DeleteManyResponse)(nil),         // 14: common.DeleteManyResponse
+	(*DependentEffect)(nil),            // 15: common.DependentEffect
+	(*SearchRequest)(nil),              // 16: common.SearchRequest
+	(*SearchResponse)(nil),             // 17: common.SearchResponse
+	(*Facet)(nil),                      // 18: common.Facet
+	(*FacetTerm)(nil),                  // 19: common.FacetTerm
+	(*AggregateRequest)(nil),           // 20: common.AggregateRequest
+	(*AggregateResponse)(nil),          // 21: common.AggregateResponse
+	(*ErrorDetail)(nil),                // 22: common.ErrorDetail
+	(*FileMetadata)(nil),               // 23: common.FileMetadata
+	(*FileInfo)(nil),                   // 24: common.FileInfo
+	(*UploadRequest)(nil),              // 25: common.UploadRequest
+	(*UploadResponse)(nil),             // 26: common.UploadResponse
+	(*DownloadRequest)(nil),            // 27: common.DownloadRequest
+	(*DownloadResponse)(nil),           // 28: common.DownloadResponse
+	(*GetFileRequest)(nil),             // 29: common.GetFileRequest
+	(*FileResponse)(nil),               // 30: common.FileResponse
+	(*ListFilesRequest)(nil),           // 31: common.ListFilesRequest
+	(*ListFilesResponse)(nil),          // 32: common.ListFilesResponse
+	(*DownloadURLRequest)(nil),         // 33: common.DownloadURLRequest
+	(*DownloadURLResponse)(nil),        // 34: common.DownloadURLResponse
+	(*NextSequenceRequest)(nil),        // 35: common.NextSequenceRequest
+	(*NextSequenceResponse)(nil),       // 36: common.NextSequenceResponse
+	(*VersionInfo)(nil),                // 37: common.VersionInfo
+	(*ListVersionsRequest)(nil),        // 38: common.ListVersionsRequest
+	(*ListVersionsResponse)(nil),       // 39: common.ListVersionsResponse
+	(*GetVersionRequest)(nil),          // 40: common.GetVersionRequest
+	(*VersionResponse)(nil),            // 41: common.VersionResponse
+	(*DiffVersionsRequest)(nil),        // 42: common.DiffVersionsRequest
+	(*FieldChange)(nil),                // 43: common.FieldChange
+	(*DiffVersionsResponse)(nil),       // 44: common.DiffVersionsResponse
+	(*RevertToVersionRequest)(nil),     // 45: common.RevertToVersionRequest
+	(*TransitionThesisRequest)(nil),    // 46: common.TransitionThesisRequest
+	(*TransitionThesisResponse)(nil),   // 47: common.TransitionThesisResponse
+	(*SupervisorCapacity)(nil),         // 48: common.SupervisorCapacity
+	(*AssignSupervisorsRequest)(nil),   // 49: common.AssignSupervisorsRequest
+	(*ProposedAssignment)(nil),         // 50: common.ProposedAssignment
+	(*UnassignedThesis)(nil),           // 51: common.UnassignedThesis
+	(*SupervisorLoad)(nil),             // 52: common.SupervisorLoad
+	(*AssignSupervisorsResponse)(nil),  // 53: common.AssignSupervisorsResponse
+	(*TimeWindow)(nil),                 // 54: common.TimeWindow
+	(*DefenseRoom)(nil),                // 55: common.DefenseRoom
+	(*MemberAvailability)(nil),         // 56: common.MemberAvailability
+	(*ScheduleDefensesRequest)(nil),    // 57: common.ScheduleDefensesRequest
+	(*CommitteeSeat)(nil),              // 58: common.CommitteeSeat
+	(*ProposedDefense)(nil),            // 59: common.ProposedDefense
+	(*UnscheduledThesis)(nil),          // 60: common.UnscheduledThesis
+	(*ScheduleDefensesResponse)(nil),   // 61: common.ScheduleDefensesResponse
+	(*ComputeFinalGradeRequest)(nil),   // 62: common.ComputeFinalGradeRequest
+	(*SourceGrade)(nil),                // 63: common.SourceGrade
+	(*ScorerGrade)(nil),                // 64: common.ScorerGrade
+	(*ComputeFinalGradeResponse)(nil),  // 65: common.ComputeFinalGradeResponse
+	(*CheckSimilarityRequest)(nil),     // 66: common.CheckSimilarityRequest
+	(*GetSimilarityReportRequest)(nil), // 67: common.GetSimilarityReportRequest
+	(*OverlapPassage)(nil),             // 68: common.OverlapPassage
+	(*SimilarityMatch)(nil),            // 69: common.SimilarityMatch
+	(*SimilarityReport)(nil),           // 70: common.SimilarityReport
+	(*SimilarityReportResponse)(nil),   // 71: common.SimilarityReportResponse
+	nil,                                // 72: common.QueryRequest.FiltersEntry
+	nil,                                // 73: common.SearchRequest.FiltersEntry
+	nil,                                // 74: common.ScorerGrade.CriteriaEntry
+	(*structpb.Struct)(nil),            // 75: google.protobuf.Struct
+	(*wrapperspb.StringValue)(nil),     // 76: google.protobuf.StringValue
+	(*timestamppb.Timestamp)(nil),      // 77: google.protobuf.Timestamp
+	(*wrapperspb.Int32Value)(nil),      // 78: google.protobuf.Int32Value
+	(*structpb.Value)(nil),             // 79: google.protobuf.Value
 }
 var file_proto_common_common_proto_depIdxs = []int32{
-	17, // 0: common.GenericRequest.data:type_name -> google.protobuf.Struct
-	17, // 1: common.GenericRequest.meta:type_name -> google.protobuf.Struct
-	18, // 2: common.GenericResponse.id:type_name -> google.protobuf.StringValue
-	17, // 3: common.GenericResponse.entity:type_name -> google.protobuf.Struct
-	19, // 4: common.GenericResponse.timestamp:type_name -> google.protobuf.Timestamp
-	17, // 5: common.BatchRequest.entities:type_name -> google.protobuf.Struct
-	17, // 6: common.BatchRequest.meta:type_name -> google.protobuf.Struct
-	15, // 7: common.BatchResponse.errors:type_name -> common.ErrorDetail
-	17, // 8: common.BatchResponse.entities:type_name -> google.protobuf.Struct
-	17, // 9: common.GetByIdRequest.meta:type_name -> google.protobuf.Struct
-	16, // 10: common.QueryRequest.filters:type_name -> common.QueryRequest.FiltersEntry
-	17, // 11: common.QueryRequest.pipeline:type_name -> google.protobuf.Struct
-	17, // 12: common.QueryRequest.meta:type_name -> google.protobuf.Struct
-	17, // 13: common.QueryResponse.entities:type_name -> google.protobuf.Struct
-	7,  // 14: common.QueryResponse.pagination:type_name -> common.Pagination
-	17, // 15: common.UpdateRequest.data:type_name -> google.protobuf.Struct
-	17, // 16: common.UpdateRequest.meta:type_name -> google.protobuf.Struct
-	17, // 17: common.DeleteRequest.meta:type_name -> google.protobuf.Struct
-	17, // 18: common.DeleteManyRequest.pipeline:type_name -> google.protobuf.Struct
-	17, // 19: common.DeleteManyRequest.meta:type_name -> google.protobuf.Struct
-	17, // 20: common.AggregateRequest.pipeline:type_name -> google.protobuf.Struct
-	17, // 21: common.AggregateRequest.meta:type_name -> google.protobuf.Struct
-	17, // 22: common.AggregateResponse.results:type_name -> google.protobuf.Struct
-	20, // 23: common.QueryRequest.FiltersEntry.value:type_name -> google.protobuf.Value
-	0,  // 24: common.CommonService.Create:input_type -> common.GenericRequest
-	2,  // 25: common.CommonService.CreateMany:input_type -> common.BatchRequest
-	4,  // 26: common.CommonService.GetById:input_type -> common.GetByIdRequest
-	5,  // 27: common.CommonService.Query:input_type -> common.QueryRequest
-	8,  // 28: common.CommonService.Update:input_type -> common.UpdateRequest
-	9,  // 29: common.CommonService.Delete:input_type -> common.DeleteRequest
-	11, // 30: common.CommonService.DeleteMany:input_type -> common.DeleteManyRequest
-	13, // 31: common.CommonService.Aggregate:input_type -> common.AggregateRequest
-	1,  // 32: common.CommonService.Create:output_type -> common.GenericResponse
-	3,  // 33: common.CommonService.CreateMany:output_type -> common.BatchResponse
-	1,  // 34: common.CommonService.GetById:output_type -> common.GenericResponse
-	6,  // 35: common.CommonService.Query:output_type -> common.QueryResponse
-	1,  // 36: common.CommonService.Update:output_type -> common.GenericResponse
-	10, // 37: common.CommonService.Delete:output_type -> common.DeleteResponse
-	12, // 38: common.CommonService.DeleteMany:output_type -> common.DeleteManyResponse
-	14, // 39: common.CommonService.Aggregate:output_type -> common.AggregateResponse
-	32, // [32:40] is the sub-list for method output_type
-	24, // [24:32] is the sub-list for method input_type
-	24, // [24:24] is the sub-list for extension type_name
-	24, // [24:24] is the sub-list for extension extendee
-	0,  // [0:24] is the sub-list for field type_name
+	75,  // 0: common.GenericRequest.data:type_name -> google.protobuf.Struct
+	75,  // 1: common.GenericRequest.meta:type_name -> google.protobuf.Struct
+	76,  // 2: common.GenericResponse.id:type_name -> google.protobuf.StringValue
+	75,  // 3: common.GenericResponse.entity:type_name -> google.protobuf.Struct
+	77,  // 4: common.GenericResponse.timestamp:type_name -> google.protobuf.Timestamp
+	22,  // 5: common.GenericResponse.errors:type_name -> common.ErrorDetail
+	75,  // 6: common.BatchRequest.entities:type_name -> google.protobuf.Struct
+	75,  // 7: common.BatchRequest.meta:type_name -> google.protobuf.Struct
+	22,  // 8: common.BatchResponse.errors:type_name -> common.ErrorDetail
+	75,  // 9: common.BatchResponse.entities:type_name -> google.protobuf.Struct
+	75,  // 10: common.GetByIdRequest.meta:type_name -> google.protobuf.Struct
+	72,  // 11: common.QueryRequest.filters:type_name -> common.QueryRequest.FiltersEntry
+	75,  // 12: common.QueryRequest.pipeline:type_name -> google.protobuf.Struct
+	75,  // 13: common.QueryRequest.meta:type_name -> google.protobuf.Struct
+	75,  // 14: common.QueryResponse.entities:type_name -> google.protobuf.Struct
+	9,   // 15: common.QueryResponse.pagination:type_name -> common.Pagination
+	7,   // 16: common.QueryResponse.hits:type_name -> common.SearchHit
+	8,   // 17: common.SearchHit.highlights:type_name -> common.Highlight
+	75,  // 18: common.UpdateRequest.data:type_name -> google.protobuf.Struct
+	75,  // 19: common.UpdateRequest.meta:type_name -> google.protobuf.Struct
+	75,  // 20: common.DeleteRequest.meta:type_name -> google.protobuf.Struct
+	15,  // 21: common.DeleteResponse.dependents:type_name -> common.DependentEffect
+	75,  // 22: common.DeleteManyRequest.pipeline:type_name -> google.protobuf.Struct
+	75,  // 23: common.DeleteManyRequest.meta:type_name -> google.protobuf.Struct
+	15,  // 24: common.DeleteManyResponse.dependents:type_name -> common.DependentEffect
+	78,  // 25: common.SearchRequest.fuzziness:type_name -> google.protobuf.Int32Value
+	73,  // 26: common.SearchRequest.filters:type_name -> common.SearchRequest.FiltersEntry
+	75,  // 27: common.SearchRequest.meta:type_name -> google.protobuf.Struct
+	7,   // 28: common.SearchResponse.hits:type_name -> common.SearchHit
+	75,  // 29: common.SearchResponse.entities:type_name -> google.protobuf.Struct
+	9,   // 30: common.SearchResponse.pagination:type_name -> common.Pagination
+	18,  // 31: common.SearchResponse.facets:type_name -> common.Facet
+	19,  // 32: common.Facet.terms:type_name -> common.FacetTerm
+	75,  // 33: common.AggregateRequest.pipeline:type_name -> google.protobuf.Struct
+	75,  // 34: common.AggregateRequest.meta:type_name -> google.protobuf.Struct
+	75,  // 35: common.AggregateResponse.results:type_name -> google.protobuf.Struct
+	77,  // 36: common.FileInfo.created_at:type_name -> google.protobuf.Timestamp
+	23,  // 37: common.UploadRequest.metadata:type_name -> common.FileMetadata
+	24,  // 38: common.UploadResponse.file:type_name -> common.FileInfo
+	75,  // 39: common.DownloadRequest.meta:type_name -> google.protobuf.Struct
+	24,  // 40: common.DownloadResponse.info:type_name -> common.FileInfo
+	75,  // 41: common.GetFileRequest.meta:type_name -> google.protobuf.Struct
+	24,  // 42: common.FileResponse.file:type_name -> common.FileInfo
+	75,  // 43: common.ListFilesRequest.meta:type_name -> google.protobuf.Struct
+	24,  // 44: common.ListFilesResponse.files:type_name -> common.FileInfo
+	77,  // 45: common.DownloadURLResponse.expires_at:type_name -> google.protobuf.Timestamp
+	75,  // 46: common.NextSequenceRequest.meta:type_name -> google.protobuf.Struct
+	75,  // 47: common.VersionInfo.meta:type_name -> google.protobuf.Struct
+	77,  // 48: common.VersionInfo.created_at:type_name -> google.protobuf.Timestamp
+	75,  // 49: common.ListVersionsRequest.meta:type_name -> google.protobuf.Struct
+	37,  // 50: common.ListVersionsResponse.versions:type_name -> common.VersionInfo
+	75,  // 51: common.GetVersionRequest.meta:type_name -> google.protobuf.Struct
+	37,  // 52: common.VersionResponse.version:type_name -> common.VersionInfo
+	75,  // 53: common.VersionResponse.entity:type_name -> google.protobuf.Struct
+	75,  // 54: common.DiffVersionsRequest.meta:type_name -> google.protobuf.Struct
+	79,  // 55: common.FieldChange.from:type_name -> google.protobuf.Value
+	79,  // 56: common.FieldChange.to:type_name -> google.protobuf.Value
+	43,  // 57: common.DiffVersionsResponse.changes:type_name -> common.FieldChange
+	75,  // 58: common.RevertToVersionRequest.meta:type_name -> google.protobuf.Struct
+	75,  // 59: common.TransitionThesisRequest.meta:type_name -> google.protobuf.Struct
+	75,  // 60: common.TransitionThesisResponse.entity:type_name -> google.protobuf.Struct
+	22,  // 61: common.TransitionThesisResponse.errors:type_name -> common.ErrorDetail
+	48,  // 62: common.AssignSupervisorsRequest.capacities:type_name -> common.SupervisorCapacity
+	75,  // 63: common.AssignSupervisorsRequest.meta:type_name -> google.protobuf.Struct
+	50,  // 64: common.AssignSupervisorsResponse.assignments:type_name -> common.ProposedAssignment
+	51,  // 65: common.AssignSupervisorsResponse.unassigned:type_name -> common.UnassignedThesis
+	52,  // 66: common.AssignSupervisorsResponse.loads:type_name -> common.SupervisorLoad
+	22,  // 67: common.AssignSupervisorsResponse.errors:type_name -> common.ErrorDetail
+	77,  // 68: common.TimeWindow.start:type_name -> google.protobuf.Timestamp
+	77,  // 69: common.TimeWindow.end:type_name -> google.protobuf.Timestamp
+	54,  // 70: common.MemberAvailability.unavailable:type_name -> common.TimeWindow
+	54,  // 71: common.ScheduleDefensesRequest.windows:type_name -> common.TimeWindow
+	55,  // 72: common.ScheduleDefensesRequest.rooms:type_name -> common.DefenseRoom
+	56,  // 73: common.ScheduleDefensesRequest.availability:type_name -> common.MemberAvailability
+	75,  // 74: common.ScheduleDefensesRequest.meta:type_name -> google.protobuf.Struct
+	77,  // 75: common.ProposedDefense.start:type_name -> google.protobuf.Timestamp
+	77,  // 76: common.ProposedDefense.end:type_name -> google.protobuf.Timestamp
+	58,  // 77: common.ProposedDefense.committee:type_name -> common.CommitteeSeat
+	59,  // 78: common.ScheduleDefensesResponse.schedules:type_name -> common.ProposedDefense
+	60,  // 79: common.ScheduleDefensesResponse.unscheduled:type_name -> common.UnscheduledThesis
+	22,  // 80: common.ScheduleDefensesResponse.errors:type_name -> common.ErrorDetail
+	75,  // 81: common.ComputeFinalGradeRequest.meta:type_name -> google.protobuf.Struct
+	74,  // 82: common.ScorerGrade.criteria:type_name -> common.ScorerGrade.CriteriaEntry
+	63,  // 83: common.ComputeFinalGradeResponse.sources:type_name -> common.SourceGrade
+	64,  // 84: common.ComputeFinalGradeResponse.scorers:type_name -> common.ScorerGrade
+	22,  // 85: common.ComputeFinalGradeResponse.errors:type_name -> common.ErrorDetail
+	75,  // 86: common.CheckSimilarityRequest.meta:type_name -> google.protobuf.Struct
+	75,  // 87: common.GetSimilarityReportRequest.meta:type_name -> google.protobuf.Struct
+	68,  // 88: common.SimilarityMatch.passages:type_name -> common.OverlapPassage
+	69,  // 89: common.SimilarityReport.matches:type_name -> common.SimilarityMatch
+	77,  // 90: common.SimilarityReport.checked_at:type_name -> google.protobuf.Timestamp
+	70,  // 91: common.SimilarityReportResponse.report:type_name -> common.SimilarityReport
+	79,  // 92: common.QueryRequest.FiltersEntry.value:type_name -> google.protobuf.Value
+	0,   // 93: common.CommonService.Create:input_type -> common.GenericRequest
+	2,   // 94: common.CommonService.CreateMany:input_type -> common.BatchRequest
+	4,   // 95: common.CommonService.GetById:input_type -> common.GetByIdRequest
+	5,   // 96: common.CommonService.Query:input_type -> common.QueryRequest
+	10,  // 97: common.CommonService.Update:input_type -> common.UpdateRequest
+	11,  // 98: common.CommonService.Delete:input_type -> common.DeleteRequest
+	13,  // 99: common.CommonService.DeleteMany:input_type -> common.DeleteManyRequest
+	20,  // 100: common.CommonService.Aggregate:input_type -> common.AggregateRequest
+	16,  // 101: common.CommonService.Search:input_type -> common.SearchRequest
+	35,  // 102: common.CommonService.NextSequence:input_type -> common.NextSequenceRequest
+	38,  // 103: common.CommonService.ListVersions:input_type -> common.ListVersionsRequest
+	40,  // 104: common.CommonService.GetVersion:input_type -> common.GetVersionRequest
+	42,  // 105: common.CommonService.DiffVersions:input_type -> common.DiffVersionsRequest
+	45,  // 106: common.CommonService.RevertToVersion:input_type -> common.RevertToVersionRequest
+	46,  // 107: common.CommonService.TransitionThesis:input_type -> common.TransitionThesisRequest
+	49,  // 108: common.CommonService.AssignSupervisors:input_type -> common.AssignSupervisorsRequest
+	57,  // 109: common.CommonService.ScheduleDefenses:input_type -> common.ScheduleDefensesRequest
+	62,  // 110: common.CommonService.ComputeFinalGrade:input_type -> common.ComputeFinalGradeRequest
+	66,  // 111: common.CommonService.CheckSimilarity:input_type -> common.CheckSimilarityRequest
+	67,  // 112: common.CommonService.GetSimilarityReport:input_type -> common.GetSimilarityReportRequest
+	25,  // 113: common.FileService.Upload:input_type -> common.UploadRequest
+	27,  // 114: common.FileService.Download:input_type -> common.DownloadRequest
+	29,  // 115: common.FileService.GetFile:input_type -> common.GetFileRequest
+	31,  // 116: common.FileService.ListFiles:input_type -> common.ListFilesRequest
+	29,  // 117: common.FileService.DeleteFile:input_type -> common.GetFileRequest
+	33,  // 118: common.FileService.GetDownloadURL:input_type -> common.DownloadURLRequest
+	1,   // 119: common.CommonService.Create:output_type -> common.GenericResponse
+	3,   // 120: common.CommonService.CreateMany:output_type -> common.BatchResponse
+	1,   // 121: common.CommonService.GetById:output_type -> common.GenericResponse
+	6,   // 122: common.CommonService.Query:output_type -> common.QueryResponse
+	1,   // 123: common.CommonService.Update:output_type -> common.GenericResponse
+	12,  // 124: common.CommonService.Delete:output_type -> common.DeleteResponse
+	14,  // 125: common.CommonService.DeleteMany:output_type -> common.DeleteManyResponse
+	21,  // 126: common.CommonService.Aggregate:output_type -> common.AggregateResponse
+	17,  // 127: common.CommonService.Search:output_type -> common.SearchResponse
+	36,  // 128: common.CommonService.NextSequence:output_type -> common.NextSequenceResponse
+	39,  // 129: common.CommonService.ListVersions:output_type -> common.ListVersionsResponse
+	41,  // 130: common.CommonService.GetVersion:output_type -> common.VersionResponse
+	44,  // 131: common.CommonService.DiffVersions:output_type -> common.DiffVersionsResponse
+	1,   // 132: common.CommonService.RevertToVersion:output_type -> common.GenericResponse
+	47,  // 133: common.CommonService.TransitionThesis:output_type -> common.TransitionThesisResponse
+	53,  // 134: common.CommonService.AssignSupervisors:output_type -> common.AssignSupervisorsResponse
+	61,  // 135: common.CommonService.ScheduleDefenses:output_type -> common.ScheduleDefensesResponse
+	65,  // 136: common.CommonService.ComputeFinalGrade:output_type -> common.ComputeFinalGradeResponse
+	71,  // 137: common.CommonService.CheckSimilarity:output_type -> common.SimilarityReportResponse
+	71,  // 138: common.CommonService.GetSimilarityReport:output_type -> common.SimilarityReportResponse
+	26,  // 139: common.FileService.Upload:output_type -> common.UploadResponse
+	28,  // 140: common.FileService.Download:output_type -> common.DownloadResponse
+	30,  // 141: common.FileService.GetFile:output_type -> common.FileResponse
+	32,  // 142: common.FileService.ListFiles:output_type -> common.ListFilesResponse
+	12,  // 143: common.FileService.DeleteFile:output_type -> common.DeleteResponse
+	34,  // 144: common.FileService.GetDownloadURL:output_type -> common.DownloadURLResponse
+	119, // [119:145] is the sub-list for method output_type
+	93,  // [93:119] is the sub-list for method input_type
+	93,  // [93:93] is the sub-list for extension type_name
+	93,  // [93:93] is the sub-list for extension extendee
+	0,   // [0:93] is the sub-list for field type_name
 }
 
 func init() { file_proto_common_common_proto_init() }
@@ -1382,15 +6260,23 @@ func file_proto_common_common_proto_init() {
 	if File_proto_common_common_proto != nil {
 		return
 	}
+	file_proto_common_common_proto_msgTypes[25].OneofWrappers = []any{
+		(*UploadRequest_Metadata)(nil),
+		(*UploadRequest_Chunk)(nil),
+	}
+	file_proto_common_common_proto_msgTypes[28].OneofWrappers = []any{
+		(*DownloadResponse_Info)(nil),
+		(*DownloadResponse_Chunk)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_common_common_proto_rawDesc), len(file_proto_common_common_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   75,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_proto_common_common_proto_goTypes,
 		DependencyIndexes: file_proto_common_common_proto_depIdxs,
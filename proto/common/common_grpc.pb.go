@@ -30,6 +30,28 @@ type CommonServiceClient interface {
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
 	DeleteMany(ctx context.Context, in *DeleteManyRequest, opts ...grpc.CallOption) (*DeleteManyResponse, error)
 	Aggregate(ctx context.Context, in *AggregateRequest, opts ...grpc.CallOption) (*AggregateResponse, error)
+	// Tìm kiếm luận văn và luận văn lưu trữ qua search index nhúng
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// Lấy giá trị tiếp theo của bộ đếm trong _counters
+	NextSequence(ctx context.Context, in *NextSequenceRequest, opts ...grpc.CallOption) (*NextSequenceResponse, error)
+	// Lịch sử phiên bản của các entity bật history (lưu trong <entity>_history).
+	// Quyền như entity gốc; lịch sử của entity đã xóa chỉ giáo vụ xem được
+	ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error)
+	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*VersionResponse, error)
+	DiffVersions(ctx context.Context, in *DiffVersionsRequest, opts ...grpc.CallOption) (*DiffVersionsResponse, error)
+	// Khôi phục entity về một phiên bản, tạo lại nếu entity đã bị xóa (chỉ giáo vụ)
+	RevertToVersion(ctx context.Context, in *RevertToVersionRequest, opts ...grpc.CallOption) (*GenericResponse, error)
+	// Chuyển trạng thái luận văn theo quy trình, ghi event_logs
+	TransitionThesis(ctx context.Context, in *TransitionThesisRequest, opts ...grpc.CallOption) (*TransitionThesisResponse, error)
+	// Phân công giảng viên hướng dẫn cho các luận văn chưa có người hướng dẫn
+	AssignSupervisors(ctx context.Context, in *AssignSupervisorsRequest, opts ...grpc.CallOption) (*AssignSupervisorsResponse, error)
+	// Xếp lịch bảo vệ không trùng phòng, hội đồng và sinh viên
+	ScheduleDefenses(ctx context.Context, in *ScheduleDefensesRequest, opts ...grpc.CallOption) (*ScheduleDefensesResponse, error)
+	// Tính điểm tổng kết theo rubric, công bố thì khóa điểm
+	ComputeFinalGrade(ctx context.Context, in *ComputeFinalGradeRequest, opts ...grpc.CallOption) (*ComputeFinalGradeResponse, error)
+	// Lấy dấu vân tay tài liệu và so trùng lặp, được worker asynq gọi
+	CheckSimilarity(ctx context.Context, in *CheckSimilarityRequest, opts ...grpc.CallOption) (*SimilarityReportResponse, error)
+	GetSimilarityReport(ctx context.Context, in *GetSimilarityReportRequest, opts ...grpc.CallOption) (*SimilarityReportResponse, error)
 }
 
 type commonServiceClient struct {
@@ -112,6 +134,114 @@ func (c *commonServiceClient) Aggregate(ctx context.Context, in *AggregateReques
 	return out, nil
 }
 
+func (c *commonServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/common.CommonService/Search", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commonServiceClient) NextSequence(ctx context.Context, in *NextSequenceRequest, opts ...grpc.CallOption) (*NextSequenceResponse, error) {
+	out := new(NextSequenceResponse)
+	err := c.cc.Invoke(ctx, "/common.CommonService/NextSequence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commonServiceClient) ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error) {
+	out := new(ListVersionsResponse)
+	err := c.cc.Invoke(ctx, "/common.CommonService/ListVersions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commonServiceClient) GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*VersionResponse, error) {
+	out := new(VersionResponse)
+	err := c.cc.Invoke(ctx, "/common.CommonService/GetVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commonServiceClient) DiffVersions(ctx context.Context, in *DiffVersionsRequest, opts ...grpc.CallOption) (*DiffVersionsResponse, error) {
+	out := new(DiffVersionsResponse)
+	err := c.cc.Invoke(ctx, "/common.CommonService/DiffVersions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commonServiceClient) RevertToVersion(ctx context.Context, in *RevertToVersionRequest, opts ...grpc.CallOption) (*GenericResponse, error) {
+	out := new(GenericResponse)
+	err := c.cc.Invoke(ctx, "/common.CommonService/RevertToVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commonServiceClient) TransitionThesis(ctx context.Context, in *TransitionThesisRequest, opts ...grpc.CallOption) (*TransitionThesisResponse, error) {
+	out := new(TransitionThesisResponse)
+	err := c.cc.Invoke(ctx, "/common.CommonService/TransitionThesis", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commonServiceClient) AssignSupervisors(ctx context.Context, in *AssignSupervisorsRequest, opts ...grpc.CallOption) (*AssignSupervisorsResponse, error) {
+	out := new(AssignSupervisorsResponse)
+	err := c.cc.Invoke(ctx, "/common.CommonService/AssignSupervisors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commonServiceClient) ScheduleDefenses(ctx context.Context, in *ScheduleDefensesRequest, opts ...grpc.CallOption) (*ScheduleDefensesResponse, error) {
+	out := new(ScheduleDefensesResponse)
+	err := c.cc.Invoke(ctx, "/common.CommonService/ScheduleDefenses", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commonServiceClient) ComputeFinalGrade(ctx context.Context, in *ComputeFinalGradeRequest, opts ...grpc.CallOption) (*ComputeFinalGradeResponse, error) {
+	out := new(ComputeFinalGradeResponse)
+	err := c.cc.Invoke(ctx, "/common.CommonService/ComputeFinalGrade", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commonServiceClient) CheckSimilarity(ctx context.Context, in *CheckSimilarityRequest, opts ...grpc.CallOption) (*SimilarityReportResponse, error) {
+	out := new(SimilarityReportResponse)
+	err := c.cc.Invoke(ctx, "/common.CommonService/CheckSimilarity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *commonServiceClient) GetSimilarityReport(ctx context.Context, in *GetSimilarityReportRequest, opts ...grpc.CallOption) (*SimilarityReportResponse, error) {
+	out := new(SimilarityReportResponse)
+	err := c.cc.Invoke(ctx, "/common.CommonService/GetSimilarityReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CommonServiceServer is the server API for CommonService service.
 // All implementations must embed UnimplementedCommonServiceServer
 // for forward compatibility
@@ -124,6 +254,28 @@ type CommonServiceServer interface {
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
 	DeleteMany(context.Context, *DeleteManyRequest) (*DeleteManyResponse, error)
 	Aggregate(context.Context, *AggregateRequest) (*AggregateResponse, error)
+	// Tìm kiếm luận văn và luận văn lưu trữ qua search index nhúng
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// Lấy giá trị tiếp theo của bộ đếm trong _counters
+	NextSequence(context.Context, *NextSequenceRequest) (*NextSequenceResponse, error)
+	// Lịch sử phiên bản của các entity bật history (lưu trong <entity>_history).
+	// Quyền như entity gốc; lịch sử của entity đã xóa chỉ giáo vụ xem được
+	ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error)
+	GetVersion(context.Context, *GetVersionRequest) (*VersionResponse, error)
+	DiffVersions(context.Context, *DiffVersionsRequest) (*DiffVersionsResponse, error)
+	// Khôi phục entity về một phiên bản, tạo lại nếu entity đã bị xóa (chỉ giáo vụ)
+	RevertToVersion(context.Context, *RevertToVersionRequest) (*GenericResponse, error)
+	// Chuyển trạng thái luận văn theo quy trình, ghi event_logs
+	TransitionThesis(context.Context, *TransitionThesisRequest) (*TransitionThesisResponse, error)
+	// Phân công giảng viên hướng dẫn cho các luận văn chưa có người hướng dẫn
+	AssignSupervisors(context.Context, *AssignSupervisorsRequest) (*AssignSupervisorsResponse, error)
+	// Xếp lịch bảo vệ không trùng phòng, hội đồng và sinh viên
+	ScheduleDefenses(context.Context, *ScheduleDefensesRequest) (*ScheduleDefensesResponse, error)
+	// Tính điểm tổng kết theo rubric, công bố thì khóa điểm
+	ComputeFinalGrade(context.Context, *ComputeFinalGradeRequest) (*ComputeFinalGradeResponse, error)
+	// Lấy dấu vân tay tài liệu và so trùng lặp, được worker asynq gọi
+	CheckSimilarity(context.Context, *CheckSimilarityRequest) (*SimilarityReportResponse, error)
+	GetSimilarityReport(context.Context, *GetSimilarityReportRequest) (*SimilarityReportResponse, error)
 	mustEmbedUnimplementedCommonServiceServer()
 }
 
@@ -155,6 +307,42 @@ func (UnimplementedCommonServiceServer) DeleteMany(context.Context, *DeleteManyR
 func (UnimplementedCommonServiceServer) Aggregate(context.Context, *AggregateRequest) (*AggregateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Aggregate not implemented")
 }
+func (UnimplementedCommonServiceServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedCommonServiceServer) NextSequence(context.Context, *NextSequenceRequest) (*NextSequenceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NextSequence not implemented")
+}
+func (UnimplementedCommonServiceServer) ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVersions not implemented")
+}
+func (UnimplementedCommonServiceServer) GetVersion(context.Context, *GetVersionRequest) (*VersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
+func (UnimplementedCommonServiceServer) DiffVersions(context.Context, *DiffVersionsRequest) (*DiffVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffVersions not implemented")
+}
+func (UnimplementedCommonServiceServer) RevertToVersion(context.Context, *RevertToVersionRequest) (*GenericResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevertToVersion not implemented")
+}
+func (UnimplementedCommonServiceServer) TransitionThesis(context.Context, *TransitionThesisRequest) (*TransitionThesisResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransitionThesis not implemented")
+}
+func (UnimplementedCommonServiceServer) AssignSupervisors(context.Context, *AssignSupervisorsRequest) (*AssignSupervisorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignSupervisors not implemented")
+}
+func (UnimplementedCommonServiceServer) ScheduleDefenses(context.Context, *ScheduleDefensesRequest) (*ScheduleDefensesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleDefenses not implemented")
+}
+func (UnimplementedCommonServiceServer) ComputeFinalGrade(context.Context, *ComputeFinalGradeRequest) (*ComputeFinalGradeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComputeFinalGrade not implemented")
+}
+func (UnimplementedCommonServiceServer) CheckSimilarity(context.Context, *CheckSimilarityRequest) (*SimilarityReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckSimilarity not implemented")
+}
+func (UnimplementedCommonServiceServer) GetSimilarityReport(context.Context, *GetSimilarityReportRequest) (*SimilarityReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSimilarityReport not implemented")
+}
 func (UnimplementedCommonServiceServer) mustEmbedUnimplementedCommonServiceServer() {}
 
 // UnsafeCommonServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -312,6 +500,222 @@ func _CommonService_Aggregate_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CommonService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommonServiceServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.CommonService/Search",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommonServiceServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommonService_NextSequence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NextSequenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommonServiceServer).NextSequence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.CommonService/NextSequence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommonServiceServer).NextSequence(ctx, req.(*NextSequenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommonService_ListVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommonServiceServer).ListVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.CommonService/ListVersions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommonServiceServer).ListVersions(ctx, req.(*ListVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommonService_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommonServiceServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.CommonService/GetVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommonServiceServer).GetVersion(ctx, req.(*GetVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommonService_DiffVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommonServiceServer).DiffVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.CommonService/DiffVersions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommonServiceServer).DiffVersions(ctx, req.(*DiffVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommonService_RevertToVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertToVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommonServiceServer).RevertToVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.CommonService/RevertToVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommonServiceServer).RevertToVersion(ctx, req.(*RevertToVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommonService_TransitionThesis_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionThesisRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommonServiceServer).TransitionThesis(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.CommonService/TransitionThesis",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommonServiceServer).TransitionThesis(ctx, req.(*TransitionThesisRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommonService_AssignSupervisors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignSupervisorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommonServiceServer).AssignSupervisors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.CommonService/AssignSupervisors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommonServiceServer).AssignSupervisors(ctx, req.(*AssignSupervisorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommonService_ScheduleDefenses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleDefensesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommonServiceServer).ScheduleDefenses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.CommonService/ScheduleDefenses",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommonServiceServer).ScheduleDefenses(ctx, req.(*ScheduleDefensesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommonService_ComputeFinalGrade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComputeFinalGradeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommonServiceServer).ComputeFinalGrade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.CommonService/ComputeFinalGrade",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommonServiceServer).ComputeFinalGrade(ctx, req.(*ComputeFinalGradeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommonService_CheckSimilarity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckSimilarityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommonServiceServer).CheckSimilarity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.CommonService/CheckSimilarity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommonServiceServer).CheckSimilarity(ctx, req.(*CheckSimilarityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommonService_GetSimilarityReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSimilarityReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommonServiceServer).GetSimilarityReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.CommonService/GetSimilarityReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommonServiceServer).GetSimilarityReport(ctx, req.(*GetSimilarityReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CommonService_ServiceDesc is the grpc.ServiceDesc for CommonService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -351,7 +755,389 @@ var CommonService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Aggregate",
 			Handler:    _CommonService_Aggregate_Handler,
 		},
+		{
+			MethodName: "Search",
+			Handler:    _CommonService_Search_Handler,
+		},
+		{
+			MethodName: "NextSequence",
+			Handler:    _CommonService_NextSequence_Handler,
+		},
+		{
+			MethodName: "ListVersions",
+			Handler:    _CommonService_ListVersions_Handler,
+		},
+		{
+			MethodName: "GetVersion",
+			Handler:    _CommonService_GetVersion_Handler,
+		},
+		{
+			MethodName: "DiffVersions",
+			Handler:    _CommonService_DiffVersions_Handler,
+		},
+		{
+			MethodName: "RevertToVersion",
+			Handler:    _CommonService_RevertToVersion_Handler,
+		},
+		{
+			MethodName: "TransitionThesis",
+			Handler:    _CommonService_TransitionThesis_Handler,
+		},
+		{
+			MethodName: "AssignSupervisors",
+			Handler:    _CommonService_AssignSupervisors_Handler,
+		},
+		{
+			MethodName: "ScheduleDefenses",
+			Handler:    _CommonService_ScheduleDefenses_Handler,
+		},
+		{
+			MethodName: "ComputeFinalGrade",
+			Handler:    _CommonService_ComputeFinalGrade_Handler,
+		},
+		{
+			MethodName: "CheckSimilarity",
+			Handler:    _CommonService_CheckSimilarity_Handler,
+		},
+		{
+			MethodName: "GetSimilarityReport",
+			Handler:    _CommonService_GetSimilarityReport_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/common/common.proto",
 }
+
+// FileServiceClient is the client API for FileService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FileServiceClient interface {
+	// Message đầu tiên chứa metadata, các message sau chứa nội dung file
+	Upload(ctx context.Context, opts ...grpc.CallOption) (FileService_UploadClient, error)
+	// Message đầu tiên chứa thông tin file, các message sau chứa nội dung file
+	Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (FileService_DownloadClient, error)
+	GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*FileResponse, error)
+	ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error)
+	DeleteFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// Link tải trực tiếp qua HTTP, có chữ ký và thời hạn
+	GetDownloadURL(ctx context.Context, in *DownloadURLRequest, opts ...grpc.CallOption) (*DownloadURLResponse, error)
+}
+
+type fileServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFileServiceClient(cc grpc.ClientConnInterface) FileServiceClient {
+	return &fileServiceClient{cc}
+}
+
+func (c *fileServiceClient) Upload(ctx context.Context, opts ...grpc.CallOption) (FileService_UploadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileService_ServiceDesc.Streams[0], "/common.FileService/Upload", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileServiceUploadClient{stream}
+	return x, nil
+}
+
+type FileService_UploadClient interface {
+	Send(*UploadRequest) error
+	CloseAndRecv() (*UploadResponse, error)
+	grpc.ClientStream
+}
+
+type fileServiceUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileServiceUploadClient) Send(m *UploadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fileServiceUploadClient) CloseAndRecv() (*UploadResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(UploadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileServiceClient) Download(ctx context.Context, in *DownloadRequest, opts ...grpc.CallOption) (FileService_DownloadClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FileService_ServiceDesc.Streams[1], "/common.FileService/Download", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileServiceDownloadClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FileService_DownloadClient interface {
+	Recv() (*DownloadResponse, error)
+	grpc.ClientStream
+}
+
+type fileServiceDownloadClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileServiceDownloadClient) Recv() (*DownloadResponse, error) {
+	m := new(DownloadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileServiceClient) GetFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*FileResponse, error) {
+	out := new(FileResponse)
+	err := c.cc.Invoke(ctx, "/common.FileService/GetFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) ListFiles(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error) {
+	out := new(ListFilesResponse)
+	err := c.cc.Invoke(ctx, "/common.FileService/ListFiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) DeleteFile(ctx context.Context, in *GetFileRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/common.FileService/DeleteFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileServiceClient) GetDownloadURL(ctx context.Context, in *DownloadURLRequest, opts ...grpc.CallOption) (*DownloadURLResponse, error) {
+	out := new(DownloadURLResponse)
+	err := c.cc.Invoke(ctx, "/common.FileService/GetDownloadURL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FileServiceServer is the server API for FileService service.
+// All implementations must embed UnimplementedFileServiceServer
+// for forward compatibility
+type FileServiceServer interface {
+	// Message đầu tiên chứa metadata, các message sau chứa nội dung file
+	Upload(FileService_UploadServer) error
+	// Message đầu tiên chứa thông tin file, các message sau chứa nội dung file
+	Download(*DownloadRequest, FileService_DownloadServer) error
+	GetFile(context.Context, *GetFileRequest) (*FileResponse, error)
+	ListFiles(context.Context, *ListFilesRequest) (*ListFilesResponse, error)
+	DeleteFile(context.Context, *GetFileRequest) (*DeleteResponse, error)
+	// Link tải trực tiếp qua HTTP, có chữ ký và thời hạn
+	GetDownloadURL(context.Context, *DownloadURLRequest) (*DownloadURLResponse, error)
+	mustEmbedUnimplementedFileServiceServer()
+}
+
+// UnimplementedFileServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedFileServiceServer struct {
+}
+
+func (UnimplementedFileServiceServer) Upload(FileService_UploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedFileServiceServer) Download(*DownloadRequest, FileService_DownloadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Download not implemented")
+}
+func (UnimplementedFileServiceServer) GetFile(context.Context, *GetFileRequest) (*FileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFile not implemented")
+}
+func (UnimplementedFileServiceServer) ListFiles(context.Context, *ListFilesRequest) (*ListFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFiles not implemented")
+}
+func (UnimplementedFileServiceServer) DeleteFile(context.Context, *GetFileRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
+}
+func (UnimplementedFileServiceServer) GetDownloadURL(context.Context, *DownloadURLRequest) (*DownloadURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDownloadURL not implemented")
+}
+func (UnimplementedFileServiceServer) mustEmbedUnimplementedFileServiceServer() {}
+
+// UnsafeFileServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FileServiceServer will
+// result in compilation errors.
+type UnsafeFileServiceServer interface {
+	mustEmbedUnimplementedFileServiceServer()
+}
+
+func RegisterFileServiceServer(s grpc.ServiceRegistrar, srv FileServiceServer) {
+	s.RegisterService(&FileService_ServiceDesc, srv)
+}
+
+func _FileService_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileServiceServer).Upload(&fileServiceUploadServer{stream})
+}
+
+type FileService_UploadServer interface {
+	SendAndClose(*UploadResponse) error
+	Recv() (*UploadRequest, error)
+	grpc.ServerStream
+}
+
+type fileServiceUploadServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileServiceUploadServer) SendAndClose(m *UploadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fileServiceUploadServer) Recv() (*UploadRequest, error) {
+	m := new(UploadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FileService_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileServiceServer).Download(m, &fileServiceDownloadServer{stream})
+}
+
+type FileService_DownloadServer interface {
+	Send(*DownloadResponse) error
+	grpc.ServerStream
+}
+
+type fileServiceDownloadServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileServiceDownloadServer) Send(m *DownloadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FileService_GetFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).GetFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.FileService/GetFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).GetFile(ctx, req.(*GetFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_ListFiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).ListFiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.FileService/ListFiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).ListFiles(ctx, req.(*ListFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).DeleteFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.FileService/DeleteFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).DeleteFile(ctx, req.(*GetFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileService_GetDownloadURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DownloadURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileServiceServer).GetDownloadURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/common.FileService/GetDownloadURL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileServiceServer).GetDownloadURL(ctx, req.(*DownloadURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FileService_ServiceDesc is the grpc.ServiceDesc for FileService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FileService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "common.FileService",
+	HandlerType: (*FileServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFile",
+			Handler:    _FileService_GetFile_Handler,
+		},
+		{
+			MethodName: "ListFiles",
+			Handler:    _FileService_ListFiles_Handler,
+		},
+		{
+			MethodName: "DeleteFile",
+			Handler:    _FileService_DeleteFile_Handler,
+		},
+		{
+			MethodName: "GetDownloadURL",
+			Handler:    _FileService_GetDownloadURL_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Upload",
+			Handler:       _FileService_Upload_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Download",
+			Handler:       _FileService_Download_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/common/common.proto",
+}
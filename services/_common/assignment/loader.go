@@ -0,0 +1,266 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const AssignmentsCollection = "supervisor_assignments"
+
+// DefaultCapacity is the number of theses a supervisor takes per semester
+// when neither the request nor the user document says otherwise
+const DefaultCapacity = 5
+
+// Term is the semester an assignment run is made for
+type Term struct {
+	AcademicYear string
+	Semester     int
+}
+
+// Capacity overrides the capacity of one supervisor
+type Capacity struct {
+	MaxPrimary int
+	MaxCo      int
+}
+
+type Loader struct {
+	db *mongo.Database
+}
+
+func NewLoader(db *mongo.Database) *Loader {
+	return &Loader{db: db}
+}
+
+// Theses returns the theses of term without a supervisor. When ids is not
+// empty only those theses are considered; the ids that already have a
+// supervisor or are outside the term are returned as skipped.
+func (l *Loader) Theses(ctx context.Context, term Term, ids []primitive.ObjectID) ([]Thesis, []Unassigned, error) {
+	filter := bson.M{
+		"academic_year": term.AcademicYear,
+		"semester":      term.Semester,
+	}
+	if len(ids) > 0 {
+		filter["_id"] = bson.M{"$in": ids}
+	}
+
+	cursor, err := l.db.Collection("theses").Find(ctx, filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load theses: %w", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, nil, fmt.Errorf("failed to load theses: %w", err)
+	}
+
+	assigned, err := l.assignedTheses(ctx, docs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var theses []Thesis
+	var skipped []Unassigned
+	found := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		id, _ := doc["_id"].(primitive.ObjectID)
+		found[id.Hex()] = true
+		if hasValue(doc["supervisor_id"]) || assigned[id.Hex()] {
+			if len(ids) > 0 {
+				skipped = append(skipped, Unassigned{ThesisID: id.Hex(), Role: RolePrimary, Reason: "thesis already has a supervisor"})
+			}
+			continue
+		}
+
+		major, _ := doc["major"].(string)
+		theses = append(theses, Thesis{
+			ID:          id.Hex(),
+			Department:  major,
+			Tags:        append(stringList(doc["tags"]), stringList(doc["keywords"])...),
+			Preferences: idList(doc["preferred_supervisor_ids"]),
+		})
+	}
+
+	for _, id := range ids {
+		if !found[id.Hex()] {
+			skipped = append(skipped, Unassigned{ThesisID: id.Hex(), Role: RolePrimary, Reason: "thesis not found in the term"})
+		}
+	}
+	return theses, skipped, nil
+}
+
+// assignedTheses returns the theses having an active primary assignment
+func (l *Loader) assignedTheses(ctx context.Context, docs []bson.M) (map[string]bool, error) {
+	ids := bson.A{}
+	for _, doc := range docs {
+		if id, ok := doc["_id"].(primitive.ObjectID); ok {
+			ids = append(ids, id, id.Hex())
+		}
+	}
+	result := map[string]bool{}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	values, err := l.db.Collection(AssignmentsCollection).Distinct(ctx, "thesis_id", bson.M{
+		"thesis_id": bson.M{"$in": ids},
+		"role":      RolePrimary,
+		"is_active": true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load assignments: %w", err)
+	}
+	for _, value := range values {
+		result[idString(value)] = true
+	}
+	return result, nil
+}
+
+// Supervisors returns the supervisors with their capacity and their current
+// load in term. Without ids every active teacher is a candidate.
+func (l *Loader) Supervisors(ctx context.Context, term Term, ids []primitive.ObjectID, capacities map[string]Capacity, defaultCapacity int) ([]Supervisor, error) {
+	filter := bson.M{"status": bson.M{"$ne": "inactive"}}
+	if len(ids) > 0 {
+		filter["_id"] = bson.M{"$in": ids}
+	} else {
+		filter["$or"] = bson.A{
+			bson.M{"role": "teacher"},
+			bson.M{"code": bson.M{"$regex": "^GV"}},
+		}
+	}
+
+	cursor, err := l.db.Collection("users").Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load supervisors: %w", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to load supervisors: %w", err)
+	}
+
+	loads, err := l.loads(ctx, term)
+	if err != nil {
+		return nil, err
+	}
+
+	supervisors := make([]Supervisor, 0, len(docs))
+	for _, doc := range docs {
+		id, _ := doc["_id"].(primitive.ObjectID)
+		major, _ := doc["major"].(string)
+		s := Supervisor{
+			ID:         id.Hex(),
+			Department: major,
+			Expertise:  stringList(doc["expertise"]),
+			MaxPrimary: intValue(doc["max_theses"], defaultCapacity),
+			MaxCo:      intValue(doc["max_co_supervised"], defaultCapacity),
+			Primary:    loads[id.Hex()][RolePrimary],
+			Co:         loads[id.Hex()][RoleCoSupervisor],
+		}
+		if c, ok := capacities[s.ID]; ok {
+			s.MaxPrimary, s.MaxCo = c.MaxPrimary, c.MaxCo
+		}
+		supervisors = append(supervisors, s)
+	}
+	return supervisors, nil
+}
+
+// loads counts the active assignments of each supervisor in term, by role
+func (l *Loader) loads(ctx context.Context, term Term) (map[string]map[string]int, error) {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"is_active": true}},
+		// thesis_id có thể là ObjectID hoặc chuỗi hex
+		bson.M{"$lookup": bson.M{
+			"from": "theses",
+			"let":  bson.M{"thesis_id": bson.M{"$toString": "$thesis_id"}},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{bson.M{"$toString": "$_id"}, "$$thesis_id"}}}},
+				bson.M{"$project": bson.M{"academic_year": 1, "semester": 1}},
+			},
+			"as": "thesis",
+		}},
+		bson.M{"$match": bson.M{
+			"thesis.academic_year": term.AcademicYear,
+			"thesis.semester":      term.Semester,
+		}},
+		bson.M{"$group": bson.M{
+			"_id":   bson.M{"supervisor_id": "$supervisor_id", "role": "$role"},
+			"count": bson.M{"$sum": 1},
+		}},
+	}
+	cursor, err := l.db.Collection(AssignmentsCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count supervisor load: %w", err)
+	}
+	var rows []struct {
+		ID struct {
+			SupervisorID interface{} `bson:"supervisor_id"`
+			Role         string      `bson:"role"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to count supervisor load: %w", err)
+	}
+
+	result := map[string]map[string]int{}
+	for _, row := range rows {
+		id := idString(row.ID.SupervisorID)
+		if result[id] == nil {
+			result[id] = map[string]int{}
+		}
+		result[id][row.ID.Role] += row.Count
+	}
+	return result, nil
+}
+
+func hasValue(v interface{}) bool {
+	return v != nil && v != ""
+}
+
+func idString(v interface{}) string {
+	switch id := v.(type) {
+	case primitive.ObjectID:
+		return id.Hex()
+	case string:
+		return id
+	}
+	return ""
+}
+
+func stringList(v interface{}) []string {
+	var result []string
+	if values, ok := v.(bson.A); ok {
+		for _, value := range values {
+			if s, ok := value.(string); ok && s != "" {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
+
+func idList(v interface{}) []string {
+	var result []string
+	if values, ok := v.(bson.A); ok {
+		for _, value := range values {
+			if id := idString(value); id != "" {
+				result = append(result, id)
+			}
+		}
+	}
+	return result
+}
+
+func intValue(v interface{}, fallback int) int {
+	switch n := v.(type) {
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	}
+	return fallback
+}
@@ -0,0 +1,230 @@
+package assignment
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Giá trị role trong supervisor_assignments
+const (
+	RolePrimary      = "primary"
+	RoleCoSupervisor = "co_supervisor"
+)
+
+type Thesis struct {
+	ID         string
+	Department string
+	Tags       []string
+	// Preferences are supervisor ids in the student's order of choice
+	Preferences []string
+}
+
+type Supervisor struct {
+	ID         string
+	Department string
+	Expertise  []string
+	MaxPrimary int
+	MaxCo      int
+	// Current load of the semester, updated as the solver assigns
+	Primary int
+	Co      int
+}
+
+type Options struct {
+	WithCoSupervisors    bool
+	AllowCrossDepartment bool
+}
+
+type Assignment struct {
+	ThesisID     string
+	SupervisorID string
+	Role         string
+	Score        float64
+	Reasons      []string
+}
+
+type Unassigned struct {
+	ThesisID string
+	Role     string
+	Reason   string
+}
+
+type Result struct {
+	Assignments []Assignment
+	Unassigned  []Unassigned
+	// Supervisors with their load after the assignment, sorted by id
+	Supervisors []*Supervisor
+}
+
+// Trọng số khi chấm điểm một cặp luận văn - giảng viên
+const (
+	preferenceWeight      = 100.0
+	preferenceStep        = 20.0
+	expertiseWeight       = 15.0
+	loadWeight            = 30.0
+	crossDepartmentWeight = 25.0
+)
+
+// Solve assigns a primary supervisor, and a co-supervisor when asked, to each
+// thesis. It is greedy: theses with the fewest eligible supervisors go first
+// and each takes the best scoring supervisor with free capacity. The result
+// only depends on the input, so a preview matches the committed run.
+func Solve(theses []Thesis, supervisors []Supervisor, opts Options) Result {
+	pool := make([]*Supervisor, len(supervisors))
+	for i := range supervisors {
+		s := supervisors[i]
+		pool[i] = &s
+	}
+	sort.Slice(pool, func(i, j int) bool { return pool[i].ID < pool[j].ID })
+
+	order := make([]Thesis, len(theses))
+	copy(order, theses)
+	eligibleCount := make(map[string]int, len(order))
+	for _, t := range order {
+		for _, s := range pool {
+			if s.MaxPrimary > 0 && (opts.AllowCrossDepartment || sameDepartment(t, s)) {
+				eligibleCount[t.ID]++
+			}
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		if eligibleCount[order[i].ID] != eligibleCount[order[j].ID] {
+			return eligibleCount[order[i].ID] < eligibleCount[order[j].ID]
+		}
+		return order[i].ID < order[j].ID
+	})
+
+	result := Result{Supervisors: pool}
+	primaries := make(map[string]string, len(order))
+	for _, t := range order {
+		best, ok := pick(t, pool, RolePrimary, "", opts)
+		if !ok {
+			result.Unassigned = append(result.Unassigned, Unassigned{ThesisID: t.ID, Role: RolePrimary, Reason: noCandidateReason(t, opts)})
+			continue
+		}
+		best.supervisor.Primary++
+		primaries[t.ID] = best.supervisor.ID
+		result.Assignments = append(result.Assignments, best.assignment(t, RolePrimary))
+	}
+
+	if opts.WithCoSupervisors {
+		for _, t := range order {
+			primary, ok := primaries[t.ID]
+			if !ok {
+				continue
+			}
+			best, ok := pick(t, pool, RoleCoSupervisor, primary, opts)
+			if !ok {
+				result.Unassigned = append(result.Unassigned, Unassigned{ThesisID: t.ID, Role: RoleCoSupervisor, Reason: "no co-supervisor with free capacity"})
+				continue
+			}
+			best.supervisor.Co++
+			result.Assignments = append(result.Assignments, best.assignment(t, RoleCoSupervisor))
+		}
+	}
+	return result
+}
+
+type candidate struct {
+	supervisor *Supervisor
+	score      float64
+	load       float64
+	reasons    []string
+}
+
+func (c candidate) assignment(t Thesis, role string) Assignment {
+	return Assignment{
+		ThesisID:     t.ID,
+		SupervisorID: c.supervisor.ID,
+		Role:         role,
+		Score:        c.score,
+		Reasons:      c.reasons,
+	}
+}
+
+func pick(t Thesis, pool []*Supervisor, role, exclude string, opts Options) (candidate, bool) {
+	var best candidate
+	found := false
+	for _, s := range pool {
+		if s.ID == exclude {
+			continue
+		}
+		c, ok := evaluate(t, s, role, opts)
+		if !ok {
+			continue
+		}
+		if !found || c.score > best.score || (c.score == best.score && c.load < best.load) {
+			best = c
+			found = true
+		}
+	}
+	return best, found
+}
+
+func evaluate(t Thesis, s *Supervisor, role string, opts Options) (candidate, bool) {
+	used, max := s.Primary, s.MaxPrimary
+	if role == RoleCoSupervisor {
+		used, max = s.Co, s.MaxCo
+	}
+	if used >= max {
+		return candidate{}, false
+	}
+	sameDept := sameDepartment(t, s)
+	if !sameDept && !opts.AllowCrossDepartment {
+		return candidate{}, false
+	}
+
+	c := candidate{supervisor: s, load: float64(used) / float64(max)}
+	for rank, id := range t.Preferences {
+		if id == s.ID {
+			c.score += max64(preferenceWeight-preferenceStep*float64(rank), preferenceStep)
+			c.reasons = append(c.reasons, fmt.Sprintf("student preference #%d", rank+1))
+			break
+		}
+	}
+	if matched := overlap(t.Tags, s.Expertise); len(matched) > 0 {
+		c.score += expertiseWeight * float64(len(matched))
+		c.reasons = append(c.reasons, "expertise: "+strings.Join(matched, ", "))
+	}
+	if !sameDept {
+		c.score -= crossDepartmentWeight
+		c.reasons = append(c.reasons, "outside department "+t.Department)
+	}
+	c.score -= loadWeight * c.load
+	c.reasons = append(c.reasons, fmt.Sprintf("load %d/%d", used, max))
+	return c, true
+}
+
+func noCandidateReason(t Thesis, opts Options) string {
+	if opts.AllowCrossDepartment || t.Department == "" {
+		return "no supervisor with free capacity"
+	}
+	return fmt.Sprintf("no supervisor with free capacity in department %s", t.Department)
+}
+
+// Luận văn hoặc giảng viên chưa có khoa thì không bị giới hạn
+func sameDepartment(t Thesis, s *Supervisor) bool {
+	return t.Department == "" || s.Department == "" || strings.EqualFold(t.Department, s.Department)
+}
+
+func overlap(tags, expertise []string) []string {
+	have := make(map[string]bool, len(expertise))
+	for _, e := range expertise {
+		have[strings.ToLower(strings.TrimSpace(e))] = true
+	}
+	var matched []string
+	for _, tag := range tags {
+		if have[strings.ToLower(strings.TrimSpace(tag))] {
+			matched = append(matched, tag)
+		}
+	}
+	return matched
+}
+
+func max64(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const EventLogsCollection = "event_logs"
+
+// Entry is one event_logs document, in the shape of the existing logs
+type Entry struct {
+	// Hex id of the user performing the action, empty for the system
+	UserID     string
+	Action     string
+	EntityType string
+	EntityID   interface{}
+	Details    bson.M
+}
+
+// Log writes entry to event_logs. Pass the context of the write it audits so
+// the entry commits with it.
+func Log(ctx context.Context, db *mongo.Database, entry Entry) error {
+	var userID interface{}
+	if id, err := primitive.ObjectIDFromHex(entry.UserID); err == nil {
+		userID = id
+	}
+
+	_, err := db.Collection(EventLogsCollection).InsertOne(ctx, bson.M{
+		"user_id":     userID,
+		"action":      entry.Action,
+		"entity_type": entry.EntityType,
+		"entity_id":   entry.EntityID,
+		"details":     entry.Details,
+		"timestamp":   time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+	return nil
+}
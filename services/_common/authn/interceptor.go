@@ -0,0 +1,134 @@
+package authn
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type claimsKey struct{}
+
+// FromContext returns the claims of the access token sent with the call, nil
+// on public methods and outside the interceptors
+func FromContext(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(claimsKey{}).(*Claims)
+	return claims
+}
+
+// NewContext attaches verified claims to ctx
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ServiceRole is the role of internal callers authenticated by the service
+// token instead of a user's access token
+const ServiceRole = "service"
+
+// ServiceTokenHeader is the metadata carrying the service token
+const ServiceTokenHeader = "x-service-token"
+
+// Options configure the auth interceptors
+type Options struct {
+	// Public methods run without a token, a token sent to them is ignored.
+	// Full method names, e.g. "/auth.AuthService/Login".
+	Public []string
+	// Internal methods also accept ServiceToken in the "x-service-token"
+	// metadata, for other services calling in the background
+	Internal     []string
+	ServiceToken string
+}
+
+// UnaryServerInterceptor verifies the bearer token of the "authorization"
+// metadata and attaches its claims to the context. Every method except the
+// public ones requires a valid, unrevoked token.
+func UnaryServerInterceptor(verifier *Verifier, options Options) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if slices.Contains(options.Public, info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx, verifier, info.FullMethod, options)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamServerInterceptor(verifier *Verifier, options Options) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if slices.Contains(options.Public, info.FullMethod) {
+			return handler(srv, stream)
+		}
+		ctx, err := authenticate(stream.Context(), verifier, info.FullMethod, options)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func authenticate(ctx context.Context, verifier *Verifier, method string, options Options) (context.Context, error) {
+	token := BearerToken(ctx)
+	if token == "" {
+		if options.ServiceToken != "" && slices.Contains(options.Internal, method) && serviceToken(ctx, options.ServiceToken) {
+			return NewContext(ctx, &Claims{Roles: ServiceRole}), nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "access token is required")
+	}
+
+	claims, err := verifier.Verify(ctx, token)
+	if errors.Is(err, ErrTokenRevoked) {
+		return nil, status.Error(codes.Unauthenticated, "access token has been revoked")
+	}
+	if errors.Is(err, ErrRevocationCheck) {
+		// Không kiểm tra được danh sách thu hồi thì từ chối thay vì cho qua
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid access token")
+	}
+	return NewContext(ctx, claims), nil
+}
+
+// BearerToken reads the token of the "authorization: Bearer <token>" metadata
+func BearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, value := range md.Get("authorization") {
+		if scheme, token, ok := strings.Cut(value, " "); ok && strings.EqualFold(scheme, "bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+func serviceToken(ctx context.Context, expected string) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, value := range md.Get(ServiceTokenHeader) {
+		if subtle.ConstantTimeCompare([]byte(value), []byte(expected)) == 1 {
+			return true
+		}
+	}
+	return false
+}
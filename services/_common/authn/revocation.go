@@ -0,0 +1,120 @@
+package authn
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Revocations tracks revoked access tokens. A token is rejected when its jti
+// is denylisted or its generation is older than the user's current one.
+type Revocations interface {
+	// Revoke denylists jti for ttl, the remaining lifetime of the token
+	Revoke(ctx context.Context, jti string, ttl time.Duration) error
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+	// Generation is the current token generation of a user, 0 by default
+	Generation(ctx context.Context, userID string) (int64, error)
+	// BumpGeneration invalidates every token issued to a user so far
+	BumpGeneration(ctx context.Context, userID string) (int64, error)
+}
+
+const (
+	denylistPrefix   = "auth:denylist:"
+	generationPrefix = "auth:generation:"
+)
+
+// RedisRevocations shares the denylist between every service instance
+type RedisRevocations struct {
+	client *redis.Client
+}
+
+func NewRedisRevocations(redisAddr string, redisDB int) *RedisRevocations {
+	return &RedisRevocations{client: redis.NewClient(&redis.Options{Addr: redisAddr, DB: redisDB})}
+}
+
+func (r *RedisRevocations) Close() error {
+	return r.client.Close()
+}
+
+func (r *RedisRevocations) Revoke(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return r.client.Set(ctx, denylistPrefix+jti, 1, ttl).Err()
+}
+
+func (r *RedisRevocations) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := r.client.Exists(ctx, denylistPrefix+jti).Result()
+	return n > 0, err
+}
+
+func (r *RedisRevocations) Generation(ctx context.Context, userID string) (int64, error) {
+	value, err := r.client.Get(ctx, generationPrefix+userID).Result()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func (r *RedisRevocations) BumpGeneration(ctx context.Context, userID string) (int64, error) {
+	return r.client.Incr(ctx, generationPrefix+userID).Result()
+}
+
+// MemoryRevocations keeps the denylist in process, for development and
+// single-instance deployments without Redis
+type MemoryRevocations struct {
+	mu          sync.Mutex
+	denylist    map[string]time.Time
+	generations map[string]int64
+}
+
+func NewMemoryRevocations() *MemoryRevocations {
+	return &MemoryRevocations{
+		denylist:    map[string]time.Time{},
+		generations: map[string]int64{},
+	}
+}
+
+func (m *MemoryRevocations) Revoke(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	// Dọn các jti đã hết hạn để map không lớn dần
+	for key, expires := range m.denylist {
+		if now.After(expires) {
+			delete(m.denylist, key)
+		}
+	}
+	m.denylist[jti] = now.Add(ttl)
+	return nil
+}
+
+func (m *MemoryRevocations) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	expires, ok := m.denylist[jti]
+	return ok && time.Now().Before(expires), nil
+}
+
+func (m *MemoryRevocations) Generation(ctx context.Context, userID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.generations[userID], nil
+}
+
+func (m *MemoryRevocations) BumpGeneration(ctx context.Context, userID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.generations[userID]++
+	return m.generations[userID], nil
+}
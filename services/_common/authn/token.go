@@ -0,0 +1,163 @@
+package authn
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrRevocationCheck means the revocation store could not be reached
+	ErrRevocationCheck = errors.New("failed to check token revocation")
+)
+
+// Claims are the claims of an access token. RegisteredClaims.ID is the jti.
+type Claims struct {
+	UserID   string `json:"user_id"`
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+	Roles    string `json:"roles"`
+	// RoleID and Permissions are copied from the user's roles document at
+	// issue time, role changes apply from the next refresh
+	RoleID      string   `json:"role_id,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	// SessionID is the refresh token family the access token was issued from
+	SessionID string `json:"sid,omitempty"`
+	// Generation must not be older than the user's generation, see
+	// Revocations.BumpGeneration
+	Generation int64 `json:"gen,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// ExpiresIn is the remaining lifetime of the token
+func (c *Claims) ExpiresIn() time.Duration {
+	if c.ExpiresAt == nil {
+		return 0
+	}
+	return time.Until(c.ExpiresAt.Time)
+}
+
+// RoleNames splits Roles, a comma separated list
+func (c *Claims) RoleNames() []string {
+	var roles []string
+	for _, role := range strings.Split(c.Roles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// HasRole reports whether the token has one of roles, ignoring case
+func (c *Claims) HasRole(roles ...string) bool {
+	for _, have := range c.RoleNames() {
+		for _, want := range roles {
+			if strings.EqualFold(have, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether the token grants permission, "*" grants all
+func (c *Claims) HasPermission(permission string) bool {
+	for _, have := range c.Permissions {
+		if have == "*" || have == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// KeySet returns the public key a token was signed with, by kid
+type KeySet interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// Verifier checks the signature of access tokens and whether they were
+// revoked. It accepts either HS256 tokens signed with a shared secret or
+// RS256/EdDSA tokens signed with a key of a KeySet, never both.
+type Verifier struct {
+	secretKey   []byte
+	keys        KeySet
+	revocations Revocations
+}
+
+// NewVerifier verifies HS256 tokens signed with secretKey
+func NewVerifier(secretKey string, revocations Revocations) *Verifier {
+	return &Verifier{secretKey: []byte(secretKey), revocations: revocations}
+}
+
+// NewKeyVerifier verifies RS256 and EdDSA tokens with the public keys of
+// keys, e.g. a JWKSCache of the auth service's key set
+func NewKeyVerifier(keys KeySet, revocations Revocations) *Verifier {
+	return &Verifier{keys: keys, revocations: revocations}
+}
+
+func (v *Verifier) Revocations() Revocations {
+	return v.revocations
+}
+
+// Parse checks the signature and expiry only
+func (v *Verifier) Parse(ctx context.Context, tokenString string) (*Claims, error) {
+	methods := []string{jwt.SigningMethodHS256.Alg()}
+	if v.keys != nil {
+		methods = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+	}
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		if v.keys == nil {
+			return v.secretKey, nil
+		}
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("token has no kid")
+		}
+		return v.keys.Key(ctx, kid)
+	}, jwt.WithValidMethods(methods))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// Verify parses the token and rejects it when revoked
+func (v *Verifier) Verify(ctx context.Context, tokenString string) (*Claims, error) {
+	claims, err := v.Parse(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if v.revocations == nil {
+		return claims, nil
+	}
+
+	if claims.ID != "" {
+		revoked, err := v.revocations.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrRevocationCheck, err)
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+	generation, err := v.revocations.Generation(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRevocationCheck, err)
+	}
+	if claims.Generation < generation {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
@@ -0,0 +1,204 @@
+package defense
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const SchedulesCollection = "defense_schedules"
+
+// Trạng thái lịch bảo vệ; lịch đã hủy không chiếm phòng hay thành viên
+const (
+	StatusScheduled = "scheduled"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+)
+
+// Vai trò trong hội đồng
+const (
+	RoleChair     = "chair"
+	RoleSecretary = "secretary"
+	RoleMember    = "member"
+)
+
+// DefaultCommitteeSize is a chair, a secretary and one member
+const DefaultCommitteeSize = 3
+
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (i Interval) Overlaps(other Interval) bool {
+	return i.Start.Before(other.End) && other.Start.Before(i.End)
+}
+
+func (i Interval) Contains(other Interval) bool {
+	return !other.Start.Before(i.Start) && !other.End.After(i.End)
+}
+
+type Room struct {
+	Location string
+	Building string
+}
+
+func (r Room) String() string {
+	if r.Building == "" {
+		return r.Location
+	}
+	return r.Location + " (" + r.Building + ")"
+}
+
+func (r Room) same(other Room) bool {
+	return strings.EqualFold(r.Location, other.Location) && strings.EqualFold(r.Building, other.Building)
+}
+
+type Seat struct {
+	MemberID string
+	Role     string
+}
+
+// Schedule is one defense, either stored or proposed by the solver
+type Schedule struct {
+	// Id of the stored document, empty for proposals
+	ID           string
+	ThesisID     string
+	StudentID    string
+	SupervisorID string
+	Interval
+	Room      Room
+	Committee []Seat
+}
+
+func (s Schedule) hasMember(id string) bool {
+	for _, seat := range s.Committee {
+		if seat.MemberID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Rules are the constraints every schedule must satisfy
+type Rules struct {
+	// Minimum committee size, DefaultCommitteeSize when 0
+	CommitteeSize int
+	// Windows restrict defenses to these intervals, none means anytime
+	Windows []Interval
+	// Unavailable intervals of committee members, by member id
+	Unavailable map[string][]Interval
+}
+
+// Conflict is a violated constraint, Code is used as ErrorDetail.code
+type Conflict struct {
+	Code    string
+	Field   string
+	Message string
+}
+
+func (c Conflict) Error() string {
+	return c.Message
+}
+
+// Check returns the constraints s violates, alone and against others.
+// Schedules with the same ID as s are ignored, so an edit does not conflict
+// with its previous version.
+func Check(s Schedule, others []Schedule, rules Rules) []Conflict {
+	var conflicts []Conflict
+
+	if !s.End.After(s.Start) {
+		conflicts = append(conflicts, Conflict{"INVALID_TIME", "defense_date", "a defense must end after it starts"})
+	}
+
+	// Lịch nhập tay trước đây không có hội đồng
+	if len(s.Committee) > 0 {
+		size := rules.CommitteeSize
+		if size <= 0 {
+			size = DefaultCommitteeSize
+		}
+		conflicts = append(conflicts, checkCommittee(s, size)...)
+	}
+
+	if len(rules.Windows) > 0 {
+		inside := false
+		for _, window := range rules.Windows {
+			if window.Contains(s.Interval) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			conflicts = append(conflicts, Conflict{"OUTSIDE_WINDOW", "defense_date", "the defense is outside the defense windows"})
+		}
+	}
+
+	for _, seat := range s.Committee {
+		for _, busy := range rules.Unavailable[seat.MemberID] {
+			if busy.Overlaps(s.Interval) {
+				conflicts = append(conflicts, Conflict{"MEMBER_UNAVAILABLE", "committee", fmt.Sprintf("committee member %s is unavailable at %s", seat.MemberID, formatTime(s.Start))})
+				break
+			}
+		}
+	}
+
+	for _, other := range others {
+		if (s.ID != "" && other.ID == s.ID) || !other.Overlaps(s.Interval) {
+			continue
+		}
+		with := other.ThesisID
+		if other.ID != "" {
+			with = "schedule " + other.ID
+		}
+		if s.Room.Location != "" && s.Room.same(other.Room) {
+			conflicts = append(conflicts, Conflict{"ROOM_CONFLICT", "location", fmt.Sprintf("room %s is already booked at %s by %s", s.Room, formatTime(other.Start), with)})
+		}
+		if s.StudentID != "" && s.StudentID == other.StudentID {
+			conflicts = append(conflicts, Conflict{"STUDENT_CONFLICT", "thesis_id", fmt.Sprintf("student %s already defends at %s", s.StudentID, formatTime(other.Start))})
+		}
+		for _, seat := range s.Committee {
+			if other.hasMember(seat.MemberID) {
+				conflicts = append(conflicts, Conflict{"MEMBER_CONFLICT", "committee", fmt.Sprintf("committee member %s already sits on %s at %s", seat.MemberID, with, formatTime(other.Start))})
+			}
+		}
+	}
+	return conflicts
+}
+
+func checkCommittee(s Schedule, size int) []Conflict {
+	var conflicts []Conflict
+	if len(s.Committee) < size {
+		conflicts = append(conflicts, Conflict{"INVALID_COMMITTEE", "committee", fmt.Sprintf("the committee must have at least %d members", size)})
+	}
+
+	chairs := 0
+	seen := map[string]bool{}
+	for _, seat := range s.Committee {
+		if seat.MemberID == "" {
+			conflicts = append(conflicts, Conflict{"INVALID_COMMITTEE", "committee", "committee member_id is required"})
+			continue
+		}
+		if seat.Role == RoleChair {
+			chairs++
+			// Giảng viên hướng dẫn không được làm chủ tịch hội đồng
+			if seat.MemberID == s.SupervisorID {
+				conflicts = append(conflicts, Conflict{"CONFLICT_OF_INTEREST", "committee", "the supervisor of the thesis cannot chair its committee"})
+			}
+		}
+		if seat.MemberID == s.StudentID {
+			conflicts = append(conflicts, Conflict{"CONFLICT_OF_INTEREST", "committee", "the student cannot sit on their own committee"})
+		}
+		if seen[seat.MemberID] {
+			conflicts = append(conflicts, Conflict{"INVALID_COMMITTEE", "committee", fmt.Sprintf("committee member %s is listed twice", seat.MemberID)})
+		}
+		seen[seat.MemberID] = true
+	}
+	if chairs != 1 {
+		conflicts = append(conflicts, Conflict{"INVALID_COMMITTEE", "committee", "the committee must have exactly one chair"})
+	}
+	return conflicts
+}
+
+func formatTime(t time.Time) string {
+	return t.Format("2006-01-02 15:04")
+}
@@ -0,0 +1,119 @@
+package defense
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "thaily/proto/common"
+	"thaily/services/_common/hooks"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Lịch nhập tay được kiểm tra với cùng ràng buộc như bộ xếp lịch
+func init() {
+	hooks.Register(hooks.Hook{Name: "defense_schedules.validate", EntityType: SchedulesCollection, Event: hooks.BeforeCreate, Fn: validateSchedule})
+	hooks.Register(hooks.Hook{Name: "defense_schedules.validate", EntityType: SchedulesCollection, Event: hooks.BeforeUpdate, Fn: validateSchedule})
+}
+
+// Các field ảnh hưởng tới xung đột lịch
+var scheduleFields = []string{"thesis_id", "defense_date", "defense_time", "duration_minutes", "location", "building", "committee", "status"}
+
+func validateSchedule(ctx context.Context, op *hooks.Operation) error {
+	if err := Normalize(op.Data); err != nil {
+		return hooks.Reject(err.Error(), &pb.ErrorDetail{Code: "INVALID", Field: "defense_date", Message: err.Error()})
+	}
+
+	doc := op.Data
+	if op.Event == hooks.BeforeUpdate {
+		touched := false
+		for _, field := range scheduleFields {
+			if _, ok := op.Data[field]; ok {
+				touched = true
+				break
+			}
+		}
+		if !touched {
+			return nil
+		}
+
+		id, err := primitive.ObjectIDFromHex(op.ID)
+		if err != nil {
+			return nil
+		}
+		var current bson.M
+		err = op.DB.Collection(SchedulesCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&current)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read defense schedule: %w", err)
+		}
+		doc = map[string]interface{}{}
+		for k, v := range current {
+			doc[k] = v
+		}
+		for k, v := range op.Data {
+			doc[k] = v
+		}
+		// defense_date mới thay cho cả ngày lẫn giờ cũ; chỉ đổi defense_time thì
+		// giữ ngày cũ với giờ mới
+		if _, ok := op.Data["defense_date"]; ok {
+			doc["defense_time"] = op.Data["defense_time"]
+		} else if _, ok := op.Data["defense_time"]; ok {
+			if start, ok := ParseTime(current["defense_date"]); ok {
+				local := start.In(time.Local)
+				op.Data["defense_date"] = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+				if err := Normalize(op.Data); err != nil {
+					return hooks.Reject(err.Error(), &pb.ErrorDetail{Code: "INVALID", Field: "defense_time", Message: err.Error()})
+				}
+				doc["defense_date"] = op.Data["defense_date"]
+				doc["defense_time"] = op.Data["defense_time"]
+			}
+		}
+	}
+
+	if status, _ := doc["status"].(string); status == StatusCancelled || status == StatusCompleted {
+		return nil
+	}
+
+	schedule, err := FromDoc(doc)
+	if err != nil {
+		return hooks.Reject(err.Error(), &pb.ErrorDetail{Code: "INVALID", Field: "defense_date", Message: err.Error()})
+	}
+	schedule.ID = op.ID
+
+	store := NewStore(op.DB)
+	defenses, err := store.Defenses(ctx, []string{schedule.ThesisID})
+	if err != nil {
+		return err
+	}
+	schedule.StudentID = defenses[schedule.ThesisID].StudentID
+	schedule.SupervisorID = defenses[schedule.ThesisID].SupervisorID
+
+	others, err := store.Overlapping(ctx, schedule.Interval)
+	if err != nil {
+		return err
+	}
+	members := make([]string, len(schedule.Committee))
+	for i, seat := range schedule.Committee {
+		members[i] = seat.MemberID
+	}
+	unavailable, err := store.Unavailable(ctx, members, schedule.Interval)
+	if err != nil {
+		return err
+	}
+
+	conflicts := Check(schedule, others, Rules{Unavailable: unavailable})
+	if len(conflicts) == 0 {
+		return nil
+	}
+	details := make([]*pb.ErrorDetail, len(conflicts))
+	for i, c := range conflicts {
+		details[i] = &pb.ErrorDetail{Code: c.Code, Field: c.Field, Message: c.Message}
+	}
+	return hooks.Reject(fmt.Sprintf("defense schedule has %d conflicts: %s", len(conflicts), conflicts[0].Message), details...)
+}
@@ -0,0 +1,257 @@
+package defense
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultMaxSteps bounds the search; past it the best partial schedule found
+// is returned
+const DefaultMaxSteps = 200000
+
+// Defense is a thesis waiting for a slot
+type Defense struct {
+	ThesisID     string
+	StudentID    string
+	SupervisorID string
+}
+
+type Problem struct {
+	Defenses     []Defense
+	Windows      []Interval
+	SlotDuration time.Duration
+	Rooms        []Room
+	// Members who can sit on committees
+	Members []string
+	// Existing schedules are fixed and only block rooms, members and students
+	Existing []Schedule
+	Rules    Rules
+	// The supervisor sits on the committee as a member (never as chair)
+	SupervisorInCommittee bool
+	MaxSteps              int
+}
+
+type Unscheduled struct {
+	ThesisID string
+	Reason   string
+}
+
+type Solution struct {
+	Schedules   []Schedule
+	Unscheduled []Unscheduled
+	// Complete is false when the step limit stopped the search early
+	Complete bool
+	Steps    int
+}
+
+// Slots splits the windows into consecutive slots of duration
+func Slots(windows []Interval, duration time.Duration) []Interval {
+	var slots []Interval
+	if duration <= 0 {
+		return slots
+	}
+	for _, window := range windows {
+		for start := window.Start; !start.Add(duration).After(window.End); start = start.Add(duration) {
+			slots = append(slots, Interval{Start: start, End: start.Add(duration)})
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start) })
+	return slots
+}
+
+// Solve places every defense in a slot and room with a committee so that
+// Check finds no conflict. It is a depth-first search over (slot, room) with
+// branch and bound on the number of defenses left out; committees are chosen
+// greedily, least loaded members first, so the search stays small.
+func Solve(p Problem) Solution {
+	if p.Rules.CommitteeSize <= 0 {
+		p.Rules.CommitteeSize = DefaultCommitteeSize
+	}
+	if p.MaxSteps <= 0 {
+		p.MaxSteps = DefaultMaxSteps
+	}
+
+	s := &solver{
+		problem: p,
+		slots:   Slots(p.Windows, p.SlotDuration),
+		load:    map[string]int{},
+		best:    Solution{},
+	}
+	s.best.Unscheduled = make([]Unscheduled, len(p.Defenses))
+	for i, d := range p.Defenses {
+		s.best.Unscheduled[i] = Unscheduled{ThesisID: d.ThesisID, Reason: "no free slot, room and committee"}
+	}
+
+	// Luận văn của giảng viên có nhiều luận văn nhất được xếp trước vì ít lựa chọn hơn
+	bySupervisor := map[string]int{}
+	for _, d := range p.Defenses {
+		bySupervisor[d.SupervisorID]++
+	}
+	s.order = make([]Defense, len(p.Defenses))
+	copy(s.order, p.Defenses)
+	sort.SliceStable(s.order, func(i, j int) bool {
+		if bySupervisor[s.order[i].SupervisorID] != bySupervisor[s.order[j].SupervisorID] {
+			return bySupervisor[s.order[i].SupervisorID] > bySupervisor[s.order[j].SupervisorID]
+		}
+		return s.order[i].ThesisID < s.order[j].ThesisID
+	})
+
+	s.search(0)
+	s.best.Steps = s.steps
+	s.best.Complete = s.steps < p.MaxSteps
+	return s.best
+}
+
+type solver struct {
+	problem     Problem
+	slots       []Interval
+	order       []Defense
+	placed      []Schedule
+	skipped     []Unscheduled
+	load        map[string]int
+	steps       int
+	best        Solution
+	bestSkipped int
+	found       bool
+}
+
+func (s *solver) search(index int) bool {
+	s.steps++
+	if s.found && len(s.skipped) >= s.bestSkipped {
+		return false
+	}
+	if index == len(s.order) {
+		s.record()
+		return len(s.skipped) == 0
+	}
+	if s.steps >= s.problem.MaxSteps {
+		return true
+	}
+
+	defense := s.order[index]
+	for _, slot := range s.slots {
+		for _, room := range s.problem.Rooms {
+			schedule, ok := s.place(defense, slot, room)
+			if !ok {
+				continue
+			}
+			s.push(schedule)
+			done := s.search(index + 1)
+			s.pop()
+			if done {
+				return true
+			}
+			if s.steps >= s.problem.MaxSteps {
+				return true
+			}
+		}
+	}
+
+	s.skipped = append(s.skipped, Unscheduled{ThesisID: defense.ThesisID, Reason: "no free slot, room and committee"})
+	done := s.search(index + 1)
+	s.skipped = s.skipped[:len(s.skipped)-1]
+	return done
+}
+
+func (s *solver) record() {
+	if s.found && len(s.skipped) >= s.bestSkipped {
+		return
+	}
+	s.found = true
+	s.bestSkipped = len(s.skipped)
+	s.best.Schedules = append([]Schedule{}, s.placed...)
+	s.best.Unscheduled = append([]Unscheduled{}, s.skipped...)
+	sort.Slice(s.best.Schedules, func(i, j int) bool {
+		if !s.best.Schedules[i].Start.Equal(s.best.Schedules[j].Start) {
+			return s.best.Schedules[i].Start.Before(s.best.Schedules[j].Start)
+		}
+		return s.best.Schedules[i].Room.String() < s.best.Schedules[j].Room.String()
+	})
+}
+
+func (s *solver) push(schedule Schedule) {
+	s.placed = append(s.placed, schedule)
+	for _, seat := range schedule.Committee {
+		s.load[seat.MemberID]++
+	}
+}
+
+func (s *solver) pop() {
+	last := s.placed[len(s.placed)-1]
+	s.placed = s.placed[:len(s.placed)-1]
+	for _, seat := range last.Committee {
+		s.load[seat.MemberID]--
+	}
+}
+
+// others returns the schedules a new placement must not conflict with
+func (s *solver) others() []Schedule {
+	return append(append([]Schedule{}, s.problem.Existing...), s.placed...)
+}
+
+func (s *solver) place(d Defense, slot Interval, room Room) (Schedule, bool) {
+	candidate := Schedule{
+		ThesisID:     d.ThesisID,
+		StudentID:    d.StudentID,
+		SupervisorID: d.SupervisorID,
+		Interval:     slot,
+		Room:         room,
+	}
+	others := s.others()
+
+	free := func(member string) bool {
+		if member == "" || member == d.StudentID {
+			return false
+		}
+		for _, busy := range s.problem.Rules.Unavailable[member] {
+			if busy.Overlaps(slot) {
+				return false
+			}
+		}
+		for _, other := range others {
+			if other.Overlaps(slot) && other.hasMember(member) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var seats []Seat
+	if s.problem.SupervisorInCommittee && d.SupervisorID != "" {
+		if !free(d.SupervisorID) {
+			return Schedule{}, false
+		}
+		seats = append(seats, Seat{MemberID: d.SupervisorID, Role: RoleMember})
+	}
+
+	pool := make([]string, 0, len(s.problem.Members))
+	for _, member := range s.problem.Members {
+		if member != d.SupervisorID && free(member) {
+			pool = append(pool, member)
+		}
+	}
+	sort.SliceStable(pool, func(i, j int) bool {
+		if s.load[pool[i]] != s.load[pool[j]] {
+			return s.load[pool[i]] < s.load[pool[j]]
+		}
+		return pool[i] < pool[j]
+	})
+
+	roles := []string{RoleChair, RoleSecretary}
+	for _, member := range pool {
+		if len(seats) >= s.problem.Rules.CommitteeSize {
+			break
+		}
+		role := RoleMember
+		if len(roles) > 0 {
+			role, roles = roles[0], roles[1:]
+		}
+		seats = append(seats, Seat{MemberID: member, Role: role})
+	}
+	candidate.Committee = seats
+
+	if len(Check(candidate, others, s.problem.Rules)) > 0 {
+		return Schedule{}, false
+	}
+	return candidate, true
+}
@@ -0,0 +1,323 @@
+package defense
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Lịch bận của thành viên hội đồng: {member_id, start, end, reason}
+const AvailabilityCollection = "defense_availability"
+
+// DefaultDuration of a defense without duration_minutes
+const DefaultDuration = 60 * time.Minute
+
+type Store struct {
+	db *mongo.Database
+}
+
+func NewStore(db *mongo.Database) *Store {
+	return &Store{db: db}
+}
+
+// ParseTime accepts the forms defense_date is stored in: a BSON date, an
+// RFC 3339 string or a plain date
+func ParseTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case primitive.DateTime:
+		return v.Time(), true
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, true
+		}
+		if t, err := time.ParseInLocation("2006-01-02", v, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Normalize stores defense_date as a date holding the start of the defense
+// and defense_time as its local HH:MM, whichever form the client sent
+func Normalize(doc map[string]interface{}) error {
+	raw, ok := doc["defense_date"]
+	if !ok {
+		return nil
+	}
+	start, ok := ParseTime(raw)
+	if !ok {
+		return fmt.Errorf("defense_date %v is not a date", raw)
+	}
+
+	if clock, ok := doc["defense_time"].(string); ok && clock != "" {
+		at, err := time.Parse("15:04", clock)
+		if err != nil {
+			return fmt.Errorf("defense_time %q must be HH:MM", clock)
+		}
+		local := start.In(time.Local)
+		// Chỉ ngày được gửi lên thì lấy giờ từ defense_time
+		if local.Hour() == 0 && local.Minute() == 0 {
+			start = time.Date(local.Year(), local.Month(), local.Day(), at.Hour(), at.Minute(), 0, 0, time.Local)
+		}
+	}
+	doc["defense_date"] = start
+	doc["defense_time"] = start.In(time.Local).Format("15:04")
+	return nil
+}
+
+// FromDoc reads a defense_schedules document. Student and supervisor are
+// filled in by the caller from the thesis.
+func FromDoc(doc map[string]interface{}) (Schedule, error) {
+	when := map[string]interface{}{"defense_date": doc["defense_date"], "defense_time": doc["defense_time"]}
+	if err := Normalize(when); err != nil {
+		return Schedule{}, err
+	}
+	start, ok := when["defense_date"].(time.Time)
+	if !ok {
+		return Schedule{}, fmt.Errorf("defense_date is required")
+	}
+
+	duration := DefaultDuration
+	switch minutes := doc["duration_minutes"].(type) {
+	case int32:
+		duration = time.Duration(minutes) * time.Minute
+	case int64:
+		duration = time.Duration(minutes) * time.Minute
+	case float64:
+		duration = time.Duration(minutes) * time.Minute
+	}
+
+	schedule := Schedule{
+		ID:       idString(doc["_id"]),
+		ThesisID: idString(doc["thesis_id"]),
+		Interval: Interval{Start: start, End: start.Add(duration)},
+	}
+	schedule.Room.Location, _ = doc["location"].(string)
+	schedule.Room.Building, _ = doc["building"].(string)
+
+	if seats, ok := doc["committee"].([]interface{}); ok {
+		for _, raw := range seats {
+			if seat := seatFromValue(raw); seat != nil {
+				schedule.Committee = append(schedule.Committee, *seat)
+			}
+		}
+	}
+	if seats, ok := doc["committee"].(bson.A); ok {
+		for _, raw := range seats {
+			if seat := seatFromValue(raw); seat != nil {
+				schedule.Committee = append(schedule.Committee, *seat)
+			}
+		}
+	}
+	return schedule, nil
+}
+
+func seatFromValue(raw interface{}) *Seat {
+	var m map[string]interface{}
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		m = v
+	case bson.M:
+		m = v
+	default:
+		return nil
+	}
+	role, _ := m["role"].(string)
+	return &Seat{MemberID: idString(m["member_id"]), Role: strings.ToLower(role)}
+}
+
+// Overlapping returns the active schedules that may overlap interval
+func (s *Store) Overlapping(ctx context.Context, interval Interval) ([]Schedule, error) {
+	// defense_time có thể dời giờ trong ngày nên lấy rộng ra một ngày mỗi bên
+	cursor, err := s.db.Collection(SchedulesCollection).Find(ctx, bson.M{
+		"defense_date": bson.M{
+			"$gte": interval.Start.Add(-24 * time.Hour),
+			"$lt":  interval.End.Add(24 * time.Hour),
+		},
+		"status": bson.M{"$ne": StatusCancelled},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load defense schedules: %w", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to load defense schedules: %w", err)
+	}
+
+	schedules := make([]Schedule, 0, len(docs))
+	for _, doc := range docs {
+		schedule, err := FromDoc(doc)
+		if err != nil || !schedule.Overlaps(interval) {
+			continue
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := s.fillTheses(ctx, schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// fillTheses sets the student and supervisor of each schedule from its thesis
+func (s *Store) fillTheses(ctx context.Context, schedules []Schedule) error {
+	ids := make([]string, 0, len(schedules))
+	for _, schedule := range schedules {
+		ids = append(ids, schedule.ThesisID)
+	}
+	defenses, err := s.Defenses(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range schedules {
+		if d, ok := defenses[schedules[i].ThesisID]; ok {
+			schedules[i].StudentID = d.StudentID
+			schedules[i].SupervisorID = d.SupervisorID
+		}
+	}
+	return nil
+}
+
+// Defenses reads the student and supervisor of theses, keyed by thesis id
+func (s *Store) Defenses(ctx context.Context, thesisIDs []string) (map[string]Defense, error) {
+	ids := bson.A{}
+	for _, hex := range thesisIDs {
+		if id, err := primitive.ObjectIDFromHex(hex); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	result := map[string]Defense{}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	cursor, err := s.db.Collection("theses").Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load theses: %w", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to load theses: %w", err)
+	}
+	for _, doc := range docs {
+		d := Defense{
+			ThesisID:     idString(doc["_id"]),
+			StudentID:    idString(doc["student_id"]),
+			SupervisorID: idString(doc["supervisor_id"]),
+		}
+		result[d.ThesisID] = d
+	}
+	return result, nil
+}
+
+// Pending returns the theses of a term without an active defense schedule
+func (s *Store) Pending(ctx context.Context, academicYear string, semester int) ([]Defense, error) {
+	scheduled, err := s.db.Collection(SchedulesCollection).Distinct(ctx, "thesis_id", bson.M{"status": bson.M{"$ne": StatusCancelled}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load defense schedules: %w", err)
+	}
+	skip := map[string]bool{}
+	for _, id := range scheduled {
+		skip[idString(id)] = true
+	}
+
+	cursor, err := s.db.Collection("theses").Find(ctx, bson.M{"academic_year": academicYear, "semester": semester})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load theses: %w", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to load theses: %w", err)
+	}
+
+	var defenses []Defense
+	for _, doc := range docs {
+		id := idString(doc["_id"])
+		if skip[id] {
+			continue
+		}
+		defenses = append(defenses, Defense{
+			ThesisID:     id,
+			StudentID:    idString(doc["student_id"]),
+			SupervisorID: idString(doc["supervisor_id"]),
+		})
+	}
+	return defenses, nil
+}
+
+// Members returns the ids of the active teachers who can sit on committees
+func (s *Store) Members(ctx context.Context) ([]string, error) {
+	cursor, err := s.db.Collection("users").Find(ctx, bson.M{
+		"status": bson.M{"$ne": "inactive"},
+		"$or": bson.A{
+			bson.M{"role": "teacher"},
+			bson.M{"code": bson.M{"$regex": "^GV"}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load committee members: %w", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to load committee members: %w", err)
+	}
+	members := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		members = append(members, idString(doc["_id"]))
+	}
+	return members, nil
+}
+
+// Unavailable returns the busy intervals of members overlapping interval
+func (s *Store) Unavailable(ctx context.Context, members []string, interval Interval) (map[string][]Interval, error) {
+	ids := bson.A{}
+	for _, member := range members {
+		ids = append(ids, member)
+		if id, err := primitive.ObjectIDFromHex(member); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	result := map[string][]Interval{}
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	cursor, err := s.db.Collection(AvailabilityCollection).Find(ctx, bson.M{
+		"member_id": bson.M{"$in": ids},
+		"start":     bson.M{"$lt": interval.End},
+		"end":       bson.M{"$gt": interval.Start},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load availability: %w", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to load availability: %w", err)
+	}
+	for _, doc := range docs {
+		start, ok1 := ParseTime(doc["start"])
+		end, ok2 := ParseTime(doc["end"])
+		if !ok1 || !ok2 {
+			continue
+		}
+		member := idString(doc["member_id"])
+		result[member] = append(result[member], Interval{Start: start, End: end})
+	}
+	return result, nil
+}
+
+func idString(v interface{}) string {
+	switch id := v.(type) {
+	case primitive.ObjectID:
+		return id.Hex()
+	case string:
+		return id
+	}
+	return ""
+}
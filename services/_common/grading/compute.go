@@ -0,0 +1,191 @@
+package grading
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Input is one score going into the final grade. Reviewer scores have no
+// criterion, the overall score is used as their total.
+type Input struct {
+	ScoreID    string  `bson:"score_id" json:"score_id"`
+	Collection string  `bson:"collection" json:"collection"`
+	ScorerID   string  `bson:"scorer_id" json:"scorer_id"`
+	Source     string  `bson:"source" json:"source"`
+	Criterion  string  `bson:"criterion,omitempty" json:"criterion,omitempty"`
+	Value      float64 `bson:"value" json:"value"`
+}
+
+type ScorerResult struct {
+	ScorerID string             `bson:"scorer_id" json:"scorer_id"`
+	Source   string             `bson:"source" json:"source"`
+	Total    float64            `bson:"total" json:"total"`
+	Criteria map[string]float64 `bson:"criteria,omitempty" json:"criteria,omitempty"`
+	// Deviation from the median total of the source
+	Deviation float64 `bson:"deviation" json:"deviation"`
+	Outlier   bool    `bson:"outlier" json:"outlier"`
+	Excluded  bool    `bson:"excluded" json:"excluded"`
+}
+
+type SourceResult struct {
+	Source  string  `bson:"source" json:"source"`
+	Weight  float64 `bson:"weight" json:"weight"`
+	Average float64 `bson:"average" json:"average"`
+	Scorers int     `bson:"scorers" json:"scorers"`
+}
+
+type Result struct {
+	Raw      float64        `bson:"raw_grade" json:"raw_grade"`
+	Final    float64        `bson:"final_grade" json:"final_grade"`
+	Sources  []SourceResult `bson:"sources" json:"sources"`
+	Scorers  []ScorerResult `bson:"scorers" json:"scorers"`
+	Warnings []string       `bson:"warnings" json:"warnings"`
+}
+
+// InputError is a score the rubric cannot accept
+type InputError struct {
+	ScoreID string
+	Code    string
+	Message string
+}
+
+// Outliers are only flagged with at least this many scorers in a source
+const minScorersForOutliers = 3
+
+// Compute aggregates the inputs with the rubric: criteria are weighted into a
+// total per scorer, scorers far from the median of their source are flagged,
+// source averages are weighted into the final grade which is then rounded.
+func Compute(rubric Rubric, inputs []Input) (Result, []InputError) {
+	var errs []InputError
+	result := Result{Warnings: []string{}}
+	scale := rubric.scale()
+
+	type key struct{ source, scorer string }
+	criteria := map[key]map[string]float64{}
+	overall := map[key]float64{}
+	var order []key
+	seen := map[key]bool{}
+	for _, in := range inputs {
+		k := key{in.Source, in.ScorerID}
+		if !seen[k] {
+			seen[k] = true
+			order = append(order, k)
+		}
+
+		if in.Criterion == "" {
+			if in.Value < 0 || in.Value > scale {
+				errs = append(errs, InputError{in.ScoreID, "SCORE_OUT_OF_RANGE", fmt.Sprintf("score %v of %s is outside 0..%v", in.Value, in.ScorerID, scale)})
+				continue
+			}
+			overall[k] = in.Value
+			continue
+		}
+
+		c, ok := rubric.criterion(in.Criterion)
+		if !ok {
+			errs = append(errs, InputError{in.ScoreID, "UNKNOWN_CRITERION", fmt.Sprintf("criterion %s is not in the rubric", in.Criterion)})
+			continue
+		}
+		if in.Value < c.Min || in.Value > c.Max {
+			errs = append(errs, InputError{in.ScoreID, "SCORE_OUT_OF_RANGE", fmt.Sprintf("%s score %v of %s is outside %v..%v", c.Key, in.Value, in.ScorerID, c.Min, c.Max)})
+			continue
+		}
+		if criteria[k] == nil {
+			criteria[k] = map[string]float64{}
+		}
+		if _, dup := criteria[k][c.Key]; dup {
+			errs = append(errs, InputError{in.ScoreID, "DUPLICATE_SCORE", fmt.Sprintf("%s scored %s twice", in.ScorerID, c.Key)})
+			continue
+		}
+		criteria[k][c.Key] = in.Value
+	}
+	if len(errs) > 0 {
+		return result, errs
+	}
+
+	bySource := map[string][]int{}
+	for _, k := range order {
+		scorer := ScorerResult{ScorerID: k.scorer, Source: k.source}
+		if scores, ok := criteria[k]; ok {
+			weighted, weights := 0.0, 0.0
+			for _, c := range rubric.Criteria {
+				value, ok := scores[c.Key]
+				if !ok {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("%s %s did not score %s", k.source, k.scorer, c.Key))
+					continue
+				}
+				weighted += c.Weight * (value - c.Min) / (c.Max - c.Min)
+				weights += c.Weight
+			}
+			scorer.Criteria = scores
+			scorer.Total = clean(weighted / weights * scale)
+		} else {
+			scorer.Total = overall[k]
+		}
+		bySource[k.source] = append(bySource[k.source], len(result.Scorers))
+		result.Scorers = append(result.Scorers, scorer)
+	}
+
+	weighted, weights := 0.0, 0.0
+	for _, source := range Sources {
+		weight := rubric.SourceWeights[source]
+		indexes := bySource[source]
+		if weight <= 0 {
+			if len(indexes) > 0 {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s scores are ignored, the source has no weight", source))
+			}
+			continue
+		}
+		if len(indexes) == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("no %s scores, its weight is spread over the other sources", source))
+			continue
+		}
+
+		totals := make([]float64, len(indexes))
+		for i, index := range indexes {
+			totals[i] = result.Scorers[index].Total
+		}
+		mid := median(totals)
+		sum, count := 0.0, 0
+		for _, index := range indexes {
+			scorer := &result.Scorers[index]
+			scorer.Deviation = clean(scorer.Total - mid)
+			if len(indexes) >= minScorersForOutliers && rubric.OutlierThreshold > 0 && math.Abs(scorer.Deviation) > rubric.OutlierThreshold {
+				scorer.Outlier = true
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s %s is %.2f from the median", source, scorer.ScorerID, scorer.Deviation))
+				if rubric.ExcludeOutliers {
+					scorer.Excluded = true
+					continue
+				}
+			}
+			sum += scorer.Total
+			count++
+		}
+		if count == 0 {
+			continue
+		}
+
+		average := sum / float64(count)
+		result.Sources = append(result.Sources, SourceResult{Source: source, Weight: weight, Average: clean(average), Scorers: count})
+		weighted += weight * average
+		weights += weight
+	}
+	if weights == 0 {
+		return result, []InputError{{Code: "NO_SCORES", Message: "there are no scores to compute a grade from"}}
+	}
+
+	result.Raw = clean(weighted / weights)
+	result.Final = rubric.Round(result.Raw)
+	return result, nil
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,178 @@
+package grading
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	pb "thaily/proto/common"
+	"thaily/services/_common/hooks"
+	"thaily/services/_common/workflow"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Điểm đã công bố bị khóa: không sửa điểm tổng kết hay điểm đầu vào, chỉ
+// giáo vụ được mở lại (status về draft) khi phúc khảo
+func init() {
+	hooks.Register(hooks.Hook{Name: "grading_rubrics.validate", EntityType: RubricsCollection, Event: hooks.BeforeCreate, Fn: validateRubric})
+	hooks.Register(hooks.Hook{Name: "grading_rubrics.validate", EntityType: RubricsCollection, Event: hooks.BeforeUpdate, Fn: validateRubric})
+
+	hooks.Register(hooks.Hook{Name: "final_grades.lock", EntityType: GradesCollection, Event: hooks.BeforeUpdate, Fn: lockGrade})
+	hooks.Register(hooks.Hook{Name: "final_grades.lock", EntityType: GradesCollection, Event: hooks.BeforeDelete, Fn: lockGrade})
+
+	for _, entityType := range []string{"defense_scores", "reviews"} {
+		for _, event := range []hooks.Event{hooks.BeforeCreate, hooks.BeforeUpdate, hooks.BeforeDelete} {
+			hooks.Register(hooks.Hook{Name: entityType + ".grade_lock", EntityType: entityType, Event: event, Fn: lockScores})
+		}
+	}
+}
+
+// Các field được đổi khi mở lại điểm đã công bố
+var reopenFields = map[string]bool{"status": true, "reopen_reason": true, "updatedAt": true}
+
+func validateRubric(ctx context.Context, op *hooks.Operation) error {
+	doc := bson.M{}
+	if op.Event == hooks.BeforeUpdate {
+		current, err := currentDoc(ctx, op)
+		if err != nil {
+			return err
+		}
+		for k, v := range current {
+			doc[k] = v
+		}
+	}
+	for k, v := range op.Data {
+		doc[k] = v
+	}
+
+	var rubric Rubric
+	raw, err := bson.Marshal(doc)
+	if err == nil {
+		err = bson.Unmarshal(raw, &rubric)
+	}
+	if err != nil {
+		return hooks.Reject(fmt.Sprintf("invalid rubric: %v", err), &pb.ErrorDetail{Code: "INVALID", Message: err.Error()})
+	}
+
+	problems := rubric.Validate()
+	if len(problems) == 0 {
+		return nil
+	}
+	fields := make([]string, 0, len(problems))
+	for field := range problems {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	details := make([]*pb.ErrorDetail, len(fields))
+	for i, field := range fields {
+		details[i] = &pb.ErrorDetail{Code: "INVALID", Field: field, Message: problems[field]}
+	}
+	return hooks.Reject(fmt.Sprintf("invalid rubric: %s", details[0].Message), details...)
+}
+
+func lockGrade(ctx context.Context, op *hooks.Operation) error {
+	current, err := currentDoc(ctx, op)
+	if err != nil || current == nil || current["status"] != StatusPublished {
+		return err
+	}
+
+	// Quyền mở lại lấy từ token đã xác thực, không tin meta do client gửi
+	actor, _ := workflow.ActorFromContext(ctx)
+	if op.Event == hooks.BeforeUpdate && op.Data["status"] == StatusDraft && actor.IsStaff() {
+		reopen := true
+		for field := range op.Data {
+			reopen = reopen && reopenFields[field]
+		}
+		if reopen {
+			return nil
+		}
+	}
+	return hooks.Reject("the final grade is published and locked", &pb.ErrorDetail{
+		Code:    "GRADE_LOCKED",
+		Field:   "status",
+		Message: "only staff can reopen a published grade, by setting status to draft",
+	})
+}
+
+// lockScores refuses changes to the scores of a thesis whose grade is published
+func lockScores(ctx context.Context, op *hooks.Operation) error {
+	doc := op.Data
+	if op.Event != hooks.BeforeCreate {
+		current, err := currentDoc(ctx, op)
+		if err != nil || current == nil {
+			return err
+		}
+		doc = current
+	}
+
+	var thesisID interface{}
+	var err error
+	switch op.EntityType {
+	case "defense_scores":
+		thesisID, err = parentField(ctx, op.DB, "defense_schedules", doc["defense_schedule_id"], "thesis_id")
+	case "reviews":
+		thesisID, err = parentField(ctx, op.DB, "submissions", doc["submission_id"], "thesis_id")
+	}
+	if err != nil {
+		return err
+	}
+	id, ok := objectID(thesisID)
+	if !ok {
+		return nil
+	}
+
+	published, err := NewStore(op.DB).Published(ctx, id)
+	if err != nil || !published {
+		return err
+	}
+	return hooks.Reject("the final grade of this thesis is published, its scores are locked", &pb.ErrorDetail{
+		Code:    "GRADE_LOCKED",
+		Message: fmt.Sprintf("the final grade of thesis %s is published", id.Hex()),
+	})
+}
+
+func currentDoc(ctx context.Context, op *hooks.Operation) (bson.M, error) {
+	id, err := primitive.ObjectIDFromHex(op.ID)
+	if err != nil {
+		return nil, nil
+	}
+	var doc bson.M
+	err = op.DB.Collection(op.EntityType).FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", op.EntityType, err)
+	}
+	return doc, nil
+}
+
+func parentField(ctx context.Context, db *mongo.Database, collection string, ref interface{}, field string) (interface{}, error) {
+	id, ok := objectID(ref)
+	if !ok {
+		return nil, nil
+	}
+	var doc bson.M
+	err := db.Collection(collection).FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", collection, err)
+	}
+	return doc[field], nil
+}
+
+func objectID(v interface{}) (primitive.ObjectID, bool) {
+	switch id := v.(type) {
+	case primitive.ObjectID:
+		return id, true
+	case string:
+		oid, err := primitive.ObjectIDFromHex(id)
+		return oid, err == nil
+	}
+	return primitive.NilObjectID, false
+}
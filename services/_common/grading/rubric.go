@@ -0,0 +1,173 @@
+package grading
+
+import (
+	"fmt"
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const RubricsCollection = "grading_rubrics"
+
+// Nguồn điểm của một người chấm
+const (
+	SourceCommittee  = "committee"
+	SourceReviewer   = "reviewer"
+	SourceSupervisor = "supervisor"
+)
+
+var Sources = []string{SourceCommittee, SourceReviewer, SourceSupervisor}
+
+// Cách làm tròn điểm cuối cùng theo bước Rounding.Step
+const (
+	RoundHalfUp = "half_up"
+	RoundDown   = "down"
+	RoundUp     = "up"
+)
+
+type Criterion struct {
+	Key    string  `bson:"key" json:"key"`
+	Name   string  `bson:"name" json:"name"`
+	Weight float64 `bson:"weight" json:"weight"`
+	Min    float64 `bson:"min" json:"min"`
+	Max    float64 `bson:"max" json:"max"`
+}
+
+type Rounding struct {
+	// Step 0 keeps the computed value
+	Step float64 `bson:"step" json:"step"`
+	Mode string  `bson:"mode" json:"mode"`
+}
+
+// Rubric is a grading_rubrics document. The rubric of a thesis is the one of
+// its major, or the one without major.
+type Rubric struct {
+	ID    primitive.ObjectID `bson:"_id,omitempty" json:"_id,omitempty"`
+	Major string             `bson:"major" json:"major"`
+	Name  string             `bson:"name" json:"name"`
+	// Scale of scorer totals and of the final grade, 10 when 0
+	Scale         float64            `bson:"scale" json:"scale"`
+	Criteria      []Criterion        `bson:"criteria" json:"criteria"`
+	SourceWeights map[string]float64 `bson:"source_weights" json:"source_weights"`
+	Rounding      Rounding           `bson:"rounding" json:"rounding"`
+	// Scorers further than this from the median of their source are outliers
+	OutlierThreshold float64 `bson:"outlier_threshold" json:"outlier_threshold"`
+	ExcludeOutliers  bool    `bson:"exclude_outliers" json:"exclude_outliers"`
+}
+
+// DefaultRubric is used when no rubric is configured, with the criteria of
+// the existing defense_scores
+func DefaultRubric() Rubric {
+	return Rubric{
+		Name:  "default",
+		Scale: 10,
+		Criteria: []Criterion{
+			{Key: "presentation", Name: "Trình bày", Weight: 0.3, Min: 0, Max: 10},
+			{Key: "content", Name: "Nội dung", Weight: 0.5, Min: 0, Max: 10},
+			{Key: "qa_session", Name: "Trả lời câu hỏi", Weight: 0.2, Min: 0, Max: 10},
+		},
+		SourceWeights: map[string]float64{
+			SourceCommittee:  0.6,
+			SourceReviewer:   0.2,
+			SourceSupervisor: 0.2,
+		},
+		Rounding:         Rounding{Step: 0.1, Mode: RoundHalfUp},
+		OutlierThreshold: 2,
+	}
+}
+
+func (r Rubric) scale() float64 {
+	if r.Scale <= 0 {
+		return 10
+	}
+	return r.Scale
+}
+
+func (r Rubric) criterion(key string) (Criterion, bool) {
+	for _, c := range r.Criteria {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return Criterion{}, false
+}
+
+// Validate returns the problems of a rubric, keyed by field
+func (r Rubric) Validate() map[string]string {
+	problems := map[string]string{}
+	if r.Scale < 0 {
+		problems["scale"] = "scale must be positive"
+	}
+	if len(r.Criteria) == 0 {
+		problems["criteria"] = "at least one criterion is required"
+	}
+	seen := map[string]bool{}
+	for i, c := range r.Criteria {
+		field := fmt.Sprintf("criteria[%d]", i)
+		switch {
+		case c.Key == "":
+			problems[field+".key"] = "key is required"
+		case seen[c.Key]:
+			problems[field+".key"] = fmt.Sprintf("criterion %s is listed twice", c.Key)
+		case c.Weight <= 0:
+			problems[field+".weight"] = "weight must be positive"
+		case c.Max <= c.Min:
+			problems[field+".max"] = "max must be greater than min"
+		}
+		seen[c.Key] = true
+	}
+
+	total := 0.0
+	for source, weight := range r.SourceWeights {
+		known := false
+		for _, s := range Sources {
+			known = known || s == source
+		}
+		if !known {
+			problems["source_weights."+source] = fmt.Sprintf("unknown source, expected one of %v", Sources)
+		}
+		if weight < 0 {
+			problems["source_weights."+source] = "weight cannot be negative"
+		}
+		total += weight
+	}
+	if total <= 0 {
+		problems["source_weights"] = "at least one source needs a positive weight"
+	}
+
+	if r.Rounding.Step < 0 {
+		problems["rounding.step"] = "step cannot be negative"
+	}
+	switch r.Rounding.Mode {
+	case "", RoundHalfUp, RoundDown, RoundUp:
+	default:
+		problems["rounding.mode"] = fmt.Sprintf("mode must be %s, %s or %s", RoundHalfUp, RoundDown, RoundUp)
+	}
+	if r.OutlierThreshold < 0 {
+		problems["outlier_threshold"] = "outlier_threshold cannot be negative"
+	}
+	return problems
+}
+
+// Round applies the rounding rule of the rubric
+func (r Rubric) Round(value float64) float64 {
+	step := r.Rounding.Step
+	if step <= 0 {
+		return clean(value)
+	}
+	// Sai số dấu phẩy động: 8.25/0.05 có thể ra 164.99999
+	q := value / step
+	switch r.Rounding.Mode {
+	case RoundDown:
+		q = math.Floor(q + 1e-9)
+	case RoundUp:
+		q = math.Ceil(q - 1e-9)
+	default:
+		q = math.Floor(q + 0.5 + 1e-9)
+	}
+	return clean(q * step)
+}
+
+func clean(value float64) float64 {
+	return math.Round(value*1e6) / 1e6
+}
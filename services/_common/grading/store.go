@@ -0,0 +1,217 @@
+package grading
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Một điểm tổng kết cho mỗi luận văn
+	GradesCollection = "final_grades"
+	// Mỗi lần tính điểm được lưu lại cùng dữ liệu đầu vào để phúc khảo
+	ComputationsCollection = "grade_computations"
+)
+
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+)
+
+// Thesis is what grading needs to know about a thesis
+type Thesis struct {
+	ID           primitive.ObjectID
+	Major        string
+	SupervisorID string
+}
+
+type Store struct {
+	db *mongo.Database
+}
+
+func NewStore(db *mongo.Database) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) Thesis(ctx context.Context, id primitive.ObjectID) (*Thesis, error) {
+	var doc bson.M
+	err := s.db.Collection("theses").FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load thesis: %w", err)
+	}
+	major, _ := doc["major"].(string)
+	return &Thesis{ID: id, Major: major, SupervisorID: idString(doc["supervisor_id"])}, nil
+}
+
+// Rubric returns the active rubric of major, falling back to the rubric
+// without major and then to DefaultRubric
+func (s *Store) Rubric(ctx context.Context, major string) (Rubric, error) {
+	for _, filter := range []bson.M{
+		{"major": major},
+		{"major": bson.M{"$in": bson.A{"", nil}}},
+	} {
+		filter["is_active"] = bson.M{"$ne": false}
+		var rubric Rubric
+		err := s.db.Collection(RubricsCollection).FindOne(ctx, filter, options.FindOne().SetSort(bson.M{"updatedAt": -1})).Decode(&rubric)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return Rubric{}, fmt.Errorf("failed to load grading rubric: %w", err)
+		}
+		return rubric, nil
+	}
+	return DefaultRubric(), nil
+}
+
+// Inputs collects the scores of a thesis: defense_scores of its schedules
+// (the supervisor's as supervisor, the others as committee) and the scores of
+// the reviews of its submissions
+func (s *Store) Inputs(ctx context.Context, thesis *Thesis) ([]Input, error) {
+	ref := bson.A{thesis.ID, thesis.ID.Hex()}
+	schedules, err := s.ids(ctx, "defense_schedules", bson.M{"thesis_id": bson.M{"$in": ref}, "status": bson.M{"$ne": "cancelled"}})
+	if err != nil {
+		return nil, err
+	}
+	var inputs []Input
+	if len(schedules) > 0 {
+		docs, err := s.find(ctx, "defense_scores", bson.M{"defense_schedule_id": bson.M{"$in": schedules}})
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			in := Input{
+				ScoreID:    idString(doc["_id"]),
+				Collection: "defense_scores",
+				ScorerID:   idString(doc["scorer_id"]),
+				Source:     SourceCommittee,
+				Value:      number(doc["score"]),
+			}
+			// Dữ liệu mẫu dùng "criteria", client mới có thể gửi "criterion"
+			if in.Criterion, _ = doc["criterion"].(string); in.Criterion == "" {
+				in.Criterion, _ = doc["criteria"].(string)
+			}
+			if in.ScorerID == thesis.SupervisorID {
+				in.Source = SourceSupervisor
+			}
+			inputs = append(inputs, in)
+		}
+	}
+
+	submissions, err := s.ids(ctx, "submissions", bson.M{"thesis_id": bson.M{"$in": ref}})
+	if err != nil {
+		return nil, err
+	}
+	if len(submissions) > 0 {
+		docs, err := s.find(ctx, "reviews", bson.M{"submission_id": bson.M{"$in": submissions}, "score": bson.M{"$type": "number"}})
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			inputs = append(inputs, Input{
+				ScoreID:    idString(doc["_id"]),
+				Collection: "reviews",
+				ScorerID:   idString(doc["reviewer_id"]),
+				Source:     SourceReviewer,
+				Value:      number(doc["score"]),
+			})
+		}
+	}
+	return inputs, nil
+}
+
+// Grade returns the final_grades document of a thesis, nil when not computed
+func (s *Store) Grade(ctx context.Context, thesisID primitive.ObjectID) (bson.M, error) {
+	var doc bson.M
+	err := s.db.Collection(GradesCollection).FindOne(ctx, bson.M{"thesis_id": bson.M{"$in": bson.A{thesisID, thesisID.Hex()}}}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load final grade: %w", err)
+	}
+	return doc, nil
+}
+
+// Published reports whether the grade of a thesis is published and locked
+func (s *Store) Published(ctx context.Context, thesisID primitive.ObjectID) (bool, error) {
+	grade, err := s.Grade(ctx, thesisID)
+	if err != nil || grade == nil {
+		return false, err
+	}
+	return grade["status"] == StatusPublished, nil
+}
+
+// ids returns the _id and its hex form of the matching documents, so that
+// references stored either way match
+func (s *Store) ids(ctx context.Context, collection string, filter bson.M) (bson.A, error) {
+	values, err := s.db.Collection(collection).Distinct(ctx, "_id", filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", collection, err)
+	}
+	ids := bson.A{}
+	for _, value := range values {
+		ids = append(ids, value)
+		if id, ok := value.(primitive.ObjectID); ok {
+			ids = append(ids, id.Hex())
+		}
+	}
+	return ids, nil
+}
+
+func (s *Store) find(ctx context.Context, collection string, filter bson.M) ([]bson.M, error) {
+	cursor, err := s.db.Collection(collection).Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", collection, err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", collection, err)
+	}
+	return docs, nil
+}
+
+func number(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	}
+	return 0
+}
+
+func idString(v interface{}) string {
+	switch id := v.(type) {
+	case primitive.ObjectID:
+		return id.Hex()
+	case string:
+		return id
+	}
+	return ""
+}
+
+// Plain converts the result types to bson.M and bson.A, the values stored
+// documents are returned as
+func Plain(v interface{}) interface{} {
+	raw, err := bson.Marshal(bson.M{"v": v})
+	if err != nil {
+		return nil
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil
+	}
+	return doc["v"]
+}
@@ -0,0 +1,185 @@
+package helper
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Các nhóm ký tự tiếng Việt có dấu theo chữ cái gốc
+var vietnameseGroups = map[rune]string{
+	'a': "àáảãạăằắẳẵặâầấẩẫậ",
+	'e': "èéẻẽẹêềếểễệ",
+	'i': "ìíỉĩị",
+	'o': "òóỏõọôồốổỗộơờớởỡợ",
+	'u': "ùúủũụưừứửữự",
+	'y': "ỳýỷỹỵ",
+	'd': "đ",
+}
+
+var (
+	foldTable   = map[rune]rune{}
+	accentClass = map[rune]string{}
+)
+
+func init() {
+	for base, variants := range vietnameseGroups {
+		upper := unicode.ToUpper(base)
+		for _, r := range variants {
+			foldTable[r] = base
+			foldTable[unicode.ToUpper(r)] = upper
+		}
+		accentClass[base] = "[" + string(base) + string(upper) + variants + strings.ToUpper(variants) + "]"
+	}
+}
+
+// FoldVietnamese removes Vietnamese diacritics rune by rune, so indexes in
+// the folded string line up with the original one
+func FoldVietnamese(s string) string {
+	return strings.Map(foldRune, s)
+}
+
+func foldRune(r rune) rune {
+	if base, ok := foldTable[r]; ok {
+		return base
+	}
+	return r
+}
+
+// EscapeRegex quotes every regex metacharacter so user input is matched literally
+func EscapeRegex(s string) string {
+	return regexp.QuoteMeta(s)
+}
+
+// AccentInsensitivePattern builds an escaped regex matching s with or without
+// Vietnamese diacritics, e.g. "luan" matches "luận"
+func AccentInsensitivePattern(s string) string {
+	var b strings.Builder
+	for _, r := range FoldVietnamese(s) {
+		if class, ok := accentClass[unicode.ToLower(r)]; ok {
+			b.WriteString(class)
+			continue
+		}
+		b.WriteString(regexp.QuoteMeta(string(r)))
+	}
+	return b.String()
+}
+
+// SearchTerms splits a search query into non-empty words
+func SearchTerms(query string) []string {
+	return strings.Fields(query)
+}
+
+// Highlight returns snippets of text around occurrences of terms, with each
+// match wrapped in <em></em>. Matching is case-insensitive and, unless
+// accentSensitive is set, ignores Vietnamese diacritics.
+func Highlight(text string, terms []string, accentSensitive bool, maxSnippets int) []string {
+	const context = 40
+
+	if text == "" || len(terms) == 0 {
+		return nil
+	}
+
+	normalize := func(s string) []rune {
+		if !accentSensitive {
+			s = FoldVietnamese(s)
+		}
+		return []rune(strings.ToLower(s))
+	}
+
+	source := []rune(text)
+	haystack := normalize(text)
+	if len(haystack) != len(source) {
+		// ToLower changed the rune count, fall back to the raw text
+		haystack = []rune(text)
+	}
+
+	type span struct{ start, end int }
+	var spans []span
+	for _, term := range terms {
+		needle := normalize(term)
+		if len(needle) == 0 {
+			continue
+		}
+		for i := 0; i+len(needle) <= len(haystack); i++ {
+			if string(haystack[i:i+len(needle)]) == string(needle) {
+				spans = append(spans, span{i, i + len(needle)})
+				i += len(needle) - 1
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	// Sort and merge overlapping matches
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	merged := []span{spans[0]}
+	for _, sp := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if sp.start <= last.end {
+			if sp.end > last.end {
+				last.end = sp.end
+			}
+			continue
+		}
+		merged = append(merged, sp)
+	}
+
+	// Group matches that fall into the same snippet window
+	var snippets []string
+	for i := 0; i < len(merged) && len(snippets) < maxSnippets; {
+		from := max(merged[i].start-context, 0)
+		to := min(merged[i].end+context, len(source))
+
+		j := i
+		for j+1 < len(merged) && merged[j+1].end <= to {
+			j++
+		}
+
+		var b strings.Builder
+		if from > 0 {
+			b.WriteString("…")
+		}
+		pos := from
+		for _, sp := range merged[i : j+1] {
+			b.WriteString(string(source[pos:sp.start]))
+			b.WriteString("<em>")
+			b.WriteString(string(source[sp.start:sp.end]))
+			b.WriteString("</em>")
+			pos = sp.end
+		}
+		b.WriteString(string(source[pos:to]))
+		if to < len(source) {
+			b.WriteString("…")
+		}
+		snippets = append(snippets, b.String())
+		i = j + 1
+	}
+	return snippets
+}
+
+// LookupPath reads a dotted field path such as "student_info.name" from a document
+func LookupPath(doc map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		var m map[string]interface{}
+		switch v := current.(type) {
+		case map[string]interface{}:
+			m = v
+		case bson.M:
+			m = v
+		default:
+			return nil, false
+		}
+		next, ok := m[part]
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
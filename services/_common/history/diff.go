@@ -0,0 +1,72 @@
+package history
+
+import (
+	"reflect"
+	"sort"
+
+	"thaily/services/_common/helper"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Change is one field that differs between two versions. Nested documents
+// are compared field by field with dotted paths, arrays as a whole.
+type Change struct {
+	Path string
+	Kind string
+	From interface{}
+	To   interface{}
+}
+
+// Diff compares two documents after converting them to their JSON form, so
+// an ObjectID and its hex string are equal
+func Diff(from, to bson.M) ([]Change, error) {
+	fromStruct, err := helper.DocToStruct(from)
+	if err != nil {
+		return nil, err
+	}
+	toStruct, err := helper.DocToStruct(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []Change{}
+	diffMaps("", fromStruct.AsMap(), toStruct.AsMap(), &changes)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
+
+func diffMaps(prefix string, from, to map[string]interface{}, changes *[]Change) {
+	for key, fromValue := range from {
+		path := prefix + key
+		toValue, ok := to[key]
+		if !ok {
+			*changes = append(*changes, Change{Path: path, Kind: ChangeRemoved, From: fromValue})
+			continue
+		}
+
+		fromMap, fromIsMap := fromValue.(map[string]interface{})
+		toMap, toIsMap := toValue.(map[string]interface{})
+		if fromIsMap && toIsMap {
+			diffMaps(path+".", fromMap, toMap, changes)
+			continue
+		}
+		if !reflect.DeepEqual(fromValue, toValue) {
+			*changes = append(*changes, Change{Path: path, Kind: ChangeChanged, From: fromValue, To: toValue})
+		}
+	}
+
+	for key, toValue := range to {
+		if _, ok := from[key]; !ok {
+			*changes = append(*changes, Change{Path: prefix + key, Kind: ChangeAdded, To: toValue})
+		}
+	}
+}
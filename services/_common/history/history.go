@@ -0,0 +1,145 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"thaily/services/_common/schema"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Operation that replaced a stored version
+const (
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+	OperationRevert = "revert"
+)
+
+var ErrVersionNotFound = errors.New("version not found")
+
+// Version is a previous state of an entity. Versions are numbered from 1 for
+// each entity, in the order they were replaced.
+type Version struct {
+	ID        primitive.ObjectID     `bson:"_id,omitempty"`
+	EntityID  primitive.ObjectID     `bson:"entity_id"`
+	Version   int64                  `bson:"version"`
+	Operation string                 `bson:"operation"`
+	Data      bson.M                 `bson:"data,omitempty"`
+	Meta      map[string]interface{} `bson:"meta,omitempty"`
+	// Version the entity was reverted to, for OperationRevert
+	RevertedTo int64     `bson:"reverted_to,omitempty"`
+	CreatedAt  time.Time `bson:"createdAt"`
+}
+
+type Store struct {
+	db *mongo.Database
+}
+
+func NewStore(db *mongo.Database) *Store {
+	return &Store{db: db}
+}
+
+// EnsureIndexes creates the (entity_id, version) index of every history collection
+func (s *Store) EnsureIndexes(ctx context.Context) error {
+	for entityType := range schema.HistoryEntities {
+		_, err := s.db.Collection(schema.HistoryCollection(entityType)).Indexes().CreateOne(ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "entity_id", Value: 1}, {Key: "version", Value: -1}},
+			Options: options.Index().SetName("entity_version").SetUnique(true),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create history index of %s: %w", entityType, err)
+		}
+	}
+	return nil
+}
+
+// Record stores previous as the next version of its entity
+func (s *Store) Record(ctx context.Context, entityType string, previous bson.M, version Version) (*Version, error) {
+	entityID, ok := previous["_id"].(primitive.ObjectID)
+	if !ok {
+		return nil, fmt.Errorf("document has no ObjectID _id")
+	}
+
+	collection := s.db.Collection(schema.HistoryCollection(entityType))
+	latest, err := s.latest(ctx, collection, entityID)
+	if err != nil {
+		return nil, err
+	}
+
+	version.ID = primitive.NilObjectID
+	version.EntityID = entityID
+	version.Version = latest + 1
+	version.Data = previous
+	version.CreatedAt = time.Now()
+
+	result, err := collection.InsertOne(ctx, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record version: %w", err)
+	}
+	version.ID = result.InsertedID.(primitive.ObjectID)
+	return &version, nil
+}
+
+func (s *Store) latest(ctx context.Context, collection *mongo.Collection, entityID primitive.ObjectID) (int64, error) {
+	var last Version
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"version": 1})
+	err := collection.FindOne(ctx, bson.M{"entity_id": entityID}, opts).Decode(&last)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read latest version: %w", err)
+	}
+	return last.Version, nil
+}
+
+// List returns the versions of an entity without their data, newest first
+func (s *Store) List(ctx context.Context, entityType string, entityID primitive.ObjectID, page, pageSize int64) ([]*Version, int64, error) {
+	collection := s.db.Collection(schema.HistoryCollection(entityType))
+	filter := bson.M{"entity_id": entityID}
+
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count versions: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"data": 0}).
+		SetSkip((page - 1) * pageSize).
+		SetLimit(pageSize)
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list versions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	versions := []*Version{}
+	if err := cursor.All(ctx, &versions); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode versions: %w", err)
+	}
+	return versions, total, nil
+}
+
+// Get returns one version with its data
+func (s *Store) Get(ctx context.Context, entityType string, entityID primitive.ObjectID, version int64) (*Version, error) {
+	var result Version
+	err := s.db.Collection(schema.HistoryCollection(entityType)).
+		FindOne(ctx, bson.M{"entity_id": entityID, "version": version}).
+		Decode(&result)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrVersionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,72 @@
+package history
+
+import (
+	"context"
+	"fmt"
+
+	"thaily/services/_common/hooks"
+	"thaily/services/_common/schema"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	previousKey   = "history.previous"
+	revertedToKey = "history.reverted_to"
+)
+
+// Bản trước được đọc ở Before hook và chỉ được lưu khi ghi thành công, cùng
+// transaction với thao tác ghi. Document bị xóa hoặc gán null theo cascade
+// cũng chạy các hook này (xem deleteOperations)
+func init() {
+	for entityType := range schema.HistoryEntities {
+		hooks.Register(hooks.Hook{Name: "history.capture", EntityType: entityType, Event: hooks.BeforeUpdate, Fn: capturePrevious})
+		hooks.Register(hooks.Hook{Name: "history.capture", EntityType: entityType, Event: hooks.BeforeDelete, Fn: capturePrevious})
+		hooks.Register(hooks.Hook{Name: "history.record", EntityType: entityType, Event: hooks.AfterUpdate, Fn: recordPrevious})
+		hooks.Register(hooks.Hook{Name: "history.record", EntityType: entityType, Event: hooks.AfterDelete, Fn: recordPrevious})
+	}
+}
+
+// MarkRevert records the write of op as a revert to version
+func MarkRevert(op *hooks.Operation, version int64) {
+	op.State[revertedToKey] = version
+}
+
+func capturePrevious(ctx context.Context, op *hooks.Operation) error {
+	id, err := primitive.ObjectIDFromHex(op.ID)
+	if err != nil {
+		return nil
+	}
+
+	var previous bson.M
+	err = op.DB.Collection(op.EntityType).FindOne(ctx, bson.M{"_id": id}).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read previous version: %w", err)
+	}
+	op.State[previousKey] = previous
+	return nil
+}
+
+func recordPrevious(ctx context.Context, op *hooks.Operation) error {
+	previous, ok := op.State[previousKey].(bson.M)
+	if !ok {
+		return nil
+	}
+
+	version := Version{Operation: OperationUpdate, Meta: op.Meta}
+	if op.Event == hooks.AfterDelete {
+		version.Operation = OperationDelete
+	}
+	if revertedTo, ok := op.State[revertedToKey].(int64); ok {
+		version.Operation = OperationRevert
+		version.RevertedTo = revertedTo
+	}
+
+	_, err := NewStore(op.DB).Record(ctx, op.EntityType, previous, version)
+	return err
+}
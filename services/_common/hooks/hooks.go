@@ -0,0 +1,197 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	pb "thaily/proto/common"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type Event string
+
+const (
+	BeforeCreate Event = "before_create"
+	AfterCreate  Event = "after_create"
+	BeforeUpdate Event = "before_update"
+	AfterUpdate  Event = "after_update"
+	BeforeDelete Event = "before_delete"
+	AfterDelete  Event = "after_delete"
+)
+
+// AllEntities registers a hook for every entity type
+const AllEntities = "*"
+
+// TaskEnqueuer queues background work, see tasks.Client
+type TaskEnqueuer interface {
+	Enqueue(ctx context.Context, taskType string, payload interface{}) error
+}
+
+// Operation describes the write a hook runs for. Before hooks may modify
+// Data; After hooks see the stored document in Entity.
+type Operation struct {
+	EntityType string
+	Event      Event
+	// Id of the document, empty before a create
+	ID string
+	// Document being created, or the fields being set by an update
+	Data map[string]interface{}
+	// Stored document after the write, nil for deletes
+	Entity map[string]interface{}
+	// meta of the request
+	Meta map[string]interface{}
+	// State is shared by the Before and After hooks of the same document
+	State map[string]interface{}
+
+	// DB and ctx share the transaction of the write when one is active
+	DB    *mongo.Database
+	Tasks TaskEnqueuer
+
+	afterCommit *[]func(ctx context.Context)
+}
+
+// AfterCommit defers fn until the write is committed, for side effects that
+// must not happen when the transaction is rolled back
+func (op *Operation) AfterCommit(fn func(ctx context.Context)) {
+	*op.afterCommit = append(*op.afterCommit, fn)
+}
+
+// EnqueueAfterCommit queues a background task once the write is committed
+func (op *Operation) EnqueueAfterCommit(taskType string, payload interface{}) {
+	op.AfterCommit(func(ctx context.Context) {
+		if op.Tasks == nil {
+			log.Printf("Task queue is not configured, dropping %s for %s %s", taskType, op.EntityType, op.ID)
+			return
+		}
+		if err := op.Tasks.Enqueue(ctx, taskType, payload); err != nil {
+			log.Printf("Failed to enqueue %s for %s %s: %v", taskType, op.EntityType, op.ID, err)
+		}
+	})
+}
+
+// Next returns a copy of op for the following event, sharing the deferred
+// side effects
+func (op *Operation) Next(event Event) *Operation {
+	next := *op
+	next.Event = event
+	return &next
+}
+
+// Commit runs the side effects deferred by AfterCommit
+func (op *Operation) Commit(ctx context.Context) {
+	for _, fn := range *op.afterCommit {
+		fn(ctx)
+	}
+	*op.afterCommit = nil
+}
+
+// NewOperation prepares the operation of one document
+func NewOperation(entityType string, event Event, db *mongo.Database, tasks TaskEnqueuer) *Operation {
+	return &Operation{
+		EntityType:  entityType,
+		Event:       event,
+		DB:          db,
+		Tasks:       tasks,
+		State:       map[string]interface{}{},
+		afterCommit: &[]func(ctx context.Context){},
+	}
+}
+
+// Rejection is returned by a hook to refuse the write with a client facing
+// message instead of an internal error
+type Rejection struct {
+	Message string
+	Details []*pb.ErrorDetail
+}
+
+func (r *Rejection) Error() string {
+	return r.Message
+}
+
+func Reject(message string, details ...*pb.ErrorDetail) error {
+	return &Rejection{Message: message, Details: details}
+}
+
+// AsRejection converts any hook error into a Rejection
+func AsRejection(err error) *Rejection {
+	var rejection *Rejection
+	if errors.As(err, &rejection) {
+		return rejection
+	}
+	return &Rejection{Message: err.Error()}
+}
+
+type HookFunc func(ctx context.Context, op *Operation) error
+
+type Hook struct {
+	Name       string
+	EntityType string
+	Event      Event
+	// Lower runs first; hooks with the same order run in registration order
+	Order int
+	Fn    HookFunc
+}
+
+var (
+	mu       sync.RWMutex
+	registry []Hook
+)
+
+// Register adds a hook, usually from an init function
+func Register(hook Hook) {
+	if hook.Name == "" || hook.EntityType == "" || hook.Event == "" || hook.Fn == nil {
+		panic("hooks: name, entity type, event and function are required")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	registry = append(registry, hook)
+	sort.SliceStable(registry, func(i, j int) bool {
+		return registry[i].Order < registry[j].Order
+	})
+}
+
+func matching(entityType string, event Event) []Hook {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	var result []Hook
+	for _, hook := range registry {
+		if hook.Event == event && (hook.EntityType == entityType || hook.EntityType == AllEntities) {
+			result = append(result, hook)
+		}
+	}
+	return result
+}
+
+// Has reports whether any hook is registered for entityType
+func Has(entityType string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, hook := range registry {
+		if hook.EntityType == entityType || hook.EntityType == AllEntities {
+			return true
+		}
+	}
+	return false
+}
+
+// Run executes the hooks matching op in order, stopping at the first error
+func Run(ctx context.Context, op *Operation) error {
+	for _, hook := range matching(op.EntityType, op.Event) {
+		if err := hook.Fn(ctx, op); err != nil {
+			var rejection *Rejection
+			if errors.As(err, &rejection) {
+				return err
+			}
+			return fmt.Errorf("hook %s failed: %w", hook.Name, err)
+		}
+	}
+	return nil
+}
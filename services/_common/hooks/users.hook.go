@@ -0,0 +1,137 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "thaily/proto/common"
+	"thaily/services/_common/authn"
+	"thaily/services/_common/password"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Email được lưu ở dạng chữ thường để đăng nhập không phụ thuộc hoa thường
+func init() {
+	Register(Hook{Name: "users.normalize_email", EntityType: "users", Event: BeforeCreate, Fn: normalizeEmail})
+	Register(Hook{Name: "users.normalize_email", EntityType: "users", Event: BeforeUpdate, Fn: normalizeEmail})
+	Register(Hook{Name: "users.hash_password", EntityType: "users", Event: BeforeCreate, Fn: hashPassword})
+	Register(Hook{Name: "users.hash_password", EntityType: "users", Event: BeforeUpdate, Fn: hashPassword})
+	Register(Hook{Name: "users.guard_role", EntityType: "users", Event: BeforeCreate, Fn: guardRoleOnCreate})
+	Register(Hook{Name: "users.guard_role", EntityType: "users", Event: BeforeUpdate, Fn: guardRoleOnUpdate})
+}
+
+// Các field quyết định quyền của tài khoản, chỉ AuthService được đổi
+var roleFields = []string{"role_id", "role", "roles"}
+
+// roleKeys are the keys of data setting a role field, dotted paths included
+func roleKeys(data map[string]interface{}) []string {
+	var keys []string
+	for key := range data {
+		for _, field := range roleFields {
+			if key == field || strings.HasPrefix(key, field+".") {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+func roleRejection(field string) error {
+	return Reject("the role of a user can only be changed through AssignRole", &pb.ErrorDetail{
+		Code:    "FORBIDDEN",
+		Field:   field,
+		Message: "the role of a user can only be changed through AssignRole",
+	})
+}
+
+// Chỉ admin được tạo tài khoản kèm vai trò; tài khoản mới chưa có token nên
+// không cần thu hồi
+func guardRoleOnCreate(ctx context.Context, op *Operation) error {
+	keys := roleKeys(op.Data)
+	if len(keys) == 0 {
+		return nil
+	}
+	if claims := authn.FromContext(ctx); claims != nil && claims.HasRole("admin") {
+		return nil
+	}
+	return roleRejection(keys[0])
+}
+
+// Đổi vai trò phải qua AssignRole để token cũ bị thu hồi. Giá trị không đổi
+// (cập nhật toàn bộ document, khôi phục phiên bản) vẫn được chấp nhận.
+func guardRoleOnUpdate(ctx context.Context, op *Operation) error {
+	keys := roleKeys(op.Data)
+	if len(keys) == 0 {
+		return nil
+	}
+	id, err := primitive.ObjectIDFromHex(op.ID)
+	if err != nil {
+		return roleRejection(keys[0])
+	}
+	var current bson.M
+	err = op.DB.Collection("users").FindOne(ctx, bson.M{"_id": id}).Decode(&current)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	for _, key := range keys {
+		if !sameValue(op.Data[key], current[key]) {
+			return roleRejection(key)
+		}
+	}
+	return nil
+}
+
+func sameValue(a, b interface{}) bool {
+	normalize := func(v interface{}) string {
+		if id, ok := v.(primitive.ObjectID); ok {
+			return id.Hex()
+		}
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprint(v)
+	}
+	return normalize(a) == normalize(b)
+}
+
+func normalizeEmail(ctx context.Context, op *Operation) error {
+	email, ok := op.Data["email"].(string)
+	if !ok {
+		return nil
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" || !strings.Contains(email, "@") {
+		return Reject("email is invalid", &pb.ErrorDetail{
+			Code:    "INVALID",
+			Field:   "email",
+			Value:   email,
+			Message: "email is invalid",
+		})
+	}
+	op.Data["email"] = email
+	return nil
+}
+
+// Mật khẩu không bao giờ được lưu ở dạng rõ; giá trị đã là hash thì giữ nguyên
+func hashPassword(ctx context.Context, op *Operation) error {
+	plain, ok := op.Data["password"].(string)
+	if !ok || plain == "" || password.IsHashed(plain) {
+		return nil
+	}
+
+	hash, err := password.Default().Hash(plain)
+	if err != nil {
+		return Reject("password cannot be hashed", &pb.ErrorDetail{
+			Code:    "INVALID",
+			Field:   "password",
+			Message: err.Error(),
+		})
+	}
+	op.Data["password"] = hash
+	return nil
+}
@@ -0,0 +1,67 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+
+	"thaily/services/_common/authn"
+)
+
+func TestGuardRoleOnCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		claims  *authn.Claims
+		data    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name:   "no role fields",
+			claims: &authn.Claims{UserID: "u1", Roles: "student"},
+			data:   map[string]interface{}{"email": "a@example.edu"},
+		},
+		{
+			name:    "student sets role_id",
+			claims:  &authn.Claims{UserID: "u1", Roles: "student"},
+			data:    map[string]interface{}{"role_id": "665f1c2e8b3f4a0012345678"},
+			wantErr: true,
+		},
+		{
+			name:    "staff sets roles",
+			claims:  &authn.Claims{UserID: "u1", Roles: "staff"},
+			data:    map[string]interface{}{"roles": "admin"},
+			wantErr: true,
+		},
+		{
+			name:    "dotted role path",
+			claims:  &authn.Claims{UserID: "u1", Roles: "student"},
+			data:    map[string]interface{}{"role.name": "admin"},
+			wantErr: true,
+		},
+		{
+			name:    "no token",
+			data:    map[string]interface{}{"role": "admin"},
+			wantErr: true,
+		},
+		{
+			name:   "admin creates a user with a role",
+			claims: &authn.Claims{UserID: "u1", Roles: "staff, Admin"},
+			data:   map[string]interface{}{"role_id": "665f1c2e8b3f4a0012345678"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.claims != nil {
+				ctx = authn.NewContext(ctx, tt.claims)
+			}
+			op := NewOperation("users", BeforeCreate, nil, nil)
+			op.Data = tt.data
+
+			err := guardRoleOnCreate(ctx, op)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("guardRoleOnCreate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package integrity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"thaily/services/_common/schema"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrRestricted = errors.New("delete is restricted by existing references")
+
+// Step is the effect of a delete on one reference field
+type Step struct {
+	Entity string
+	Field  string
+	Action schema.OnDelete
+	IDs    []primitive.ObjectID
+}
+
+// Plan lists every document affected by deleting IDs of Entity
+type Plan struct {
+	Entity string
+	IDs    []primitive.ObjectID
+	Steps  []Step
+}
+
+// Restricted returns the steps that block the delete
+func (p *Plan) Restricted() []Step {
+	var result []Step
+	for _, step := range p.Steps {
+		if step.Action == schema.Restrict {
+			result = append(result, step)
+		}
+	}
+	return result
+}
+
+// Cascaded returns the ids deleted by cascade, per entity type
+func (p *Plan) Cascaded() map[string][]string {
+	result := map[string][]string{}
+	for _, step := range p.Steps {
+		if step.Action != schema.Cascade {
+			continue
+		}
+		for _, id := range step.IDs {
+			result[step.Entity] = append(result[step.Entity], id.Hex())
+		}
+	}
+	return result
+}
+
+func (p *Plan) Error() error {
+	restricted := p.Restricted()
+	if len(restricted) == 0 {
+		return nil
+	}
+	step := restricted[0]
+	return fmt.Errorf("%w: %d %s reference it through %s", ErrRestricted, len(step.IDs), step.Entity, step.Field)
+}
+
+type stepKey struct {
+	entity string
+	field  string
+}
+
+// PlanDelete walks the declared relations from the deleted documents,
+// following cascades transitively. Nothing is written.
+func (c *Checker) PlanDelete(ctx context.Context, entityType string, ids []primitive.ObjectID) (*Plan, error) {
+	plan := &Plan{Entity: entityType, IDs: ids}
+	deleting := map[string]map[primitive.ObjectID]bool{entityType: {}}
+	for _, id := range ids {
+		deleting[entityType][id] = true
+	}
+
+	type pending struct {
+		entity string
+		ids    []primitive.ObjectID
+	}
+	queue := []pending{{entity: entityType, ids: ids}}
+	steps := map[stepKey]int{}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, relation := range schema.Dependents(current.entity) {
+			found, err := c.findIDs(ctx, relation.Entity, bson.M{relation.Field: bson.M{"$in": referenceValues(current.ids)}})
+			if err != nil {
+				return nil, err
+			}
+			if len(found) == 0 {
+				continue
+			}
+
+			key := stepKey{entity: relation.Entity, field: relation.Field}
+			index, ok := steps[key]
+			if !ok {
+				index = len(plan.Steps)
+				steps[key] = index
+				plan.Steps = append(plan.Steps, Step{Entity: relation.Entity, Field: relation.Field, Action: relation.OnDelete})
+			}
+
+			if deleting[relation.Entity] == nil {
+				deleting[relation.Entity] = map[primitive.ObjectID]bool{}
+			}
+			var cascaded []primitive.ObjectID
+			for id := range found {
+				if relation.OnDelete == schema.Cascade {
+					if deleting[relation.Entity][id] {
+						continue
+					}
+					deleting[relation.Entity][id] = true
+					cascaded = append(cascaded, id)
+				}
+				plan.Steps[index].IDs = append(plan.Steps[index].IDs, id)
+			}
+			if len(cascaded) > 0 {
+				queue = append(queue, pending{entity: relation.Entity, ids: cascaded})
+			}
+		}
+	}
+
+	// Documents removed by a cascade neither block the delete nor need nulling
+	kept := plan.Steps[:0]
+	for _, step := range plan.Steps {
+		if step.Action != schema.Cascade {
+			remaining := step.IDs[:0]
+			for _, id := range step.IDs {
+				if !deleting[step.Entity][id] {
+					remaining = append(remaining, id)
+				}
+			}
+			step.IDs = remaining
+		}
+		if len(step.IDs) > 0 {
+			kept = append(kept, step)
+		}
+	}
+	plan.Steps = kept
+	return plan, nil
+}
+
+// Apply deletes and nulls the dependents of a plan. The documents of
+// plan.Entity itself are left to the caller; run both in one transaction.
+func (c *Checker) Apply(ctx context.Context, plan *Plan) error {
+	if err := plan.Error(); err != nil {
+		return err
+	}
+
+	// Xóa từ lá lên gốc để nếu không có transaction cũng không để lại tham chiếu mồ côi
+	for i := len(plan.Steps) - 1; i >= 0; i-- {
+		step := plan.Steps[i]
+		collection := c.db.Collection(step.Entity)
+		filter := bson.M{"_id": bson.M{"$in": step.IDs}}
+
+		var err error
+		switch step.Action {
+		case schema.Cascade:
+			_, err = collection.DeleteMany(ctx, filter)
+		case schema.SetNull:
+			_, err = collection.UpdateMany(ctx, filter, bson.M{
+				"$set": bson.M{step.Field: nil, "updatedAt": time.Now()},
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to apply %s on %s.%s: %w", step.Action, step.Entity, step.Field, err)
+		}
+	}
+	return nil
+}
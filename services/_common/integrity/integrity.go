@@ -0,0 +1,116 @@
+package integrity
+
+import (
+	"context"
+	"fmt"
+
+	"thaily/services/_common/schema"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Violation is a reference field pointing at a document that does not exist
+type Violation struct {
+	// Index of the document in the checked batch
+	Index  int
+	Field  string
+	Value  string
+	Target string
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("%s: %s %s does not exist", v.Field, v.Target, v.Value)
+}
+
+type Checker struct {
+	db *mongo.Database
+}
+
+func NewChecker(db *mongo.Database) *Checker {
+	return &Checker{db: db}
+}
+
+// CheckReferences verifies every declared reference field present in docs.
+// Missing and null fields are accepted; ids may be ObjectIDs or hex strings.
+func (c *Checker) CheckReferences(ctx context.Context, entityType string, docs ...map[string]interface{}) ([]Violation, error) {
+	var violations []Violation
+	for _, relation := range schema.References(entityType) {
+		wanted := map[primitive.ObjectID]bool{}
+		for i, doc := range docs {
+			value, ok := doc[relation.Field]
+			if !ok || value == nil {
+				continue
+			}
+			id, ok := toObjectID(value)
+			if !ok {
+				violations = append(violations, Violation{Index: i, Field: relation.Field, Value: fmt.Sprint(value), Target: relation.Target})
+				continue
+			}
+			wanted[id] = true
+		}
+		if len(wanted) == 0 {
+			continue
+		}
+
+		ids := make([]primitive.ObjectID, 0, len(wanted))
+		for id := range wanted {
+			ids = append(ids, id)
+		}
+		existing, err := c.findIDs(ctx, relation.Target, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return nil, err
+		}
+
+		for i, doc := range docs {
+			id, ok := toObjectID(doc[relation.Field])
+			if ok && !existing[id] {
+				violations = append(violations, Violation{Index: i, Field: relation.Field, Value: id.Hex(), Target: relation.Target})
+			}
+		}
+	}
+	return violations, nil
+}
+
+func (c *Checker) findIDs(ctx context.Context, collection string, filter bson.M) (map[primitive.ObjectID]bool, error) {
+	cursor, err := c.db.Collection(collection).Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s: %w", collection, err)
+	}
+	defer cursor.Close(ctx)
+
+	result := map[primitive.ObjectID]bool{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		result[doc.ID] = true
+	}
+	return result, cursor.Err()
+}
+
+func toObjectID(value interface{}) (primitive.ObjectID, bool) {
+	switch v := value.(type) {
+	case primitive.ObjectID:
+		return v, true
+	case string:
+		id, err := primitive.ObjectIDFromHex(v)
+		return id, err == nil
+	}
+	return primitive.NilObjectID, false
+}
+
+// referenceValues matches a reference stored either as ObjectID or as hex
+// string, since documents created through the API keep ids as strings
+func referenceValues(ids []primitive.ObjectID) bson.A {
+	values := make(bson.A, 0, len(ids)*2)
+	for _, id := range ids {
+		values = append(values, id, id.Hex())
+	}
+	return values
+}
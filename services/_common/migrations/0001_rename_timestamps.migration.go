@@ -0,0 +1,141 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// The data generator and indexes.js write created_at/updated_at while the
+// resolvers write createdAt/updatedAt. Normalize everything to camelCase.
+//
+// Không có Down: sau Up không còn phân biệt được document nào vốn đã dùng
+// camelCase, đổi ngược toàn bộ sẽ làm hỏng chúng
+func init() {
+	Register(Migration{
+		Version:     1,
+		Name:        "rename_timestamps_to_camel_case",
+		Description: "created_at -> createdAt, updated_at -> updatedAt in all collections and indexes (irreversible)",
+		Up:          renameTimestampsUp,
+	})
+}
+
+var timestampFields = map[string]string{
+	"created_at": "createdAt",
+	"updated_at": "updatedAt",
+}
+
+func renameTimestampsUp(ctx context.Context, db *mongo.Database) error {
+	return renameFields(ctx, db, timestampFields)
+}
+
+func renameFields(ctx context.Context, db *mongo.Database, fields map[string]string) error {
+	names, err := db.ListCollectionNames(ctx, bson.M{"type": "collection"})
+	if err != nil {
+		return fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	for _, name := range names {
+		if name == CollectionName || strings.HasPrefix(name, "system.") {
+			continue
+		}
+		collection := db.Collection(name)
+
+		for from, to := range fields {
+			// Document đã có field mới thì giữ nguyên, $rename sẽ ghi đè giá trị mới
+			_, err := collection.UpdateMany(ctx,
+				bson.M{from: bson.M{"$exists": true}, to: bson.M{"$exists": false}},
+				bson.M{"$rename": bson.M{from: to}},
+			)
+			if err != nil {
+				return fmt.Errorf("failed to rename %s.%s: %w", name, from, err)
+			}
+		}
+
+		if err := renameIndexKeys(ctx, collection, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameIndexKeys recreates every index that references a renamed field,
+// keeping all of its options. An index still named after its old key gets
+// the default name of the new key.
+func renameIndexKeys(ctx context.Context, collection *mongo.Collection, fields map[string]string) error {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list indexes of %s: %w", collection.Name(), err)
+	}
+	var indexes []bson.D
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return fmt.Errorf("failed to read indexes of %s: %w", collection.Name(), err)
+	}
+
+	for _, index := range indexes {
+		var name string
+		var key bson.D
+		for _, elem := range index {
+			switch elem.Key {
+			case "name":
+				name, _ = elem.Value.(string)
+			case "key":
+				key, _ = elem.Value.(bson.D)
+			}
+		}
+
+		changed := false
+		newKey := bson.D{}
+		for _, elem := range key {
+			if to, ok := fields[elem.Key]; ok {
+				elem.Key = to
+				changed = true
+			}
+			newKey = append(newKey, elem)
+		}
+		if !changed {
+			continue
+		}
+
+		// Giữ mọi option của index (partialFilterExpression, collation,
+		// expireAfterSeconds...), chỉ thay key; v và ns do server tự đặt
+		newSpec := bson.D{}
+		for _, elem := range index {
+			switch elem.Key {
+			case "v", "ns":
+				continue
+			case "key":
+				elem.Value = newKey
+			case "name":
+				if name == defaultIndexName(key) {
+					elem.Value = defaultIndexName(newKey)
+				}
+			}
+			newSpec = append(newSpec, elem)
+		}
+
+		if _, err := collection.Indexes().DropOne(ctx, name); err != nil {
+			return fmt.Errorf("failed to drop index %s.%s: %w", collection.Name(), name, err)
+		}
+		err := collection.Database().RunCommand(ctx, bson.D{
+			{Key: "createIndexes", Value: collection.Name()},
+			{Key: "indexes", Value: bson.A{newSpec}},
+		}).Err()
+		if err != nil {
+			return fmt.Errorf("failed to recreate index %s.%s: %w", collection.Name(), name, err)
+		}
+	}
+	return nil
+}
+
+// defaultIndexName is the name MongoDB gives an index on key, e.g. createdAt_-1
+func defaultIndexName(key bson.D) string {
+	parts := make([]string, 0, len(key)*2)
+	for _, elem := range key {
+		parts = append(parts, elem.Key, fmt.Sprint(elem.Value))
+	}
+	return strings.Join(parts, "_")
+}
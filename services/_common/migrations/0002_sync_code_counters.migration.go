@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+
+	"thaily/services/_common/sequence"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Dữ liệu mẫu đã có mã GV001, GV002... nên bộ đếm phải bắt đầu sau mã lớn nhất
+func init() {
+	Register(Migration{
+		Version:     2,
+		Name:        "sync_code_counters",
+		Description: "Raise _counters above the codes already present in the data",
+		Up:          syncCodeCountersUp,
+		Down:        syncCodeCountersDown,
+	})
+}
+
+func syncCodeCountersUp(ctx context.Context, db *mongo.Database) error {
+	return sequence.NewGenerator(db).SyncWithExisting(ctx, db)
+}
+
+// Counters stay where they are: lowering them could hand out codes in use
+func syncCodeCountersDown(ctx context.Context, db *mongo.Database) error {
+	return nil
+}
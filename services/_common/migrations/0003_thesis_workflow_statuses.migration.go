@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"thaily/services/_common/workflow"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Trạng thái cũ cùng tên được gắn key, trạng thái còn thiếu được tạo mới
+func init() {
+	Register(Migration{
+		Version:     3,
+		Name:        "thesis_workflow_statuses",
+		Description: "Give thesis_statuses a workflow key and create the missing lifecycle states",
+		Up:          thesisWorkflowStatusesUp,
+		Down:        thesisWorkflowStatusesDown,
+	})
+}
+
+const workflowMigrationMarker = "thesis_workflow_statuses"
+
+func thesisWorkflowStatusesUp(ctx context.Context, db *mongo.Database) error {
+	statuses := db.Collection(workflow.StatusesCollection)
+	for i, status := range workflow.Statuses {
+		_, err := statuses.UpdateOne(ctx,
+			bson.M{"$or": bson.A{
+				bson.M{"key": string(status.State)},
+				bson.M{"name": status.Name, "key": bson.M{"$exists": false}},
+			}},
+			bson.M{
+				"$set": bson.M{
+					"key":       string(status.State),
+					"order":     i + 1,
+					"is_active": true,
+				},
+				"$setOnInsert": bson.M{
+					"name":        status.Name,
+					"description": status.Description,
+					"color":       status.Color,
+					"createdBy":   workflowMigrationMarker,
+				},
+			},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to upsert status %s: %w", status.State, err)
+		}
+	}
+	return nil
+}
+
+// Down removes the keys and the statuses created by Up that no thesis uses
+func thesisWorkflowStatusesDown(ctx context.Context, db *mongo.Database) error {
+	statuses := db.Collection(workflow.StatusesCollection)
+
+	used, err := db.Collection("theses").Distinct(ctx, "status_id", bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to list used statuses: %w", err)
+	}
+	_, err = statuses.DeleteMany(ctx, bson.M{
+		"createdBy": workflowMigrationMarker,
+		"_id":       bson.M{"$nin": used},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete statuses: %w", err)
+	}
+
+	_, err = statuses.UpdateMany(ctx, bson.M{}, bson.M{"$unset": bson.M{"key": "", "createdBy": ""}})
+	if err != nil {
+		return fmt.Errorf("failed to remove status keys: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Auth service so sánh email ở dạng chữ thường, email cũ viết hoa phải được
+// chuẩn hóa thì tài khoản mới đăng nhập được
+func init() {
+	Register(Migration{
+		Version:     4,
+		Name:        "normalize_user_emails",
+		Description: "Trim and lowercase the stored user emails",
+		Up:          normalizeUserEmailsUp,
+		Down:        normalizeUserEmailsDown,
+	})
+}
+
+func normalizeUserEmailsUp(ctx context.Context, db *mongo.Database) error {
+	users := db.Collection("users")
+	cursor, err := users.Find(ctx,
+		bson.M{"email": bson.M{"$type": "string"}},
+		options.Find().SetProjection(bson.M{"email": 1}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+	var docs []struct {
+		ID    interface{} `bson:"_id"`
+		Email string      `bson:"email"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return fmt.Errorf("failed to read users: %w", err)
+	}
+
+	owners := map[string][]string{}
+	for _, doc := range docs {
+		email := strings.ToLower(strings.TrimSpace(doc.Email))
+		owners[email] = append(owners[email], doc.Email)
+	}
+	// Hai tài khoản trùng email sau khi chuẩn hóa phải được gộp bằng tay trước
+	var collisions []string
+	for email, stored := range owners {
+		if len(stored) > 1 {
+			collisions = append(collisions, fmt.Sprintf("%s (%s)", email, strings.Join(stored, ", ")))
+		}
+	}
+	if len(collisions) > 0 {
+		sort.Strings(collisions)
+		return fmt.Errorf("users share an email once normalized, merge them first: %s", strings.Join(collisions, "; "))
+	}
+
+	for _, doc := range docs {
+		email := strings.ToLower(strings.TrimSpace(doc.Email))
+		if email == doc.Email {
+			continue
+		}
+		if _, err := users.UpdateByID(ctx, doc.ID, bson.M{"$set": bson.M{"email": email}}); err != nil {
+			return fmt.Errorf("failed to normalize email %s: %w", doc.Email, err)
+		}
+	}
+	return nil
+}
+
+// The original casing is not kept, emails stay lowercase
+func normalizeUserEmailsDown(ctx context.Context, db *mongo.Database) error {
+	return nil
+}
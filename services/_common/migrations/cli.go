@@ -0,0 +1,98 @@
+package migrations
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const usage = `usage: migrate <command> [options]
+
+commands:
+  up     [-to VERSION]  apply pending migrations (up to VERSION if given)
+  down   [-steps N]     roll back the last N applied migrations (default 1),
+                        stopping at an irreversible one
+  status                show applied and pending migrations`
+
+// RunCLI executes the `migrate` subcommand with the given arguments
+func RunCLI(ctx context.Context, db *mongo.Database, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", usage)
+	}
+
+	runner := NewRunner(db)
+	cmd, rest := args[0], args[1:]
+
+	switch cmd {
+	case "up":
+		fs := flag.NewFlagSet("migrate up", flag.ContinueOnError)
+		to := fs.Int64("to", 0, "Target version (0 = latest)")
+		if err := fs.Parse(rest); err != nil {
+			return err
+		}
+
+		applied, err := runner.Up(ctx, *to)
+		for _, m := range applied {
+			fmt.Fprintf(out, "applied  %d  %s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(out, "no pending migrations")
+		}
+		return nil
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := fs.Int("steps", 1, "Number of migrations to roll back")
+		if err := fs.Parse(rest); err != nil {
+			return err
+		}
+
+		reverted, err := runner.Down(ctx, *steps)
+		for _, m := range reverted {
+			fmt.Fprintf(out, "reverted %d  %s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			fmt.Fprintln(out, "nothing to roll back")
+		}
+		return nil
+
+	case "status":
+		entries, err := runner.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, e := range entries {
+			state := "pending"
+			appliedAt := ""
+			if e.Applied {
+				state = "applied"
+				appliedAt = e.AppliedAt.Format(time.RFC3339)
+			}
+			if e.Mismatch {
+				state = "applied (checksum mismatch)"
+			}
+			if e.Missing {
+				state = "applied (not registered)"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", e.Version, e.Name, state, appliedAt)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown command %q\n%s", cmd, usage)
+	}
+}
@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MigrationFunc applies (or reverts) a single schema/data change
+type MigrationFunc func(ctx context.Context, db *mongo.Database) error
+
+// Migration is a versioned, ordered change to the database
+type Migration struct {
+	Version     int64
+	Name        string
+	Description string
+	Up          MigrationFunc
+	Down        MigrationFunc
+}
+
+// Checksum identifies a migration by version and name, so that a different
+// migration registered under an applied version is detected. It stays the
+// same when the description, comments or formatting change.
+func (m Migration) Checksum() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d|%s", m.Version, m.Name)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[int64]Migration{}
+)
+
+// Register adds a migration to the global registry, normally from an init function
+func Register(m Migration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if m.Version <= 0 {
+		panic(fmt.Sprintf("migrations: invalid version %d for %q", m.Version, m.Name))
+	}
+	if m.Up == nil {
+		panic(fmt.Sprintf("migrations: migration %d (%s) has no Up function", m.Version, m.Name))
+	}
+	if existing, ok := registry[m.Version]; ok {
+		panic(fmt.Sprintf("migrations: version %d registered twice (%s, %s)", m.Version, existing.Name, m.Name))
+	}
+	registry[m.Version] = m
+}
+
+// All returns the registered migrations sorted by version
+func All() []Migration {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	list := make([]Migration, 0, len(registry))
+	for _, m := range registry {
+		list = append(list, m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list
+}
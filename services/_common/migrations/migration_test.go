@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestChecksum(t *testing.T) {
+	base := Migration{Version: 4, Name: "add_defense_rooms", Description: "rooms"}
+
+	tests := []struct {
+		name string
+		m    Migration
+		same bool
+	}{
+		{"description edited", Migration{Version: 4, Name: "add_defense_rooms", Description: "defense rooms"}, true},
+		{"another function", Migration{Version: 4, Name: "add_defense_rooms", Description: "rooms", Down: func(ctx context.Context, db *mongo.Database) error { return nil }}, true},
+		{"renamed", Migration{Version: 4, Name: "add_rooms", Description: "rooms"}, false},
+		{"another version", Migration{Version: 5, Name: "add_defense_rooms", Description: "rooms"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := base.Checksum() == tt.m.Checksum(); got != tt.same {
+			t.Errorf("%s: same checksum = %v, want %v", tt.name, got, tt.same)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	CollectionName = "_migrations"
+	lockID         = "lock"
+	defaultLockTTL = 10 * time.Minute
+)
+
+var ErrLocked = errors.New("migrations are locked by another instance")
+
+// AppliedMigration is the record stored in _migrations for every applied version
+type AppliedMigration struct {
+	Version    int64     `bson:"_id"`
+	Name       string    `bson:"name"`
+	Checksum   string    `bson:"checksum"`
+	AppliedAt  time.Time `bson:"appliedAt"`
+	DurationMs int64     `bson:"durationMs"`
+}
+
+// StatusEntry describes the state of one migration version
+type StatusEntry struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	// Mismatch is set when the applied checksum differs from the registered one
+	Mismatch bool
+	// Missing is set when the version is recorded as applied but no longer registered
+	Missing bool
+}
+
+type Runner struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+	migrations []Migration
+	owner      string
+	lockTTL    time.Duration
+}
+
+func NewRunner(db *mongo.Database) *Runner {
+	host, _ := os.Hostname()
+	return &Runner{
+		db:         db,
+		collection: db.Collection(CollectionName),
+		migrations: All(),
+		owner:      fmt.Sprintf("%s:%d", host, os.Getpid()),
+		lockTTL:    defaultLockTTL,
+	}
+}
+
+// Up applies pending migrations in order, stopping after target (0 = all)
+func (r *Runner) Up(ctx context.Context, target int64) ([]Migration, error) {
+	var applied []Migration
+	err := r.withLock(ctx, func() error {
+		records, err := r.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range r.migrations {
+			if target > 0 && m.Version > target {
+				break
+			}
+			if rec, ok := records[m.Version]; ok {
+				if rec.Checksum != m.Checksum() {
+					return fmt.Errorf("checksum mismatch for applied migration %d (%s)", m.Version, m.Name)
+				}
+				continue
+			}
+
+			log.Printf("Applying migration %d: %s", m.Version, m.Name)
+			start := time.Now()
+			if err := m.Up(ctx, r.db); err != nil {
+				return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+
+			_, err := r.collection.InsertOne(ctx, AppliedMigration{
+				Version:    m.Version,
+				Name:       m.Name,
+				Checksum:   m.Checksum(),
+				AppliedAt:  time.Now(),
+				DurationMs: time.Since(start).Milliseconds(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to record migration %d: %w", m.Version, err)
+			}
+			applied = append(applied, m)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last `steps` applied migrations in reverse order
+func (r *Runner) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	var reverted []Migration
+	err := r.withLock(ctx, func() error {
+		records, err := r.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := len(r.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			m := r.migrations[i]
+			if _, ok := records[m.Version]; !ok {
+				continue
+			}
+			if m.Down == nil {
+				return fmt.Errorf("migration %d (%s) is irreversible", m.Version, m.Name)
+			}
+
+			log.Printf("Reverting migration %d: %s", m.Version, m.Name)
+			if err := m.Down(ctx, r.db); err != nil {
+				return fmt.Errorf("rollback of migration %d (%s) failed: %w", m.Version, m.Name, err)
+			}
+			if _, err := r.collection.DeleteOne(ctx, bson.M{"_id": m.Version}); err != nil {
+				return fmt.Errorf("failed to remove migration record %d: %w", m.Version, err)
+			}
+			reverted = append(reverted, m)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists every registered migration along with versions that are applied but unknown
+func (r *Runner) Status(ctx context.Context) ([]StatusEntry, error) {
+	records, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]StatusEntry, 0, len(r.migrations))
+	for _, m := range r.migrations {
+		entry := StatusEntry{Version: m.Version, Name: m.Name}
+		if rec, ok := records[m.Version]; ok {
+			entry.Applied = true
+			entry.AppliedAt = rec.AppliedAt
+			entry.Mismatch = rec.Checksum != m.Checksum()
+			delete(records, m.Version)
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, rec := range records {
+		entries = append(entries, StatusEntry{
+			Version:   rec.Version,
+			Name:      rec.Name,
+			Applied:   true,
+			AppliedAt: rec.AppliedAt,
+			Missing:   true,
+		})
+	}
+	return entries, nil
+}
+
+func (r *Runner) applied(ctx context.Context) (map[int64]AppliedMigration, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$ne": lockID}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var list []AppliedMigration
+	if err := cursor.All(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to decode applied migrations: %w", err)
+	}
+
+	records := make(map[int64]AppliedMigration, len(list))
+	for _, rec := range list {
+		records[rec.Version] = rec
+	}
+	return records, nil
+}
+
+// withLock runs fn while holding the lock document in _migrations.
+// A lock older than lockTTL is considered abandoned and can be taken over,
+// so it is renewed while fn runs.
+func (r *Runner) withLock(ctx context.Context, fn func() error) error {
+	now := time.Now()
+	filter := bson.M{
+		"_id": lockID,
+		"$or": bson.A{
+			bson.M{"locked": false},
+			bson.M{"expiresAt": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"locked":    true,
+		"owner":     r.owner,
+		"lockedAt":  now,
+		"expiresAt": now.Add(r.lockTTL),
+	}}
+
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetUpsert(true)).Err()
+	if err != nil && err != mongo.ErrNoDocuments {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrLocked
+		}
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	defer func() {
+		// Release with a fresh context so a cancelled run still unlocks
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := r.collection.UpdateOne(releaseCtx,
+			bson.M{"_id": lockID, "owner": r.owner},
+			bson.M{"$set": bson.M{"locked": false}, "$unset": bson.M{"expiresAt": ""}},
+		)
+		if err != nil {
+			log.Printf("Failed to release migration lock: %v", err)
+		}
+	}()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go r.renewLock(stop)
+
+	return fn()
+}
+
+// renewLock pushes the expiry of the held lock forward every third of
+// lockTTL until stop is closed
+func (r *Runner) renewLock(stop <-chan struct{}) {
+	ticker := time.NewTicker(r.lockTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		result, err := r.collection.UpdateOne(ctx,
+			bson.M{"_id": lockID, "owner": r.owner, "locked": true},
+			bson.M{"$set": bson.M{"expiresAt": time.Now().Add(r.lockTTL)}},
+		)
+		cancel()
+		if err != nil {
+			log.Printf("Failed to renew migration lock: %v", err)
+		} else if result.MatchedCount == 0 {
+			log.Printf("Warning: migration lock was taken over by another instance")
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package password
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	BcryptAlgorithm   = "bcrypt"
+	Argon2idAlgorithm = "argon2id"
+	// PlaintextAlgorithm marks legacy rows that were stored without hashing
+	PlaintextAlgorithm = "plaintext"
+)
+
+// Hasher hashes passwords with one algorithm. Encoded hashes carry their own
+// parameters so a hasher can verify hashes made with other settings.
+type Hasher interface {
+	// Algorithm identifies the encoding produced by Hash
+	Algorithm() string
+	Hash(password string) (string, error)
+	Verify(password, encoded string) bool
+	// NeedsRehash reports whether encoded is weaker than what Hash produces now
+	NeedsRehash(encoded string) bool
+}
+
+type Config struct {
+	// bcrypt or argon2id
+	Algorithm  string
+	BcryptCost int
+	Argon2     Argon2Params
+}
+
+// DefaultConfig hashes new passwords with argon2id
+func DefaultConfig() Config {
+	return Config{
+		Algorithm:  Argon2idAlgorithm,
+		BcryptCost: DefaultBcryptCost,
+		Argon2:     DefaultArgon2Params(),
+	}
+}
+
+// NewHasher builds the configured hasher
+func NewHasher(config Config) (Hasher, error) {
+	switch config.Algorithm {
+	case BcryptAlgorithm:
+		return NewBcryptHasher(config.BcryptCost)
+	case "", Argon2idAlgorithm:
+		return NewArgon2idHasher(config.Argon2)
+	default:
+		return nil, fmt.Errorf("unknown password hasher %q", config.Algorithm)
+	}
+}
+
+// Identify returns the algorithm of a stored password, PlaintextAlgorithm
+// when it is not a known hash
+func Identify(encoded string) string {
+	switch {
+	case isBcrypt(encoded):
+		return BcryptAlgorithm
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return Argon2idAlgorithm
+	}
+	return PlaintextAlgorithm
+}
+
+// IsHashed reports whether a stored password is already hashed
+func IsHashed(encoded string) bool {
+	return Identify(encoded) != PlaintextAlgorithm
+}
+
+// Passwords hashes with the configured hasher and verifies every known
+// encoding, including legacy plaintext rows
+type Passwords struct {
+	hasher    Hasher
+	verifiers map[string]Hasher
+	// Hash giả để thời gian xử lý email không tồn tại giống email có thật
+	dummy string
+}
+
+func New(hasher Hasher) (*Passwords, error) {
+	p := &Passwords{
+		hasher: hasher,
+		verifiers: map[string]Hasher{
+			BcryptAlgorithm:   &BcryptHasher{cost: DefaultBcryptCost},
+			Argon2idAlgorithm: &Argon2idHasher{params: DefaultArgon2Params()},
+		},
+	}
+	p.verifiers[hasher.Algorithm()] = hasher
+
+	dummy, err := hasher.Hash("dummy password for unknown accounts")
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash dummy password: %w", err)
+	}
+	p.dummy = dummy
+	return p, nil
+}
+
+// Hasher is the hasher new passwords are hashed with
+func (p *Passwords) Hasher() Hasher {
+	return p.hasher
+}
+
+func (p *Passwords) Hash(password string) (string, error) {
+	return p.hasher.Hash(password)
+}
+
+// Verify checks password against a stored value. rehash is set when the
+// password matched but the stored value is plaintext, another algorithm or
+// weaker parameters. An empty stored value (unknown account) still costs a
+// full hash verification so that callers cannot tell the cases apart.
+func (p *Passwords) Verify(password, stored string) (ok, rehash bool) {
+	if stored == "" {
+		p.hasher.Verify(password, p.dummy)
+		return false, false
+	}
+
+	algorithm := Identify(stored)
+	if algorithm == PlaintextAlgorithm {
+		p.hasher.Verify(password, p.dummy)
+		// So sánh digest để thời gian không phụ thuộc độ dài mật khẩu
+		a, b := sha256.Sum256([]byte(password)), sha256.Sum256([]byte(stored))
+		ok = subtle.ConstantTimeCompare(a[:], b[:]) == 1
+		return ok, ok
+	}
+
+	if !p.verifiers[algorithm].Verify(password, stored) {
+		return false, false
+	}
+	return true, algorithm != p.hasher.Algorithm() || p.hasher.NeedsRehash(stored)
+}
+
+var (
+	defaultMu        sync.RWMutex
+	defaultPasswords *Passwords
+)
+
+// SetDefault replaces the Passwords returned by Default, normally from main
+func SetDefault(p *Passwords) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultPasswords = p
+}
+
+// Default returns the process-wide Passwords, built from DefaultConfig unless
+// SetDefault was called
+func Default() *Passwords {
+	defaultMu.RLock()
+	p := defaultPasswords
+	defaultMu.RUnlock()
+	if p != nil {
+		return p
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultPasswords == nil {
+		hasher, err := NewHasher(DefaultConfig())
+		if err != nil {
+			panic(err)
+		}
+		if defaultPasswords, err = New(hasher); err != nil {
+			panic(err)
+		}
+	}
+	return defaultPasswords
+}
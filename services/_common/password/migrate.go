@@ -0,0 +1,72 @@
+package password
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MigrateReport struct {
+	Checked int
+	// Already hashed, weaker hashes are upgraded at the next login
+	Hashed   int
+	Migrated int
+	Errors   []string
+}
+
+// MigratePlaintext hashes every legacy plaintext password in users. Rows
+// changed concurrently (e.g. by a login) are left alone.
+func MigratePlaintext(ctx context.Context, db *mongo.Database, p *Passwords, dryRun bool) (*MigrateReport, error) {
+	users := db.Collection("users")
+	cursor, err := users.Find(ctx,
+		bson.M{"password": bson.M{"$type": "string", "$ne": ""}},
+		options.Find().SetProjection(bson.M{"password": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	report := &MigrateReport{}
+	for cursor.Next(ctx) {
+		var row struct {
+			ID       primitive.ObjectID `bson:"_id"`
+			Password string             `bson:"password"`
+		}
+		if err := cursor.Decode(&row); err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			continue
+		}
+		report.Checked++
+		if IsHashed(row.Password) {
+			report.Hashed++
+			continue
+		}
+		if dryRun {
+			report.Migrated++
+			continue
+		}
+
+		hash, err := p.Hash(row.Password)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", row.ID.Hex(), err))
+			continue
+		}
+		result, err := users.UpdateOne(ctx,
+			bson.M{"_id": row.ID, "password": row.Password},
+			bson.M{"$set": bson.M{"password": hash}},
+		)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", row.ID.Hex(), err))
+			continue
+		}
+		if result.ModifiedCount > 0 {
+			report.Migrated++
+		}
+	}
+	return report, cursor.Err()
+}
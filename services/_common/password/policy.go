@@ -0,0 +1,36 @@
+package password
+
+import (
+	"errors"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MinLength = 8
+	// MaxBytes keeps passwords usable with bcrypt, which ignores what follows
+	MaxBytes = 72
+)
+
+// ValidatePolicy checks a new password chosen by a user
+func ValidatePolicy(plain string) error {
+	if utf8.RuneCountInString(plain) < MinLength {
+		return errors.New("password must be at least 8 characters")
+	}
+	if len(plain) > MaxBytes {
+		return errors.New("password must be at most 72 bytes")
+	}
+	var letter, digit bool
+	for _, r := range plain {
+		switch {
+		case unicode.IsLetter(r):
+			letter = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	if !letter || !digit {
+		return errors.New("password must contain letters and digits")
+	}
+	return nil
+}
@@ -32,6 +32,10 @@ func (s *CommonService) Aggregate(ctx context.Context, req *pb.AggregateRequest)
 		stageDoc := helper.StructToDoc(stage)
 		pipeline = append(pipeline, stageDoc)
 	}
+	pipeline, err := hidePipeline(req.EntityType, pipeline)
+	if err != nil {
+		return nil, err
+	}
 
 	return s.adapter.Aggregate(ctx, req.EntityType, req.AllowDiskUse, req.MaxTimeMs, pipeline)
 }
@@ -0,0 +1,237 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	pb "thaily/proto/common"
+	"thaily/services/_common/assignment"
+	"thaily/services/_common/audit"
+	"thaily/services/_common/hooks"
+	"thaily/services/_common/workflow"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// action của event_logs cho mỗi lần phân công
+const assignSupervisorAction = "assign_supervisor"
+
+func (s *CommonService) AssignSupervisors(ctx context.Context, req *pb.AssignSupervisorsRequest) (*pb.AssignSupervisorsResponse, error) {
+	if req.AcademicYear == "" || req.Semester <= 0 {
+		return &pb.AssignSupervisorsResponse{
+			Success: false,
+			Message: "academic_year and semester are required",
+		}, nil
+	}
+
+	thesisIDs, invalid := parseObjectIDs(req.ThesisIds)
+	if len(invalid) > 0 {
+		return &pb.AssignSupervisorsResponse{
+			Success: false,
+			Message: fmt.Sprintf("invalid thesis_ids: %v", invalid),
+		}, nil
+	}
+	supervisorIDs, invalid := parseObjectIDs(req.SupervisorIds)
+	if len(invalid) > 0 {
+		return &pb.AssignSupervisorsResponse{
+			Success: false,
+			Message: fmt.Sprintf("invalid supervisor_ids: %v", invalid),
+		}, nil
+	}
+
+	capacities := make(map[string]assignment.Capacity, len(req.Capacities))
+	for _, c := range req.Capacities {
+		capacities[c.SupervisorId] = assignment.Capacity{MaxPrimary: int(c.MaxPrimary), MaxCo: int(c.MaxCo)}
+	}
+	defaultCapacity := int(req.DefaultCapacity)
+	if defaultCapacity <= 0 {
+		defaultCapacity = assignment.DefaultCapacity
+	}
+
+	term := assignment.Term{AcademicYear: req.AcademicYear, Semester: int(req.Semester)}
+	loader := assignment.NewLoader(s.adapter.GetDatabase())
+	theses, skipped, err := loader.Theses(ctx, term, thesisIDs)
+	if err != nil {
+		return &pb.AssignSupervisorsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+	supervisors, err := loader.Supervisors(ctx, term, supervisorIDs, capacities, defaultCapacity)
+	if err != nil {
+		return &pb.AssignSupervisorsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	result := assignment.Solve(theses, supervisors, assignment.Options{
+		WithCoSupervisors:    req.WithCoSupervisors,
+		AllowCrossDepartment: req.AllowCrossDepartment,
+	})
+	resp := assignmentResponse(result, skipped)
+	resp.Preview = req.Preview
+
+	if req.Preview || len(result.Assignments) == 0 {
+		resp.Success = true
+		resp.Message = fmt.Sprintf("%d assignments proposed, %d unassigned", len(resp.Assignments), len(resp.Unassigned))
+		return resp, nil
+	}
+
+	// Chỉ giáo vụ được ghi phân công, bản xem trước thì ai cũng xem được
+	actor, ok := workflow.ActorFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "access token is required")
+	}
+	if !actor.IsStaff() {
+		return nil, status.Error(codes.PermissionDenied, "only staff can commit supervisor assignments")
+	}
+
+	runID := primitive.NewObjectID()
+	var ops []*hooks.Operation
+	var updated []*structpb.Struct
+	err = s.withHooks(ctx, "theses", func(ctx context.Context) error {
+		ops, updated = nil, nil
+		now := time.Now()
+		docs := make([]interface{}, len(result.Assignments))
+		for i, a := range result.Assignments {
+			thesisID, _ := primitive.ObjectIDFromHex(a.ThesisID)
+			supervisorID, _ := primitive.ObjectIDFromHex(a.SupervisorID)
+
+			if a.Role == assignment.RolePrimary {
+				op := s.newOperation(ctx, "theses", hooks.BeforeUpdate, req.Meta)
+				op.ID = a.ThesisID
+				op.Data = map[string]interface{}{
+					"supervisor_id": supervisorID,
+					"updatedAt":     now,
+				}
+				updateResp, err := s.applyUpdate(ctx, op, thesisID)
+				if errors.Is(err, errWriteFailed) {
+					return &hooks.Rejection{Message: fmt.Sprintf("thesis %s: %s", a.ThesisID, updateResp.Message), Details: updateResp.Errors}
+				}
+				if err != nil {
+					return err
+				}
+				ops = append(ops, op)
+				updated = append(updated, updateResp.Entity)
+			}
+
+			var assignedBy interface{}
+			if id, err := primitive.ObjectIDFromHex(actor.UserID); err == nil {
+				assignedBy = id
+			}
+			docs[i] = bson.M{
+				"thesis_id":         thesisID,
+				"supervisor_id":     supervisorID,
+				"role":              a.Role,
+				"score":             a.Score,
+				"assigned_at":       now,
+				"assigned_by":       assignedBy,
+				"assignment_run_id": runID,
+				"is_active":         true,
+				"createdAt":         now,
+				"updatedAt":         now,
+			}
+		}
+
+		created, err := s.adapter.CreateMany(ctx, assignment.AssignmentsCollection, docs, true)
+		if err != nil {
+			return err
+		}
+		if !created.Success || len(created.Ids) != len(docs) {
+			return &hooks.Rejection{Message: created.Message, Details: created.Errors}
+		}
+		for i, id := range created.Ids {
+			resp.Assignments[i].AssignmentId = id
+		}
+
+		return audit.Log(ctx, s.adapter.GetDatabase(), audit.Entry{
+			UserID:     actor.UserID,
+			Action:     assignSupervisorAction,
+			EntityType: assignment.AssignmentsCollection,
+			EntityID:   runID,
+			Details: bson.M{
+				"academic_year":          req.AcademicYear,
+				"semester":               req.Semester,
+				"assignment_ids":         created.Ids,
+				"assigned":               len(created.Ids),
+				"unassigned":             len(resp.Unassigned),
+				"with_co_supervisors":    req.WithCoSupervisors,
+				"allow_cross_department": req.AllowCrossDepartment,
+				"default_capacity":       defaultCapacity,
+			},
+		})
+	})
+	if err != nil {
+		rejection := hooks.AsRejection(err)
+		resp.Message = fmt.Sprintf("failed to commit assignment: %s", rejection.Message)
+		resp.Errors = rejection.Details
+		for _, a := range resp.Assignments {
+			a.AssignmentId = ""
+		}
+		return resp, nil
+	}
+
+	for _, op := range ops {
+		op.Commit(ctx)
+	}
+	s.indexSearch("theses", updated...)
+
+	resp.Success = true
+	resp.RunId = runID.Hex()
+	resp.Message = fmt.Sprintf("%d assignments created, %d unassigned", len(resp.Assignments), len(resp.Unassigned))
+	return resp, nil
+}
+
+func assignmentResponse(result assignment.Result, skipped []assignment.Unassigned) *pb.AssignSupervisorsResponse {
+	resp := &pb.AssignSupervisorsResponse{
+		Assignments: make([]*pb.ProposedAssignment, len(result.Assignments)),
+		Unassigned:  []*pb.UnassignedThesis{},
+		Loads:       make([]*pb.SupervisorLoad, len(result.Supervisors)),
+	}
+	for i, a := range result.Assignments {
+		resp.Assignments[i] = &pb.ProposedAssignment{
+			ThesisId:     a.ThesisID,
+			SupervisorId: a.SupervisorID,
+			Role:         a.Role,
+			Score:        a.Score,
+			Reasons:      a.Reasons,
+		}
+	}
+	for _, u := range append(skipped, result.Unassigned...) {
+		resp.Unassigned = append(resp.Unassigned, &pb.UnassignedThesis{
+			ThesisId: u.ThesisID,
+			Role:     u.Role,
+			Reason:   u.Reason,
+		})
+	}
+	for i, s := range result.Supervisors {
+		resp.Loads[i] = &pb.SupervisorLoad{
+			SupervisorId: s.ID,
+			Primary:      int32(s.Primary),
+			Co:           int32(s.Co),
+			MaxPrimary:   int32(s.MaxPrimary),
+			MaxCo:        int32(s.MaxCo),
+		}
+	}
+	return resp
+}
+
+func parseObjectIDs(values []string) ([]primitive.ObjectID, []string) {
+	ids := make([]primitive.ObjectID, 0, len(values))
+	var invalid []string
+	for _, value := range values {
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			invalid = append(invalid, value)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, invalid
+}
@@ -2,9 +2,15 @@ package resolvers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	pb "thaily/proto/common"
 	"thaily/services/_common/helper"
+	"thaily/services/_common/hooks"
+	"thaily/services/_common/integrity"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func (s *CommonService) Create(ctx context.Context, req *pb.GenericRequest) (*pb.GenericResponse, error) {
@@ -27,7 +33,53 @@ func (s *CommonService) Create(ctx context.Context, req *pb.GenericRequest) (*pb
 	doc["createdAt"] = now
 	doc["updatedAt"] = now
 
-	return s.adapter.Create(ctx, req.EntityType, doc)
+	op := s.newOperation(ctx, req.EntityType, hooks.BeforeCreate, req.Meta)
+	op.Data = doc
+
+	var resp *pb.GenericResponse
+	err := s.withHooks(ctx, req.EntityType, func(ctx context.Context) error {
+		if err := hooks.Run(ctx, op); err != nil {
+			return err
+		}
+
+		if message, details := s.checkReferences(ctx, req.EntityType, doc); message != "" {
+			return &hooks.Rejection{Message: message, Details: details}
+		}
+
+		if err := s.sequence.AssignCodes(ctx, req.EntityType, doc); err != nil {
+			return hooks.Reject(fmt.Sprintf("failed to generate code: %v", err))
+		}
+
+		var err error
+		resp, err = s.adapter.Create(ctx, req.EntityType, doc)
+		if err != nil {
+			return err
+		}
+		if !resp.Success {
+			return errWriteFailed
+		}
+
+		after := op.Next(hooks.AfterCreate)
+		after.ID = resp.Id.GetValue()
+		after.Entity = resp.Entity.AsMap()
+		return hooks.Run(ctx, after)
+	})
+	if errors.Is(err, errWriteFailed) {
+		return resp, nil
+	}
+	if err != nil {
+		rejection := hooks.AsRejection(err)
+		return &pb.GenericResponse{
+			Success: false,
+			Message: rejection.Message,
+			Errors:  rejection.Details,
+		}, nil
+	}
+
+	op.Commit(ctx)
+	s.indexSearch(req.EntityType, resp.Entity)
+	hideEntity(req.EntityType, resp.Entity)
+	return resp, nil
 }
 
 func (s *CommonService) CreateMany(ctx context.Context, req *pb.BatchRequest) (*pb.BatchResponse, error) {
@@ -45,14 +97,174 @@ func (s *CommonService) CreateMany(ctx context.Context, req *pb.BatchRequest) (*
 		}, nil
 	}
 
+	withHooks := hooks.Has(req.EntityType)
 	docs := make([]interface{}, len(req.Entities))
+	checked := make([]map[string]interface{}, len(req.Entities))
+	ops := make([]*hooks.Operation, len(req.Entities))
 	for i, entity := range req.Entities {
 		doc := helper.StructToDoc(entity)
 		now := time.Now()
 		doc["createdAt"] = now
 		doc["updatedAt"] = now
+		if _, ok := doc["_id"]; !ok && withHooks {
+			// Gán trước _id để After hook biết document nào đã được tạo
+			doc["_id"] = primitive.NewObjectID()
+		}
 		docs[i] = doc
+		checked[i] = doc
+
+		ops[i] = s.newOperation(ctx, req.EntityType, hooks.BeforeCreate, req.Meta)
+		ops[i].Data = doc
 	}
 
-	return s.adapter.CreateMany(ctx, req.EntityType, docs, req.Ordered)
+	var resp *pb.BatchResponse
+	err := s.withHooks(ctx, req.EntityType, func(ctx context.Context) error {
+		for i, op := range ops {
+			if err := hooks.Run(ctx, op); err != nil {
+				rejection := hooks.AsRejection(err)
+				for _, detail := range rejection.Details {
+					detail.Index = int32(i)
+				}
+				return rejection
+			}
+		}
+
+		violations, err := s.integrity.CheckReferences(ctx, req.EntityType, checked...)
+		if err != nil {
+			return fmt.Errorf("failed to check references: %w", err)
+		}
+		if len(violations) > 0 {
+			errs := make([]*pb.ErrorDetail, len(violations))
+			for i, v := range violations {
+				errs[i] = violationToProto(v)
+				errs[i].Field = fmt.Sprintf("entities[%d].%s", v.Index, v.Field)
+			}
+			return &hooks.Rejection{
+				Message: "some entities reference documents that do not exist",
+				Details: errs,
+			}
+		}
+
+		if err := s.sequence.AssignCodes(ctx, req.EntityType, checked...); err != nil {
+			return hooks.Reject(fmt.Sprintf("failed to generate codes: %v", err))
+		}
+
+		resp, err = s.adapter.CreateMany(ctx, req.EntityType, docs, req.Ordered)
+		if err != nil {
+			return err
+		}
+		if !resp.Success && withHooks {
+			// Trong transaction, lỗi một phần sẽ hủy toàn bộ batch
+			return errWriteFailed
+		}
+
+		return s.runAfterCreateMany(ctx, req.EntityType, ops, resp)
+	})
+	if errors.Is(err, errWriteFailed) {
+		resp.Message = "Batch insert failed, no entities were created"
+		resp.Ids = []string{}
+		resp.CreatedCount = 0
+		return resp, nil
+	}
+	if err != nil {
+		rejection := hooks.AsRejection(err)
+		return &pb.BatchResponse{
+			Success: false,
+			Message: rejection.Message,
+			Errors:  rejection.Details,
+		}, nil
+	}
+
+	for _, op := range ops {
+		op.Commit(ctx)
+	}
+	if len(resp.Entities) > 0 {
+		s.indexSearch(req.EntityType, resp.Entities...)
+	} else {
+		// Partial inserts only return ids
+		s.reindexSearch(ctx, req.EntityType, resp.Ids)
+	}
+	hideEntity(req.EntityType, resp.Entities...)
+	return resp, nil
+}
+
+// runAfterCreateMany runs the AfterCreate hooks of the documents that were
+// inserted, matched through their pre-assigned ids
+func (s *CommonService) runAfterCreateMany(ctx context.Context, entityType string, ops []*hooks.Operation, resp *pb.BatchResponse) error {
+	if !hooks.Has(entityType) {
+		return nil
+	}
+
+	entities := make(map[string]map[string]interface{}, len(resp.Ids))
+	for _, entity := range resp.Entities {
+		if entity == nil {
+			continue
+		}
+		if id, ok := entity.Fields["_id"]; ok {
+			entities[id.GetStringValue()] = entity.AsMap()
+		}
+	}
+	if len(entities) == 0 && len(resp.Ids) > 0 {
+		// Partial inserts only return ids
+		ids := make([]primitive.ObjectID, 0, len(resp.Ids))
+		for _, idStr := range resp.Ids {
+			if id, err := primitive.ObjectIDFromHex(idStr); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		found, err := s.adapter.FindByIds(ctx, entityType, ids, nil)
+		if err != nil {
+			return err
+		}
+		for id, entity := range found {
+			entities[id] = entity.AsMap()
+		}
+	}
+
+	for _, op := range ops {
+		id, ok := op.Data["_id"].(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		entity, ok := entities[id.Hex()]
+		if !ok {
+			continue
+		}
+
+		after := op.Next(hooks.AfterCreate)
+		after.ID = id.Hex()
+		after.Entity = entity
+		if err := hooks.Run(ctx, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkReferences describes the dangling references in doc, returning an
+// empty message when all declared references exist
+func (s *CommonService) checkReferences(ctx context.Context, entityType string, doc map[string]interface{}) (string, []*pb.ErrorDetail) {
+	violations, err := s.integrity.CheckReferences(ctx, entityType, doc)
+	if err != nil {
+		return fmt.Sprintf("failed to check references: %v", err), nil
+	}
+	if len(violations) == 0 {
+		return "", nil
+	}
+
+	details := make([]*pb.ErrorDetail, len(violations))
+	for i, v := range violations {
+		details[i] = violationToProto(v)
+	}
+	return violations[0].Error(), details
+}
+
+func violationToProto(v integrity.Violation) *pb.ErrorDetail {
+	return &pb.ErrorDetail{
+		Code:    "REFERENCE_NOT_FOUND",
+		Field:   v.Field,
+		Value:   v.Value,
+		Index:   int32(v.Index),
+		Message: v.Error(),
+	}
 }
@@ -0,0 +1,225 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	pb "thaily/proto/common"
+	"thaily/services/_common/defense"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *CommonService) ScheduleDefenses(ctx context.Context, req *pb.ScheduleDefensesRequest) (*pb.ScheduleDefensesResponse, error) {
+	if len(req.ThesisIds) == 0 && (req.AcademicYear == "" || req.Semester <= 0) {
+		return &pb.ScheduleDefensesResponse{
+			Success: false,
+			Message: "thesis_ids or academic_year and semester are required",
+		}, nil
+	}
+	if len(req.Rooms) == 0 {
+		return &pb.ScheduleDefensesResponse{
+			Success: false,
+			Message: "at least one room is required",
+		}, nil
+	}
+	windows, err := toIntervals(req.Windows)
+	if err != nil || len(windows) == 0 {
+		message := "at least one defense window is required"
+		if err != nil {
+			message = fmt.Sprintf("invalid windows: %v", err)
+		}
+		return &pb.ScheduleDefensesResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+	if _, invalid := parseObjectIDs(req.ThesisIds); len(invalid) > 0 {
+		return &pb.ScheduleDefensesResponse{
+			Success: false,
+			Message: fmt.Sprintf("invalid thesis_ids: %v", invalid),
+		}, nil
+	}
+
+	slot := defense.DefaultDuration
+	if req.SlotMinutes > 0 {
+		slot = time.Duration(req.SlotMinutes) * time.Minute
+	}
+	span := windows[0]
+	for _, w := range windows[1:] {
+		if w.Start.Before(span.Start) {
+			span.Start = w.Start
+		}
+		if w.End.After(span.End) {
+			span.End = w.End
+		}
+	}
+
+	store := defense.NewStore(s.adapter.GetDatabase())
+	var defenses []defense.Defense
+	var missing []defense.Unscheduled
+	if len(req.ThesisIds) > 0 {
+		found, err := store.Defenses(ctx, req.ThesisIds)
+		if err != nil {
+			return &pb.ScheduleDefensesResponse{Success: false, Message: err.Error()}, nil
+		}
+		for _, id := range req.ThesisIds {
+			d, ok := found[id]
+			if !ok {
+				missing = append(missing, defense.Unscheduled{ThesisID: id, Reason: "thesis not found"})
+				continue
+			}
+			defenses = append(defenses, d)
+		}
+	} else {
+		defenses, err = store.Pending(ctx, req.AcademicYear, int(req.Semester))
+		if err != nil {
+			return &pb.ScheduleDefensesResponse{Success: false, Message: err.Error()}, nil
+		}
+	}
+
+	members := req.MemberIds
+	if len(members) == 0 {
+		if members, err = store.Members(ctx); err != nil {
+			return &pb.ScheduleDefensesResponse{Success: false, Message: err.Error()}, nil
+		}
+	}
+	existing, err := store.Overlapping(ctx, span)
+	if err != nil {
+		return &pb.ScheduleDefensesResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	// Lịch bận gồm cả giảng viên hướng dẫn vì họ có thể ngồi hội đồng
+	busy := append([]string{}, members...)
+	for _, d := range defenses {
+		busy = append(busy, d.SupervisorID)
+	}
+	unavailable, err := store.Unavailable(ctx, busy, span)
+	if err != nil {
+		return &pb.ScheduleDefensesResponse{Success: false, Message: err.Error()}, nil
+	}
+	for _, a := range req.Availability {
+		intervals, err := toIntervals(a.Unavailable)
+		if err != nil {
+			return &pb.ScheduleDefensesResponse{
+				Success: false,
+				Message: fmt.Sprintf("invalid availability of %s: %v", a.MemberId, err),
+			}, nil
+		}
+		unavailable[a.MemberId] = append(unavailable[a.MemberId], intervals...)
+	}
+
+	rooms := make([]defense.Room, len(req.Rooms))
+	for i, r := range req.Rooms {
+		rooms[i] = defense.Room{Location: r.Location, Building: r.Building}
+	}
+
+	solution := defense.Solve(defense.Problem{
+		Defenses:     defenses,
+		Windows:      windows,
+		SlotDuration: slot,
+		Rooms:        rooms,
+		Members:      members,
+		Existing:     existing,
+		Rules: defense.Rules{
+			CommitteeSize: int(req.CommitteeSize),
+			Windows:       windows,
+			Unavailable:   unavailable,
+		},
+		SupervisorInCommittee: req.SupervisorInCommittee,
+	})
+	resp := scheduleResponse(solution, missing)
+	resp.Preview = req.Preview
+
+	if req.Preview || len(solution.Schedules) == 0 {
+		resp.Success = true
+		resp.Message = fmt.Sprintf("%d defenses proposed, %d unscheduled", len(resp.Schedules), len(resp.Unscheduled))
+		return resp, nil
+	}
+
+	// Ghi qua CreateMany để hook kiểm tra lại từng lịch và gán mã
+	entities := make([]*structpb.Struct, len(solution.Schedules))
+	for i, schedule := range solution.Schedules {
+		committee := make([]interface{}, len(schedule.Committee))
+		for j, seat := range schedule.Committee {
+			committee[j] = map[string]interface{}{"member_id": seat.MemberID, "role": seat.Role}
+		}
+		entity, err := structpb.NewStruct(map[string]interface{}{
+			"thesis_id":        schedule.ThesisID,
+			"defense_date":     schedule.Start.Format(time.RFC3339),
+			"duration_minutes": int(schedule.End.Sub(schedule.Start) / time.Minute),
+			"location":         schedule.Room.Location,
+			"building":         schedule.Room.Building,
+			"status":           defense.StatusScheduled,
+			"committee":        committee,
+		})
+		if err != nil {
+			return &pb.ScheduleDefensesResponse{Success: false, Message: err.Error()}, nil
+		}
+		entities[i] = entity
+	}
+
+	created, err := s.CreateMany(ctx, &pb.BatchRequest{
+		EntityType: defense.SchedulesCollection,
+		Entities:   entities,
+		Ordered:    true,
+		Meta:       req.Meta,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !created.Success || len(created.Ids) != len(entities) {
+		resp.Message = fmt.Sprintf("failed to save defense schedules: %s", created.Message)
+		resp.Errors = created.Errors
+		return resp, nil
+	}
+	for i, id := range created.Ids {
+		resp.Schedules[i].ScheduleId = id
+	}
+
+	resp.Success = true
+	resp.Message = fmt.Sprintf("%d defenses scheduled, %d unscheduled", len(resp.Schedules), len(resp.Unscheduled))
+	return resp, nil
+}
+
+func scheduleResponse(solution defense.Solution, missing []defense.Unscheduled) *pb.ScheduleDefensesResponse {
+	resp := &pb.ScheduleDefensesResponse{
+		Schedules:   make([]*pb.ProposedDefense, len(solution.Schedules)),
+		Unscheduled: []*pb.UnscheduledThesis{},
+		Complete:    solution.Complete,
+	}
+	for i, schedule := range solution.Schedules {
+		seats := make([]*pb.CommitteeSeat, len(schedule.Committee))
+		for j, seat := range schedule.Committee {
+			seats[j] = &pb.CommitteeSeat{MemberId: seat.MemberID, Role: seat.Role}
+		}
+		resp.Schedules[i] = &pb.ProposedDefense{
+			ThesisId:  schedule.ThesisID,
+			Start:     timestamppb.New(schedule.Start),
+			End:       timestamppb.New(schedule.End),
+			Location:  schedule.Room.Location,
+			Building:  schedule.Room.Building,
+			Committee: seats,
+		}
+	}
+	for _, u := range append(missing, solution.Unscheduled...) {
+		resp.Unscheduled = append(resp.Unscheduled, &pb.UnscheduledThesis{ThesisId: u.ThesisID, Reason: u.Reason})
+	}
+	return resp
+}
+
+func toIntervals(windows []*pb.TimeWindow) ([]defense.Interval, error) {
+	intervals := make([]defense.Interval, 0, len(windows))
+	for _, w := range windows {
+		if w.GetStart() == nil || w.GetEnd() == nil {
+			return nil, fmt.Errorf("start and end are required")
+		}
+		interval := defense.Interval{Start: w.Start.AsTime(), End: w.End.AsTime()}
+		if !interval.End.After(interval.Start) {
+			return nil, fmt.Errorf("%s must end after it starts", interval.Start.Format(time.RFC3339))
+		}
+		intervals = append(intervals, interval)
+	}
+	return intervals, nil
+}
@@ -2,12 +2,19 @@ package resolvers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	pb "thaily/proto/common"
 	"thaily/services/_common/helper"
+	"thaily/services/_common/hooks"
+	"thaily/services/_common/integrity"
+	"thaily/services/_common/schema"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func (s *CommonService) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
@@ -33,7 +40,89 @@ func (s *CommonService) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.
 		}, nil
 	}
 
-	return s.adapter.Delete(ctx, req.EntityType, id)
+	if !req.DryRun && len(schema.Dependents(req.EntityType)) == 0 && !hooks.Has(req.EntityType) {
+		resp, err := s.adapter.Delete(ctx, req.EntityType, id)
+		if err != nil || !resp.Success {
+			return resp, err
+		}
+
+		s.unindexSearch(req.EntityType, req.Id)
+		s.deleteAttachments(ctx, req.EntityType, req.Id)
+		return resp, nil
+	}
+
+	targetIDs, err := s.adapter.ResolveIds(ctx, req.EntityType, []primitive.ObjectID{id}, nil)
+	if err != nil {
+		return &pb.DeleteResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+	if len(targetIDs) == 0 {
+		return &pb.DeleteResponse{
+			Success: false,
+			Message: "Entity not found",
+		}, nil
+	}
+
+	if req.DryRun {
+		plan, err := s.planDelete(ctx, req.EntityType, targetIDs)
+		if err != nil {
+			return &pb.DeleteResponse{
+				Success:    false,
+				Message:    err.Error(),
+				Dependents: dependentsToProto(plan),
+			}, nil
+		}
+		return &pb.DeleteResponse{
+			Success:      true,
+			Message:      "Dry run, nothing was deleted",
+			DeletedCount: 1,
+			Dependents:   dependentsToProto(plan),
+		}, nil
+	}
+
+	// Kế hoạch được tính trong transaction để phụ thuộc không đổi giữa lúc đọc và lúc xóa
+	var plan *integrity.Plan
+	var ops []*hooks.Operation
+	var resp *pb.DeleteResponse
+	err = s.adapter.RunInTransaction(ctx, func(ctx context.Context) error {
+		plan, ops, resp = nil, nil, nil
+		var err error
+		if plan, err = s.planDelete(ctx, req.EntityType, targetIDs); err != nil {
+			return err
+		}
+		ops = s.deleteOperations(ctx, plan, req.Meta)
+		if err := runDeleteHooks(ctx, ops, hooks.BeforeDelete); err != nil {
+			return err
+		}
+		if err := s.integrity.Apply(ctx, plan); err != nil {
+			return err
+		}
+		resp, err = s.adapter.Delete(ctx, req.EntityType, id)
+		if err == nil && !resp.Success {
+			err = errors.New(resp.Message)
+		}
+		if err != nil {
+			return err
+		}
+		return runDeleteHooks(ctx, ops, hooks.AfterDelete)
+	})
+	dependents := dependentsToProto(plan)
+	if err != nil {
+		return &pb.DeleteResponse{
+			Success:    false,
+			Message:    deleteErrorMessage("failed to delete entity", err),
+			Dependents: dependents,
+		}, nil
+	}
+	resp.Dependents = dependents
+	commitOperations(ctx, ops)
+
+	s.unindexSearch(req.EntityType, req.Id)
+	s.deleteAttachments(ctx, req.EntityType, req.Id)
+	s.cleanupCascaded(ctx, plan)
+	return resp, nil
 }
 
 func (s *CommonService) DeleteMany(ctx context.Context, req *pb.DeleteManyRequest) (*pb.DeleteManyResponse, error) {
@@ -52,10 +141,8 @@ func (s *CommonService) DeleteMany(ctx context.Context, req *pb.DeleteManyReques
 	}
 
 	objectIDs := make([]primitive.ObjectID, 0, len(req.Ids))
+	failedIDs := make([]string, 0)
 	if len(req.Ids) > 0 {
-		objectIDs := make([]primitive.ObjectID, 0, len(req.Ids))
-		failedIDs := make([]string, 0)
-
 		for _, idStr := range req.Ids {
 			id, err := primitive.ObjectIDFromHex(idStr)
 			if err != nil {
@@ -79,5 +166,238 @@ func (s *CommonService) DeleteMany(ctx context.Context, req *pb.DeleteManyReques
 		pipeline = append(pipeline, stageDoc)
 	}
 
-	return s.adapter.DeleteMany(ctx, req.EntityType, objectIDs, pipeline)
+	if !req.DryRun && len(schema.Dependents(req.EntityType)) == 0 && !hooks.Has(req.EntityType) {
+		resp, err := s.adapter.DeleteMany(ctx, req.EntityType, objectIDs, pipeline)
+		if err != nil || !resp.Success {
+			return resp, err
+		}
+		resp.FailedIds = append(resp.FailedIds, failedIDs...)
+
+		s.unindexSearch(req.EntityType, resp.DeletedIds...)
+		s.deleteAttachments(ctx, req.EntityType, resp.DeletedIds...)
+		return resp, nil
+	}
+
+	// Chốt danh sách id trước để kế hoạch xóa và lệnh xóa áp dụng cùng một tập
+	targetIDs, err := s.adapter.ResolveIds(ctx, req.EntityType, objectIDs, pipeline)
+	if err != nil {
+		return &pb.DeleteManyResponse{
+			Success:   false,
+			Message:   err.Error(),
+			FailedIds: failedIDs,
+		}, nil
+	}
+	if len(targetIDs) == 0 {
+		return &pb.DeleteManyResponse{
+			Success:    true,
+			Message:    "No entities matched",
+			FailedIds:  failedIDs,
+			DeletedIds: []string{},
+		}, nil
+	}
+
+	if req.DryRun {
+		plan, err := s.planDelete(ctx, req.EntityType, targetIDs)
+		if err != nil {
+			return &pb.DeleteManyResponse{
+				Success:    false,
+				Message:    err.Error(),
+				FailedIds:  failedIDs,
+				Dependents: dependentsToProto(plan),
+			}, nil
+		}
+		deletedIDs := make([]string, len(targetIDs))
+		for i, id := range targetIDs {
+			deletedIDs[i] = id.Hex()
+		}
+		return &pb.DeleteManyResponse{
+			Success:      true,
+			Message:      "Dry run, nothing was deleted",
+			DeletedCount: int32(len(targetIDs)),
+			FailedIds:    failedIDs,
+			DeletedIds:   deletedIDs,
+			Dependents:   dependentsToProto(plan),
+		}, nil
+	}
+
+	var plan *integrity.Plan
+	var ops []*hooks.Operation
+	var resp *pb.DeleteManyResponse
+	err = s.adapter.RunInTransaction(ctx, func(ctx context.Context) error {
+		plan, ops, resp = nil, nil, nil
+		var err error
+		if plan, err = s.planDelete(ctx, req.EntityType, targetIDs); err != nil {
+			return err
+		}
+		ops = s.deleteOperations(ctx, plan, req.Meta)
+		if err := runDeleteHooks(ctx, ops, hooks.BeforeDelete); err != nil {
+			return err
+		}
+		if err := s.integrity.Apply(ctx, plan); err != nil {
+			return err
+		}
+		resp, err = s.adapter.DeleteMany(ctx, req.EntityType, targetIDs, nil)
+		if err == nil && !resp.Success {
+			err = errors.New(resp.Message)
+		}
+		if err != nil {
+			return err
+		}
+		return runDeleteHooks(ctx, ops, hooks.AfterDelete)
+	})
+	dependents := dependentsToProto(plan)
+	if err != nil {
+		return &pb.DeleteManyResponse{
+			Success:    false,
+			Message:    deleteErrorMessage("failed to delete entities", err),
+			FailedIds:  failedIDs,
+			Dependents: dependents,
+		}, nil
+	}
+	resp.FailedIds = append(resp.FailedIds, failedIDs...)
+	resp.Dependents = dependents
+	commitOperations(ctx, ops)
+
+	s.unindexSearch(req.EntityType, resp.DeletedIds...)
+	s.deleteAttachments(ctx, req.EntityType, resp.DeletedIds...)
+	s.cleanupCascaded(ctx, plan)
+	return resp, nil
+}
+
+// planDelete plans the delete of ids. When references restrict it, the plan
+// is returned with the error so the dependents can still be reported.
+func (s *CommonService) planDelete(ctx context.Context, entityType string, ids []primitive.ObjectID) (*integrity.Plan, error) {
+	plan, err := s.integrity.PlanDelete(ctx, entityType, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check dependents: %w", err)
+	}
+	return plan, plan.Error()
+}
+
+// deleteOperations prepares one hook operation per document touched by plan:
+// the requested documents and the cascaded ones as deletes, the documents
+// whose references are nulled as updates. The nulling is written by
+// integrity.Apply, so Before hooks of those updates can reject it but not
+// change it, and their After hooks see no Entity.
+func (s *CommonService) deleteOperations(ctx context.Context, plan *integrity.Plan, meta *structpb.Struct) []*hooks.Operation {
+	var ops []*hooks.Operation
+	deleted := func(entityType string, ids []primitive.ObjectID) {
+		if !hooks.Has(entityType) {
+			return
+		}
+		for _, id := range ids {
+			op := s.newOperation(ctx, entityType, hooks.BeforeDelete, meta)
+			op.ID = id.Hex()
+			ops = append(ops, op)
+		}
+	}
+	deleted(plan.Entity, plan.IDs)
+
+	// Một document có thể bị gán null qua nhiều trường, gộp lại thành một update
+	nulled := map[string]*hooks.Operation{}
+	now := time.Now()
+	for _, step := range plan.Steps {
+		if step.Action == schema.Cascade {
+			deleted(step.Entity, step.IDs)
+			continue
+		}
+		if step.Action != schema.SetNull || !hooks.Has(step.Entity) {
+			continue
+		}
+		for _, id := range step.IDs {
+			key := step.Entity + "/" + id.Hex()
+			op, ok := nulled[key]
+			if !ok {
+				op = s.newOperation(ctx, step.Entity, hooks.BeforeUpdate, meta)
+				op.ID = id.Hex()
+				op.Data = map[string]interface{}{"updatedAt": now}
+				nulled[key] = op
+				ops = append(ops, op)
+			}
+			op.Data[step.Field] = nil
+		}
+	}
+	return ops
+}
+
+// runDeleteHooks runs the Before or After hooks of the operations of a delete.
+// event is the delete event; nulled documents get the matching update event.
+func runDeleteHooks(ctx context.Context, ops []*hooks.Operation, event hooks.Event) error {
+	for _, op := range ops {
+		next := event
+		if op.Event == hooks.BeforeUpdate {
+			next = hooks.BeforeUpdate
+			if event == hooks.AfterDelete {
+				next = hooks.AfterUpdate
+			}
+		}
+		if err := hooks.Run(ctx, op.Next(next)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func commitOperations(ctx context.Context, ops []*hooks.Operation) {
+	for _, op := range ops {
+		op.Commit(ctx)
+	}
+}
+
+// deleteErrorMessage keeps the message of a hook rejection or a restricted
+// delete as is
+func deleteErrorMessage(prefix string, err error) string {
+	var rejection *hooks.Rejection
+	if errors.As(err, &rejection) {
+		return rejection.Message
+	}
+	if errors.Is(err, integrity.ErrRestricted) {
+		return err.Error()
+	}
+	return fmt.Sprintf("%s: %v", prefix, err)
+}
+
+// cleanupCascaded keeps the search index and attachments in sync with
+// documents removed by cascade
+func (s *CommonService) cleanupCascaded(ctx context.Context, plan *integrity.Plan) {
+	for entityType, ids := range plan.Cascaded() {
+		s.unindexSearch(entityType, ids...)
+		s.deleteAttachments(ctx, entityType, ids...)
+	}
+}
+
+func dependentsToProto(plan *integrity.Plan) []*pb.DependentEffect {
+	if plan == nil {
+		return nil
+	}
+	result := make([]*pb.DependentEffect, len(plan.Steps))
+	for i, step := range plan.Steps {
+		ids := make([]string, len(step.IDs))
+		for j, id := range step.IDs {
+			ids[j] = id.Hex()
+		}
+		result[i] = &pb.DependentEffect{
+			EntityType: step.Entity,
+			Field:      step.Field,
+			Action:     string(step.Action),
+			Count:      int32(len(step.IDs)),
+			Ids:        ids,
+		}
+	}
+	return result
+}
+
+// deleteAttachments removes files that would be orphaned by deleting their owners
+func (s *CommonService) deleteAttachments(ctx context.Context, entityType string, ids ...string) {
+	if s.fileStore == nil || len(ids) == 0 {
+		return
+	}
+	count, err := s.fileStore.DeleteByEntity(ctx, entityType, ids)
+	if err != nil {
+		log.Printf("Failed to delete attachments of %s: %v", entityType, err)
+		return
+	}
+	if count > 0 {
+		log.Printf("Deleted %d orphaned attachments of %s", count, entityType)
+	}
 }
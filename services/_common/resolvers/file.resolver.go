@@ -0,0 +1,371 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+	pb "thaily/proto/common"
+	"thaily/services/_common/hooks"
+	"thaily/services/_common/similarity"
+	"thaily/services/_common/storage"
+	"thaily/services/_common/tasks"
+	"thaily/services/_common/workflow"
+	"thaily/services/adapter"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	downloadChunkSize  = 256 * 1024
+	defaultDownloadTTL = 15 * time.Minute
+	maxDownloadTTL     = 24 * time.Hour
+)
+
+type FileService struct {
+	pb.UnimplementedFileServiceServer
+	adapter *adapter.MongoDBAdapter
+	store   *storage.FileStore
+	signer  *storage.TokenSigner
+	// Base URL of the HTTP download endpoint, e.g. http://localhost:8081
+	publicURL string
+	tasks     hooks.TaskEnqueuer
+}
+
+func NewFileService(adapter *adapter.MongoDBAdapter, store *storage.FileStore, signer *storage.TokenSigner, publicURL string) *FileService {
+	return &FileService{
+		adapter:   adapter,
+		store:     store,
+		signer:    signer,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// SetTaskQueue re-runs the similarity check of a submission when a file is
+// uploaded to it
+func (s *FileService) SetTaskQueue(tasks hooks.TaskEnqueuer) {
+	s.tasks = tasks
+}
+
+func (s *FileService) Upload(stream pb.FileService_UploadServer) error {
+	ctx := stream.Context()
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	meta := first.GetMetadata()
+	if meta == nil {
+		return stream.SendAndClose(&pb.UploadResponse{
+			Success: false,
+			Message: "first message must contain metadata",
+		})
+	}
+
+	if meta.FileName == "" || meta.MimeType == "" {
+		return stream.SendAndClose(&pb.UploadResponse{
+			Success: false,
+			Message: "file_name and mime_type are required",
+		})
+	}
+
+	if meta.EntityType == "" || meta.EntityId == "" {
+		return stream.SendAndClose(&pb.UploadResponse{
+			Success: false,
+			Message: "entity_type and entity_id are required",
+		})
+	}
+
+	if err := protectedError(meta.EntityType); err != nil {
+		return err
+	}
+
+	// Người sở hữu là người gọi, không tin owner_id do client gửi
+	actor, ok := workflow.ActorFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "access token is required")
+	}
+
+	entityID, err := primitive.ObjectIDFromHex(meta.EntityId)
+	if err != nil {
+		return stream.SendAndClose(&pb.UploadResponse{
+			Success: false,
+			Message: fmt.Sprintf("invalid entity_id format: %v", err),
+		})
+	}
+
+	owner, err := s.adapter.FindOne(ctx, meta.EntityType, bson.M{"_id": entityID}, bson.M{"_id": 1})
+	if err != nil || !owner.Success {
+		return stream.SendAndClose(&pb.UploadResponse{
+			Success: false,
+			Message: fmt.Sprintf("%s %s not found", meta.EntityType, meta.EntityId),
+		})
+	}
+
+	info, err := s.store.Upload(ctx, storage.FileInfo{
+		FileName:   meta.FileName,
+		MimeType:   meta.MimeType,
+		OwnerID:    actor.UserID,
+		EntityType: meta.EntityType,
+		EntityID:   meta.EntityId,
+	}, &uploadReader{stream: stream}, meta.Checksum)
+	if err != nil {
+		return stream.SendAndClose(&pb.UploadResponse{
+			Success: false,
+			Message: fmt.Sprintf("upload failed: %v", err),
+		})
+	}
+	s.queueSimilarityCheck(ctx, info)
+
+	return stream.SendAndClose(&pb.UploadResponse{
+		Success: true,
+		Message: "File uploaded successfully",
+		File:    fileInfoToProto(info),
+	})
+}
+
+func (s *FileService) queueSimilarityCheck(ctx context.Context, info *storage.FileInfo) {
+	if s.tasks == nil || info.EntityType != similarity.SourceSubmissions || !similarity.Supported(info.MimeType, info.FileName) {
+		return
+	}
+	var submission struct {
+		ThesisID interface{} `bson:"thesis_id"`
+	}
+	id, _ := primitive.ObjectIDFromHex(info.EntityID)
+	if err := s.adapter.GetDatabase().Collection(info.EntityType).FindOne(ctx, bson.M{"_id": id}).Decode(&submission); err != nil {
+		log.Printf("Failed to queue similarity check of submission %s: %v", info.EntityID, err)
+		return
+	}
+	thesisID := fmt.Sprint(submission.ThesisID)
+	if oid, ok := submission.ThesisID.(primitive.ObjectID); ok {
+		thesisID = oid.Hex()
+	}
+	if err := similarity.MarkPending(ctx, s.adapter.GetDatabase(), info.EntityID, thesisID); err != nil {
+		log.Printf("Failed to queue similarity check of submission %s: %v", info.EntityID, err)
+		return
+	}
+	if err := s.tasks.Enqueue(ctx, tasks.TypeSimilarityCheck, tasks.SimilarityPayload{EntityType: info.EntityType, ID: info.EntityID}); err != nil {
+		log.Printf("Failed to queue similarity check of submission %s: %v", info.EntityID, err)
+	}
+}
+
+func (s *FileService) Download(req *pb.DownloadRequest, stream pb.FileService_DownloadServer) error {
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid ID format: %v", err)
+	}
+
+	info, reader, err := s.store.Open(stream.Context(), id)
+	if errors.Is(err, storage.ErrNotFound) {
+		return status.Error(codes.NotFound, "file not found")
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	defer reader.Close()
+	if err := fileAccess(stream.Context(), info); err != nil {
+		return err
+	}
+
+	if err := stream.Send(&pb.DownloadResponse{Data: &pb.DownloadResponse_Info{Info: fileInfoToProto(info)}}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, downloadChunkSize)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			chunk := &pb.DownloadResponse{Data: &pb.DownloadResponse_Chunk{Chunk: buf[:n]}}
+			if err := stream.Send(chunk); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to read file: %v", err)
+		}
+	}
+}
+
+func (s *FileService) GetFile(ctx context.Context, req *pb.GetFileRequest) (*pb.FileResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return &pb.FileResponse{
+			Success: false,
+			Message: fmt.Sprintf("invalid ID format: %v", err),
+		}, nil
+	}
+
+	info, err := s.store.Get(ctx, id)
+	if err != nil {
+		return &pb.FileResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &pb.FileResponse{
+		Success: true,
+		Message: "File retrieved successfully",
+		File:    fileInfoToProto(info),
+	}, nil
+}
+
+func (s *FileService) ListFiles(ctx context.Context, req *pb.ListFilesRequest) (*pb.ListFilesResponse, error) {
+	if req.EntityType == "" || req.EntityId == "" {
+		return &pb.ListFilesResponse{
+			Success: false,
+			Message: "entity_type and entity_id are required",
+		}, nil
+	}
+
+	files, err := s.store.List(ctx, req.EntityType, req.EntityId)
+	if err != nil {
+		return &pb.ListFilesResponse{
+			Success: false,
+			Message: fmt.Sprintf("failed to list files: %v", err),
+		}, nil
+	}
+
+	result := make([]*pb.FileInfo, len(files))
+	for i := range files {
+		result[i] = fileInfoToProto(&files[i])
+	}
+
+	return &pb.ListFilesResponse{
+		Success: true,
+		Message: "Files retrieved successfully",
+		Files:   result,
+	}, nil
+}
+
+func (s *FileService) DeleteFile(ctx context.Context, req *pb.GetFileRequest) (*pb.DeleteResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return &pb.DeleteResponse{
+			Success: false,
+			Message: fmt.Sprintf("invalid ID format: %v", err),
+		}, nil
+	}
+
+	info, err := s.store.Get(ctx, id)
+	if err != nil {
+		return &pb.DeleteResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+	if err := fileAccess(ctx, info); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Delete(ctx, id); err != nil {
+		return &pb.DeleteResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	return &pb.DeleteResponse{
+		Success:      true,
+		Message:      "File deleted successfully",
+		DeletedCount: 1,
+	}, nil
+}
+
+func (s *FileService) GetDownloadURL(ctx context.Context, req *pb.DownloadURLRequest) (*pb.DownloadURLResponse, error) {
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return &pb.DownloadURLResponse{
+			Success: false,
+			Message: fmt.Sprintf("invalid ID format: %v", err),
+		}, nil
+	}
+
+	info, err := s.store.Get(ctx, id)
+	if err != nil {
+		return &pb.DownloadURLResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+	if err := fileAccess(ctx, info); err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+	if ttl <= 0 {
+		ttl = defaultDownloadTTL
+	}
+	if ttl > maxDownloadTTL {
+		ttl = maxDownloadTTL
+	}
+
+	token, expiresAt := s.signer.Sign(req.Id, ttl)
+	return &pb.DownloadURLResponse{
+		Success:   true,
+		Message:   "Download URL created successfully",
+		Url:       s.publicURL + storage.DownloadPath + req.Id + "?token=" + url.QueryEscape(token),
+		ExpiresAt: timestamppb.New(expiresAt),
+	}, nil
+}
+
+// fileAccess lets the owner of a file and staff read or delete it
+func fileAccess(ctx context.Context, info *storage.FileInfo) error {
+	actor, ok := workflow.ActorFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "access token is required")
+	}
+	if actor.IsStaff() || (info.OwnerID != "" && info.OwnerID == actor.UserID) {
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, "only the owner or staff can access this file")
+}
+
+// uploadReader exposes the chunks of a client stream as an io.Reader
+type uploadReader struct {
+	stream pb.FileService_UploadServer
+	buf    []byte
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if req.GetMetadata() != nil {
+			return 0, fmt.Errorf("metadata can only be sent once")
+		}
+		r.buf = req.GetChunk()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func fileInfoToProto(info *storage.FileInfo) *pb.FileInfo {
+	return &pb.FileInfo{
+		Id:         info.ID.Hex(),
+		FileName:   info.FileName,
+		MimeType:   info.MimeType,
+		Size:       info.Size,
+		Checksum:   info.Checksum,
+		OwnerId:    info.OwnerID,
+		EntityType: info.EntityType,
+		EntityId:   info.EntityID,
+		CreatedAt:  timestamppb.New(info.CreatedAt),
+		Storage:    info.Storage,
+		Integrity:  info.Integrity,
+	}
+}
@@ -0,0 +1,274 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	pb "thaily/proto/common"
+	"thaily/services/_common/audit"
+	"thaily/services/_common/grading"
+	"thaily/services/_common/hooks"
+	"thaily/services/_common/workflow"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// action của event_logs khi tính và công bố điểm
+const (
+	computeGradeAction = "compute_final_grade"
+	publishGradeAction = "publish_final_grade"
+)
+
+func (s *CommonService) ComputeFinalGrade(ctx context.Context, req *pb.ComputeFinalGradeRequest) (*pb.ComputeFinalGradeResponse, error) {
+	thesisID, err := primitive.ObjectIDFromHex(req.ThesisId)
+	if err != nil {
+		return &pb.ComputeFinalGradeResponse{
+			Success: false,
+			Message: fmt.Sprintf("invalid thesis_id: %v", err),
+		}, nil
+	}
+
+	store := grading.NewStore(s.adapter.GetDatabase())
+	thesis, err := store.Thesis(ctx, thesisID)
+	if err != nil {
+		return &pb.ComputeFinalGradeResponse{Success: false, Message: err.Error()}, nil
+	}
+	if thesis == nil {
+		return &pb.ComputeFinalGradeResponse{
+			Success: false,
+			Message: fmt.Sprintf("thesis %s not found", req.ThesisId),
+		}, nil
+	}
+
+	grade, err := store.Grade(ctx, thesisID)
+	if err != nil {
+		return &pb.ComputeFinalGradeResponse{Success: false, Message: err.Error()}, nil
+	}
+	if grade != nil && grade["status"] == grading.StatusPublished {
+		final, _ := grade["final_grade"].(float64)
+		gradeID, _ := grade["_id"].(primitive.ObjectID)
+		return &pb.ComputeFinalGradeResponse{
+			Success:    false,
+			Message:    "the final grade is published and locked",
+			FinalGrade: final,
+			Status:     grading.StatusPublished,
+			GradeId:    gradeID.Hex(),
+			Errors: []*pb.ErrorDetail{{
+				Code:    "GRADE_LOCKED",
+				Field:   "thesis_id",
+				Value:   req.ThesisId,
+				Message: "reopen the grade before computing it again",
+			}},
+		}, nil
+	}
+
+	rubric, err := store.Rubric(ctx, thesis.Major)
+	if err != nil {
+		return &pb.ComputeFinalGradeResponse{Success: false, Message: err.Error()}, nil
+	}
+	inputs, err := store.Inputs(ctx, thesis)
+	if err != nil {
+		return &pb.ComputeFinalGradeResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	result, inputErrs := grading.Compute(rubric, inputs)
+	if len(inputErrs) > 0 {
+		details := make([]*pb.ErrorDetail, len(inputErrs))
+		for i, e := range inputErrs {
+			details[i] = &pb.ErrorDetail{Code: e.Code, Field: "scores", Value: e.ScoreID, Message: e.Message}
+		}
+		return &pb.ComputeFinalGradeResponse{
+			Success: false,
+			Message: fmt.Sprintf("cannot compute the final grade: %s", inputErrs[0].Message),
+			Rubric:  rubric.Name,
+			Errors:  details,
+		}, nil
+	}
+
+	resp := gradeResponse(result)
+	resp.Preview = req.Preview
+	resp.Rubric = rubric.Name
+	resp.Status = grading.StatusDraft
+	if req.Publish {
+		resp.Status = grading.StatusPublished
+	}
+	if req.Preview {
+		resp.Success = true
+		resp.Message = fmt.Sprintf("final grade %v", result.Final)
+		return resp, nil
+	}
+
+	// Người tính điểm lấy từ token; chỉ giáo vụ được công bố điểm
+	actor, ok := workflow.ActorFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "access token is required")
+	}
+	if req.Publish && !actor.IsStaff() {
+		return nil, status.Error(codes.PermissionDenied, "only staff can publish a final grade")
+	}
+	var computedBy interface{}
+	if id, err := primitive.ObjectIDFromHex(actor.UserID); err == nil {
+		computedBy = id
+	}
+
+	var op *hooks.Operation
+	var saved *structpb.Struct
+	err = s.withHooks(ctx, grading.GradesCollection, func(ctx context.Context) error {
+		op, saved = nil, nil
+		now := time.Now()
+
+		// Lưu nguyên rubric và điểm đầu vào để phúc khảo tính lại được
+		computation, err := s.adapter.Create(ctx, grading.ComputationsCollection, bson.M{
+			"thesis_id":   thesisID,
+			"rubric":      grading.Plain(rubric),
+			"inputs":      grading.Plain(inputs),
+			"final_grade": result.Final,
+			"raw_grade":   result.Raw,
+			"sources":     grading.Plain(result.Sources),
+			"scorers":     grading.Plain(result.Scorers),
+			"warnings":    grading.Plain(result.Warnings),
+			"published":   req.Publish,
+			"computed_by": computedBy,
+			"computed_at": now,
+			"createdAt":   now,
+			"updatedAt":   now,
+		})
+		if err != nil {
+			return err
+		}
+		if !computation.Success {
+			return &hooks.Rejection{Message: computation.Message, Details: computation.Errors}
+		}
+		resp.ComputationId = computation.Id.GetValue()
+		computationID, _ := primitive.ObjectIDFromHex(resp.ComputationId)
+
+		data := map[string]interface{}{
+			"thesis_id":      thesisID,
+			"final_grade":    result.Final,
+			"raw_grade":      result.Raw,
+			"status":         resp.Status,
+			"rubric_id":      rubric.ID,
+			"rubric_name":    rubric.Name,
+			"computation_id": computationID,
+			"sources":        grading.Plain(result.Sources),
+			"scorers":        grading.Plain(result.Scorers),
+			"warnings":       grading.Plain(result.Warnings),
+			"computed_by":    computedBy,
+			"computed_at":    now,
+			"updatedAt":      now,
+		}
+		if rubric.ID.IsZero() {
+			data["rubric_id"] = nil
+		}
+		if req.Publish {
+			data["published_by"] = computedBy
+			data["published_at"] = now
+		}
+
+		if grade != nil {
+			gradeID, _ := grade["_id"].(primitive.ObjectID)
+			op = s.newOperation(ctx, grading.GradesCollection, hooks.BeforeUpdate, req.Meta)
+			op.ID = gradeID.Hex()
+			op.Data = data
+			updated, err := s.applyUpdate(ctx, op, gradeID)
+			if errors.Is(err, errWriteFailed) {
+				return &hooks.Rejection{Message: updated.Message, Details: updated.Errors}
+			}
+			if err != nil {
+				return err
+			}
+			resp.GradeId = op.ID
+			saved = updated.Entity
+		} else {
+			data["createdAt"] = now
+			created, err := s.adapter.Create(ctx, grading.GradesCollection, data)
+			if err != nil {
+				return err
+			}
+			if !created.Success {
+				return &hooks.Rejection{Message: created.Message, Details: created.Errors}
+			}
+			resp.GradeId = created.Id.GetValue()
+			saved = created.Entity
+		}
+
+		action := computeGradeAction
+		if req.Publish {
+			action = publishGradeAction
+		}
+		return audit.Log(ctx, s.adapter.GetDatabase(), audit.Entry{
+			UserID:     actor.UserID,
+			Action:     action,
+			EntityType: grading.GradesCollection,
+			EntityID:   resp.GradeId,
+			Details: bson.M{
+				"thesis_id":      req.ThesisId,
+				"computation_id": resp.ComputationId,
+				"final_grade":    result.Final,
+				"rubric":         rubric.Name,
+				"outliers":       countOutliers(result),
+			},
+		})
+	})
+	if err != nil {
+		rejection := hooks.AsRejection(err)
+		resp.Message = fmt.Sprintf("failed to save final grade: %s", rejection.Message)
+		resp.Errors = rejection.Details
+		resp.GradeId, resp.ComputationId = "", ""
+		return resp, nil
+	}
+
+	if op != nil {
+		op.Commit(ctx)
+	}
+	s.indexSearch(grading.GradesCollection, saved)
+
+	resp.Success = true
+	resp.Message = fmt.Sprintf("final grade %v saved as %s", result.Final, resp.Status)
+	return resp, nil
+}
+
+func gradeResponse(result grading.Result) *pb.ComputeFinalGradeResponse {
+	resp := &pb.ComputeFinalGradeResponse{
+		FinalGrade: result.Final,
+		RawGrade:   result.Raw,
+		Sources:    make([]*pb.SourceGrade, len(result.Sources)),
+		Scorers:    make([]*pb.ScorerGrade, len(result.Scorers)),
+		Warnings:   result.Warnings,
+	}
+	for i, source := range result.Sources {
+		resp.Sources[i] = &pb.SourceGrade{
+			Source:  source.Source,
+			Weight:  source.Weight,
+			Average: source.Average,
+			Scorers: int32(source.Scorers),
+		}
+	}
+	for i, scorer := range result.Scorers {
+		resp.Scorers[i] = &pb.ScorerGrade{
+			ScorerId:  scorer.ScorerID,
+			Source:    scorer.Source,
+			Total:     scorer.Total,
+			Deviation: scorer.Deviation,
+			Outlier:   scorer.Outlier,
+			Excluded:  scorer.Excluded,
+			Criteria:  scorer.Criteria,
+		}
+	}
+	return resp
+}
+
+func countOutliers(result grading.Result) int {
+	count := 0
+	for _, scorer := range result.Scorers {
+		if scorer.Outlier {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,193 @@
+package resolvers
+
+import (
+	"fmt"
+	"strings"
+	"thaily/services/_common/schema"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// hiddenStages unsets the schema.HiddenFields of entityType, empty when it
+// has none
+func hiddenStages(entityType string) bson.A {
+	fields := schema.Hidden(entityType)
+	if len(fields) == 0 {
+		return nil
+	}
+	paths := bson.A{}
+	for _, field := range fields {
+		paths = append(paths, field)
+	}
+	return bson.A{bson.M{"$unset": paths}}
+}
+
+// hiddenProjection keeps the hidden fields of entityType out of a find
+// projection: they are dropped from an inclusion and excluded otherwise
+func hiddenProjection(entityType string, projection bson.M) bson.M {
+	fields := schema.Hidden(entityType)
+	if len(fields) == 0 {
+		return projection
+	}
+	inclusion := false
+	for key, value := range projection {
+		if isHiddenPath(fields, key) {
+			delete(projection, key)
+			continue
+		}
+		if key != "_id" && value != 0 && value != false {
+			inclusion = true
+		}
+	}
+	if !inclusion {
+		for _, field := range fields {
+			projection[field] = 0
+		}
+	}
+	return projection
+}
+
+func isHiddenPath(fields []string, path string) bool {
+	for _, field := range fields {
+		if path == field || strings.HasPrefix(path, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// hideEntity removes the hidden fields of entityType from a returned document
+func hideEntity(entityType string, entities ...*structpb.Struct) {
+	for _, entity := range entities {
+		if entity == nil {
+			continue
+		}
+		for _, field := range schema.Hidden(entityType) {
+			delete(entity.Fields, field)
+		}
+	}
+}
+
+// hidePipeline removes the hidden fields from every document entering a
+// client pipeline: those of entityType, before the first stage, and those
+// read by $lookup and $unionWith
+func hidePipeline(entityType string, pipeline bson.A) (bson.A, error) {
+	result := bson.A{}
+	unset := hiddenStages(entityType)
+	// $text chỉ hợp lệ ở stage đầu tiên nên được tách ra trước $unset
+	if len(unset) > 0 && len(pipeline) > 0 {
+		if text, rest, ok := splitTextMatch(pipeline[0]); ok {
+			result = append(result, text)
+			pipeline = append(bson.A{rest}, pipeline[1:]...)
+		}
+	}
+	result = append(result, unset...)
+
+	for _, stage := range pipeline {
+		stages, err := hideStage(stage)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, stages...)
+	}
+	return result, nil
+}
+
+// splitTextMatch splits a $match with $text into the $text condition and the
+// rest of the filter
+func splitTextMatch(stage interface{}) (text, rest bson.M, ok bool) {
+	doc, _ := stage.(bson.M)
+	match, _ := doc["$match"].(bson.M)
+	if len(doc) != 1 || match["$text"] == nil {
+		return nil, nil, false
+	}
+	remaining := bson.M{}
+	for key, value := range match {
+		if key != "$text" {
+			remaining[key] = value
+		}
+	}
+	return bson.M{"$match": bson.M{"$text": match["$text"]}}, bson.M{"$match": remaining}, true
+}
+
+// hideStage rewrites a stage reading another collection, returning it with
+// the stages to run right after it
+func hideStage(stage interface{}) (bson.A, error) {
+	doc, ok := stage.(bson.M)
+	if !ok {
+		return bson.A{stage}, nil
+	}
+	result := bson.A{doc}
+
+	for name, spec := range doc {
+		switch name {
+		case "$lookup":
+			lookup, _ := spec.(bson.M)
+			from, _ := lookup["from"].(string)
+			if sub, ok := asList(lookup["pipeline"]); ok {
+				hidden, err := hidePipeline(from, sub)
+				if err != nil {
+					return nil, err
+				}
+				lookup["pipeline"] = hidden
+				continue
+			}
+			// Dạng localField/foreignField không có pipeline con
+			if as, _ := lookup["as"].(string); as != "" {
+				for _, field := range schema.Hidden(from) {
+					result = append(result, bson.M{"$unset": as + "." + field})
+				}
+			}
+
+		case "$unionWith":
+			union, _ := spec.(bson.M)
+			if coll, ok := spec.(string); ok {
+				union = bson.M{"coll": coll}
+				doc[name] = union
+			}
+			coll, _ := union["coll"].(string)
+			sub, _ := asList(union["pipeline"])
+			hidden, err := hidePipeline(coll, sub)
+			if err != nil {
+				return nil, err
+			}
+			if len(hidden) > 0 {
+				union["pipeline"] = hidden
+			}
+
+		case "$graphLookup":
+			graph, _ := spec.(bson.M)
+			if from, _ := graph["from"].(string); len(schema.Hidden(from)) > 0 {
+				return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("$graphLookup cannot read %s", from))
+			}
+
+		case "$facet":
+			facet, _ := spec.(bson.M)
+			for key, value := range facet {
+				sub, ok := asList(value)
+				if !ok {
+					continue
+				}
+				hidden, err := hidePipeline("", sub)
+				if err != nil {
+					return nil, err
+				}
+				facet[key] = hidden
+			}
+		}
+	}
+	return result, nil
+}
+
+func asList(value interface{}) (bson.A, bool) {
+	switch v := value.(type) {
+	case bson.A:
+		return v, true
+	case []interface{}:
+		return bson.A(v), true
+	}
+	return nil, false
+}
@@ -0,0 +1,110 @@
+package resolvers
+
+import (
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestHiddenProjection(t *testing.T) {
+	tests := []struct {
+		name       string
+		entityType string
+		projection bson.M
+		want       bson.M
+	}{
+		{"no projection", "users", bson.M{}, bson.M{"password": 0}},
+		{"inclusion", "users", bson.M{"email": 1, "password": 1}, bson.M{"email": 1}},
+		{"only the hidden field", "users", bson.M{"password": 1}, bson.M{"password": 0}},
+		{"nested hidden path", "users", bson.M{"password.hash": 1, "_id": 1}, bson.M{"_id": 1, "password": 0}},
+		{"other collection", "theses", bson.M{}, bson.M{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hiddenProjection(tt.entityType, tt.projection)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("hiddenProjection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHidePipeline(t *testing.T) {
+	tests := []struct {
+		name       string
+		entityType string
+		pipeline   bson.A
+		want       bson.A
+		wantErr    bool
+	}{
+		{
+			name:       "users are unset first",
+			entityType: "users",
+			pipeline:   bson.A{bson.M{"$project": bson.M{"hash": "$password"}}},
+			want: bson.A{
+				bson.M{"$unset": bson.A{"password"}},
+				bson.M{"$project": bson.M{"hash": "$password"}},
+			},
+		},
+		{
+			name:       "$text stays the first stage",
+			entityType: "users",
+			pipeline:   bson.A{bson.M{"$match": bson.M{"$text": bson.M{"$search": "an"}, "password": bson.M{"$regex": "^\\$argon"}}}},
+			want: bson.A{
+				bson.M{"$match": bson.M{"$text": bson.M{"$search": "an"}}},
+				bson.M{"$unset": bson.A{"password"}},
+				bson.M{"$match": bson.M{"password": bson.M{"$regex": "^\\$argon"}}},
+			},
+		},
+		{
+			name:       "lookup of users",
+			entityType: "theses",
+			pipeline:   bson.A{bson.M{"$lookup": bson.M{"from": "users", "localField": "student_id", "foreignField": "_id", "as": "student"}}},
+			want: bson.A{
+				bson.M{"$lookup": bson.M{"from": "users", "localField": "student_id", "foreignField": "_id", "as": "student"}},
+				bson.M{"$unset": "student.password"},
+			},
+		},
+		{
+			name:       "lookup pipeline of users",
+			entityType: "theses",
+			pipeline:   bson.A{bson.M{"$lookup": bson.M{"from": "users", "pipeline": []interface{}{bson.M{"$replaceRoot": bson.M{"newRoot": "$$ROOT"}}}, "as": "u"}}},
+			want: bson.A{
+				bson.M{"$lookup": bson.M{"from": "users", "pipeline": bson.A{
+					bson.M{"$unset": bson.A{"password"}},
+					bson.M{"$replaceRoot": bson.M{"newRoot": "$$ROOT"}},
+				}, "as": "u"}},
+			},
+		},
+		{
+			name:       "unionWith users inside a facet",
+			entityType: "theses",
+			pipeline:   bson.A{bson.M{"$facet": bson.M{"all": []interface{}{bson.M{"$unionWith": "users"}}}}},
+			want: bson.A{
+				bson.M{"$facet": bson.M{"all": bson.A{
+					bson.M{"$unionWith": bson.M{"coll": "users", "pipeline": bson.A{bson.M{"$unset": bson.A{"password"}}}}},
+				}}},
+			},
+		},
+		{
+			name:       "graphLookup of users",
+			entityType: "theses",
+			pipeline:   bson.A{bson.M{"$graphLookup": bson.M{"from": "users"}}},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hidePipeline(tt.entityType, tt.pipeline)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("hidePipeline() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("hidePipeline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
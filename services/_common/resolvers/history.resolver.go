@@ -0,0 +1,392 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	pb "thaily/proto/common"
+	"thaily/services/_common/helper"
+	"thaily/services/_common/history"
+	"thaily/services/_common/hooks"
+	"thaily/services/_common/schema"
+	"thaily/services/_common/workflow"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const maxVersionPageSize = 100
+
+// historyTarget validates the entity of a history request
+func historyTarget(entityType, entityID string) (primitive.ObjectID, string) {
+	if entityType == "" {
+		return primitive.NilObjectID, "entity_type is required"
+	}
+	if !schema.HistoryEnabled(entityType) {
+		return primitive.NilObjectID, fmt.Sprintf("history is not enabled for %s", entityType)
+	}
+	if entityID == "" {
+		return primitive.NilObjectID, "entity_id is required"
+	}
+	id, err := primitive.ObjectIDFromHex(entityID)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Sprintf("invalid ID format: %v", err)
+	}
+	return id, ""
+}
+
+// historyAccess applies the access rules of the live entity to its history.
+// Any signed in user may read what GetById returns; a deleted entity is no
+// longer readable live, so its history is left to staff.
+func (s *CommonService) historyAccess(ctx context.Context, entityType string, id primitive.ObjectID) error {
+	actor, ok := workflow.ActorFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "access token is required")
+	}
+	if actor.IsStaff() {
+		return nil
+	}
+	current, err := s.currentDocument(ctx, entityType, id)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if current == nil {
+		return status.Error(codes.PermissionDenied, "only staff can access the history of a deleted entity")
+	}
+	return nil
+}
+
+func (s *CommonService) ListVersions(ctx context.Context, req *pb.ListVersionsRequest) (*pb.ListVersionsResponse, error) {
+	id, message := historyTarget(req.EntityType, req.EntityId)
+	if message != "" {
+		return &pb.ListVersionsResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+	if err := s.historyAccess(ctx, req.EntityType, id); err != nil {
+		return nil, err
+	}
+
+	page := int64(req.Page)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := int64(req.PageSize)
+	if pageSize < 1 {
+		pageSize = 10
+	} else if pageSize > maxVersionPageSize {
+		pageSize = maxVersionPageSize
+	}
+
+	versions, total, err := s.history.List(ctx, req.EntityType, id, page, pageSize)
+	if err != nil {
+		return &pb.ListVersionsResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	result := make([]*pb.VersionInfo, len(versions))
+	for i, version := range versions {
+		if result[i], err = versionToProto(version); err != nil {
+			return &pb.ListVersionsResponse{
+				Success: false,
+				Message: fmt.Sprintf("failed to convert version: %v", err),
+			}, nil
+		}
+	}
+
+	return &pb.ListVersionsResponse{
+		Success:  true,
+		Message:  "Versions retrieved successfully",
+		Versions: result,
+		Total:    total,
+	}, nil
+}
+
+func (s *CommonService) GetVersion(ctx context.Context, req *pb.GetVersionRequest) (*pb.VersionResponse, error) {
+	id, message := historyTarget(req.EntityType, req.EntityId)
+	if message != "" {
+		return &pb.VersionResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+	if err := s.historyAccess(ctx, req.EntityType, id); err != nil {
+		return nil, err
+	}
+
+	version, err := s.history.Get(ctx, req.EntityType, id, req.Version)
+	if err != nil {
+		return &pb.VersionResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	info, err := versionToProto(version)
+	if err != nil {
+		return &pb.VersionResponse{
+			Success: false,
+			Message: fmt.Sprintf("failed to convert version: %v", err),
+		}, nil
+	}
+	entity, err := helper.DocToStruct(projectFields(version.Data, req.Fields))
+	if err != nil {
+		return &pb.VersionResponse{
+			Success: false,
+			Message: fmt.Sprintf("failed to convert entity: %v", err),
+		}, nil
+	}
+
+	return &pb.VersionResponse{
+		Success: true,
+		Message: "Version retrieved successfully",
+		Version: info,
+		Entity:  entity,
+	}, nil
+}
+
+func (s *CommonService) DiffVersions(ctx context.Context, req *pb.DiffVersionsRequest) (*pb.DiffVersionsResponse, error) {
+	id, message := historyTarget(req.EntityType, req.EntityId)
+	if message != "" {
+		return &pb.DiffVersionsResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+	if err := s.historyAccess(ctx, req.EntityType, id); err != nil {
+		return nil, err
+	}
+
+	from, err := s.versionData(ctx, req.EntityType, id, req.FromVersion)
+	if err != nil {
+		return &pb.DiffVersionsResponse{
+			Success: false,
+			Message: fmt.Sprintf("from_version: %v", err),
+		}, nil
+	}
+	to, err := s.versionData(ctx, req.EntityType, id, req.ToVersion)
+	if err != nil {
+		return &pb.DiffVersionsResponse{
+			Success: false,
+			Message: fmt.Sprintf("to_version: %v", err),
+		}, nil
+	}
+
+	changes, err := history.Diff(from, to)
+	if err != nil {
+		return &pb.DiffVersionsResponse{
+			Success: false,
+			Message: fmt.Sprintf("failed to compare versions: %v", err),
+		}, nil
+	}
+
+	result := make([]*pb.FieldChange, len(changes))
+	for i, change := range changes {
+		result[i] = &pb.FieldChange{
+			Path: change.Path,
+			Kind: change.Kind,
+		}
+		if change.Kind != history.ChangeAdded {
+			result[i].From, _ = structpb.NewValue(change.From)
+		}
+		if change.Kind != history.ChangeRemoved {
+			result[i].To, _ = structpb.NewValue(change.To)
+		}
+	}
+
+	return &pb.DiffVersionsResponse{
+		Success: true,
+		Message: fmt.Sprintf("%d fields changed", len(result)),
+		Changes: result,
+	}, nil
+}
+
+// RevertToVersion writes the stored version back through the same hooks and
+// checks as Update, so the state being replaced becomes a version itself. A
+// deleted entity is recreated with its original id.
+func (s *CommonService) RevertToVersion(ctx context.Context, req *pb.RevertToVersionRequest) (*pb.GenericResponse, error) {
+	id, message := historyTarget(req.EntityType, req.EntityId)
+	if message != "" {
+		return &pb.GenericResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+	if err := s.historyAccess(ctx, req.EntityType, id); err != nil {
+		return nil, err
+	}
+
+	version, err := s.history.Get(ctx, req.EntityType, id, req.Version)
+	if err != nil {
+		return &pb.GenericResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	doc := version.Data
+	doc["_id"] = id
+	doc["updatedAt"] = time.Now()
+
+	var op *hooks.Operation
+	var resp *pb.GenericResponse
+	err = s.withHooks(ctx, req.EntityType, func(ctx context.Context) error {
+		current, err := s.currentDocument(ctx, req.EntityType, id)
+		if err != nil {
+			return err
+		}
+
+		if current == nil {
+			op = s.newOperation(ctx, req.EntityType, hooks.BeforeCreate, req.Meta)
+		} else {
+			op = s.newOperation(ctx, req.EntityType, hooks.BeforeUpdate, req.Meta)
+			op.ID = req.EntityId
+			history.MarkRevert(op, version.Version)
+		}
+		op.Data = doc
+		if err := hooks.Run(ctx, op); err != nil {
+			return err
+		}
+
+		if message, details := s.checkReferences(ctx, req.EntityType, doc); message != "" {
+			return &hooks.Rejection{Message: message, Details: details}
+		}
+
+		if current == nil {
+			resp, err = s.adapter.Create(ctx, req.EntityType, doc)
+		} else {
+			// Các field không có trong phiên bản cũ bị xóa khỏi bản hiện tại
+			update := bson.M{"$set": withoutID(doc)}
+			unset := bson.M{}
+			for field := range current {
+				if _, ok := doc[field]; !ok {
+					unset[field] = ""
+				}
+			}
+			if len(unset) > 0 {
+				update["$unset"] = unset
+			}
+			resp, err = s.adapter.Update(ctx, req.EntityType, id, update)
+		}
+		if err != nil {
+			return err
+		}
+		if !resp.Success {
+			return errWriteFailed
+		}
+
+		event := hooks.AfterUpdate
+		if current == nil {
+			event = hooks.AfterCreate
+		}
+		after := op.Next(event)
+		after.ID = req.EntityId
+		after.Entity = resp.Entity.AsMap()
+		return hooks.Run(ctx, after)
+	})
+	if errors.Is(err, errWriteFailed) {
+		return resp, nil
+	}
+	if err != nil {
+		rejection := hooks.AsRejection(err)
+		return &pb.GenericResponse{
+			Success: false,
+			Message: rejection.Message,
+			Errors:  rejection.Details,
+		}, nil
+	}
+
+	op.Commit(ctx)
+	s.indexSearch(req.EntityType, resp.Entity)
+	resp.Message = fmt.Sprintf("Entity reverted to version %d", version.Version)
+	return resp, nil
+}
+
+// versionData returns a stored version, or the live document for version 0
+func (s *CommonService) versionData(ctx context.Context, entityType string, id primitive.ObjectID, version int64) (bson.M, error) {
+	if version < 0 {
+		return nil, fmt.Errorf("version must not be negative")
+	}
+	if version == 0 {
+		current, err := s.currentDocument(ctx, entityType, id)
+		if err != nil {
+			return nil, err
+		}
+		if current == nil {
+			return nil, fmt.Errorf("entity has been deleted")
+		}
+		return current, nil
+	}
+
+	stored, err := s.history.Get(ctx, entityType, id, version)
+	if err != nil {
+		return nil, err
+	}
+	return stored.Data, nil
+}
+
+// currentDocument returns the live document, nil if it does not exist
+func (s *CommonService) currentDocument(ctx context.Context, entityType string, id primitive.ObjectID) (bson.M, error) {
+	var current bson.M
+	err := s.adapter.GetDatabase().Collection(entityType).FindOne(ctx, bson.M{"_id": id}).Decode(&current)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entity: %w", err)
+	}
+	return current, nil
+}
+
+func versionToProto(version *history.Version) (*pb.VersionInfo, error) {
+	info := &pb.VersionInfo{
+		Version:    version.Version,
+		Operation:  version.Operation,
+		RevertedTo: version.RevertedTo,
+		CreatedAt:  timestamppb.New(version.CreatedAt),
+	}
+	if len(version.Meta) > 0 {
+		meta, err := helper.DocToStruct(version.Meta)
+		if err != nil {
+			return nil, err
+		}
+		info.Meta = meta
+	}
+	return info, nil
+}
+
+// projectFields keeps _id and the top-level fields named by fields, like the
+// projection of GetById; a dotted path keeps its whole top-level field
+func projectFields(doc bson.M, fields []string) bson.M {
+	if len(fields) == 0 {
+		return doc
+	}
+	result := bson.M{"_id": doc["_id"]}
+	for _, field := range fields {
+		top := strings.SplitN(field, ".", 2)[0]
+		if value, ok := doc[top]; ok {
+			result[top] = value
+		}
+	}
+	return result
+}
+
+func withoutID(doc bson.M) bson.M {
+	result := make(bson.M, len(doc))
+	for k, v := range doc {
+		if k != "_id" {
+			result[k] = v
+		}
+	}
+	return result
+}
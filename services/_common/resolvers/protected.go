@@ -0,0 +1,86 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"thaily/services/_common/schema"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Các key trong pipeline nhận tên collection: $lookup, $graphLookup và
+// $unionWith
+var collectionKeys = map[string]bool{
+	"from":       true,
+	"coll":       true,
+	"$unionWith": true,
+}
+
+// Các stage ghi kết quả vào collection. Ghi qua pipeline bỏ qua mọi hook
+// (trạng thái luận văn, khóa điểm, hash mật khẩu, vai trò) nên bị cấm
+var writeStages = map[string]bool{
+	"$out":   true,
+	"$merge": true,
+}
+
+// ProtectCollections refuses requests naming a collection of
+// schema.ProtectedCollections, as entity_type or inside a pipeline, and
+// pipelines writing with $out or $merge
+func ProtectCollections(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := checkProtected(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func checkProtected(req interface{}) error {
+	if r, ok := req.(interface{ GetEntityType() string }); ok {
+		if err := protectedError(r.GetEntityType()); err != nil {
+			return err
+		}
+	}
+	if r, ok := req.(interface{ GetPipeline() []*structpb.Struct }); ok {
+		for _, stage := range r.GetPipeline() {
+			if err := checkPipelineValue(structpb.NewStructValue(stage)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkPipelineValue(value *structpb.Value) error {
+	switch v := value.GetKind().(type) {
+	case *structpb.Value_StructValue:
+		for key, field := range v.StructValue.GetFields() {
+			if writeStages[key] {
+				return status.Error(codes.InvalidArgument, fmt.Sprintf("%s is not allowed, write through Create and Update", key))
+			}
+			if name, ok := field.GetKind().(*structpb.Value_StringValue); ok && collectionKeys[key] {
+				if err := protectedError(name.StringValue); err != nil {
+					return err
+				}
+			}
+			if err := checkPipelineValue(field); err != nil {
+				return err
+			}
+		}
+	case *structpb.Value_ListValue:
+		for _, item := range v.ListValue.GetValues() {
+			if err := checkPipelineValue(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func protectedError(entityType string) error {
+	if !schema.Protected(entityType) {
+		return nil
+	}
+	return status.Error(codes.PermissionDenied, fmt.Sprintf("%s cannot be accessed through this service", entityType))
+}
@@ -0,0 +1,87 @@
+package resolvers
+
+import (
+	"testing"
+
+	pb "thaily/proto/common"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestCheckProtected(t *testing.T) {
+	stage := func(m map[string]interface{}) *structpb.Struct {
+		s, err := structpb.NewStruct(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name     string
+		req      interface{}
+		wantCode codes.Code
+	}{
+		{
+			name:     "plain query",
+			req:      &pb.AggregateRequest{EntityType: "theses", Pipeline: []*structpb.Struct{stage(map[string]interface{}{"$match": map[string]interface{}{"status": "draft"}})}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "protected entity type",
+			req:      &pb.GetByIdRequest{EntityType: "signing_keys", Id: "1"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "roles",
+			req:      &pb.UpdateRequest{EntityType: "roles", Id: "1"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "rewind a sequence counter",
+			req:      &pb.UpdateRequest{EntityType: "_counters", Id: "1"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "fake an applied migration",
+			req:      &pb.GenericRequest{EntityType: "_migrations"},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "lookup of a protected collection",
+			req:      &pb.AggregateRequest{EntityType: "users", Pipeline: []*structpb.Struct{stage(map[string]interface{}{"$lookup": map[string]interface{}{"from": "refresh_tokens", "as": "t"}})}},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "merge into theses",
+			req: &pb.AggregateRequest{EntityType: "theses", Pipeline: []*structpb.Struct{
+				stage(map[string]interface{}{"$set": map[string]interface{}{"status_id": "approved"}}),
+				stage(map[string]interface{}{"$merge": "theses"}),
+			}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "merge document form",
+			req: &pb.AggregateRequest{EntityType: "grades", Pipeline: []*structpb.Struct{
+				stage(map[string]interface{}{"$merge": map[string]interface{}{"into": "grades", "whenMatched": "merge"}}),
+			}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "out",
+			req:      &pb.QueryRequest{EntityType: "users", Pipeline: []*structpb.Struct{stage(map[string]interface{}{"$out": "users_copy"})}},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkProtected(tt.req)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("checkProtected() = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+}
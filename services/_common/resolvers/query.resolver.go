@@ -8,6 +8,9 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func (s *CommonService) GetById(ctx context.Context, req *pb.GetByIdRequest) (*pb.GenericResponse, error) {
@@ -39,7 +42,11 @@ func (s *CommonService) GetById(ctx context.Context, req *pb.GetByIdRequest) (*p
 		}
 	}
 
-	return s.adapter.GetById(ctx, req.EntityType, id, projection)
+	resp, err := s.adapter.GetById(ctx, req.EntityType, id, hiddenProjection(req.EntityType, projection))
+	if resp != nil {
+		hideEntity(req.EntityType, resp.Entity)
+	}
+	return resp, err
 }
 
 func (s *CommonService) Query(ctx context.Context, req *pb.QueryRequest) (*pb.QueryResponse, error) {
@@ -61,31 +68,85 @@ func (s *CommonService) Query(ctx context.Context, req *pb.QueryRequest) (*pb.Qu
 	}
 
 	filter := bson.M{}
+	// $text phải nằm ở stage đầu tiên, trước khi bỏ các field ẩn
+	var textFilter bson.M
 
-	if req.Query != "" && len(req.SearchFields) > 0 {
-		orConditions := bson.A{}
-		for _, field := range req.SearchFields {
-			orConditions = append(orConditions, bson.M{
-				field: bson.M{"$regex": req.Query, "$options": "i"},
-			})
+	var search *searchPlan
+	if req.Query != "" {
+		plan, err := s.planSearch(ctx, req)
+		if status.Code(err) == codes.InvalidArgument {
+			return nil, err
+		}
+		if err != nil {
+			return &pb.QueryResponse{
+				Success: false,
+				Message: err.Error(),
+			}, nil
 		}
-		filter["$or"] = orConditions
+		search = plan
 	}
 
-	for key, value := range req.Filters {
-		filter[key] = helper.StructValueToInterface(value)
+	if search != nil {
+		switch search.mode {
+		case searchModeText:
+			textFilter = bson.M{"$text": bson.M{
+				"$search":             req.Query,
+				"$diacriticSensitive": req.AccentSensitive,
+			}}
+		case searchModeRegex:
+			pattern := helper.AccentInsensitivePattern(req.Query)
+			if req.AccentSensitive {
+				pattern = helper.EscapeRegex(req.Query)
+			}
+			orConditions := bson.A{}
+			for _, field := range search.fields {
+				orConditions = append(orConditions, bson.M{
+					field: bson.M{"$regex": pattern, "$options": "i"},
+				})
+			}
+			filter["$or"] = orConditions
+		}
+	}
+
+	if len(req.Filters) > 0 {
+		filters := bson.M{}
+		for key, value := range req.Filters {
+			filters[key] = helper.StructValueToInterface(value)
+		}
+		// $and giữ điều kiện tìm kiếm khi filters cũng có $or
+		if len(filter) > 0 {
+			filter = bson.M{"$and": bson.A{filter, filters}}
+		} else {
+			filter = filters
+		}
 	}
 
 	pipeline := bson.A{}
 	pipelineCount := bson.A{}
+	if textFilter != nil {
+		pipeline = append(pipeline, bson.M{"$match": textFilter})
+		pipelineCount = append(pipelineCount, bson.M{"$match": textFilter})
+	}
+	pipeline = append(pipeline, hiddenStages(req.EntityType)...)
+	pipelineCount = append(pipelineCount, hiddenStages(req.EntityType)...)
 	if len(filter) > 0 {
 		pipeline = append(pipeline, bson.M{"$match": filter})
 		pipelineCount = append(pipelineCount, bson.M{"$match": filter})
 	}
 
+	if search != nil && search.mode == searchModeText {
+		pipeline = append(pipeline,
+			bson.M{"$addFields": bson.M{scoreField: bson.M{"$meta": "textScore"}}},
+			bson.M{"$sort": bson.M{scoreField: -1}},
+		)
+	}
+
 	for _, stage := range req.Pipeline {
-		stageDoc := helper.StructToDoc(stage)
-		pipeline = append(pipeline, stageDoc)
+		stages, err := hideStage(helper.StructToDoc(stage))
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, stages...)
 	}
 
 	projection := bson.M{}
@@ -94,7 +155,152 @@ func (s *CommonService) Query(ctx context.Context, req *pb.QueryRequest) (*pb.Qu
 		for _, field := range req.Fields {
 			projection[field] = 1
 		}
+		if search != nil {
+			for _, field := range search.fields {
+				projection[field] = 1
+			}
+			if search.mode == searchModeText {
+				projection[scoreField] = 1
+			}
+		}
 	}
 
-	return s.adapter.Query(ctx, req.EntityType, pipeline, projection, req.Page, req.PageSize, pipelineCount)
+	resp, err := s.adapter.Query(ctx, req.EntityType, pipeline, projection, req.Page, req.PageSize, pipelineCount)
+	if resp != nil {
+		hideEntity(req.EntityType, resp.Entities...)
+	}
+	if err != nil || !resp.Success || search == nil {
+		return resp, err
+	}
+
+	resp.SearchMode = search.mode
+	resp.Hits = buildSearchHits(resp.Entities, search, req)
+	return resp, nil
+}
+
+const (
+	searchModeAuto  = "auto"
+	searchModeText  = "text"
+	searchModeRegex = "regex"
+
+	scoreField          = "_score"
+	maxSnippetsPerField = 3
+)
+
+type searchPlan struct {
+	mode string
+	// fields được tìm kiếm và dùng để highlight
+	fields []string
+}
+
+// planSearch chooses between the collection's text index and an escaped regex
+func (s *CommonService) planSearch(ctx context.Context, req *pb.QueryRequest) (*searchPlan, error) {
+	mode := req.SearchMode
+	if mode == "" {
+		mode = searchModeAuto
+	}
+
+	switch mode {
+	case searchModeRegex:
+		if len(req.SearchFields) == 0 {
+			return nil, fmt.Errorf("search_fields is required for regex search")
+		}
+		return &searchPlan{mode: searchModeRegex, fields: req.SearchFields}, nil
+
+	case searchModeText, searchModeAuto:
+		textFields, err := s.adapter.TextIndexFields(ctx, req.EntityType)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(textFields) > 0 && coversFields(textFields, req.SearchFields) {
+			fields := req.SearchFields
+			if len(fields) == 0 {
+				fields = textFields
+			}
+			return &searchPlan{mode: searchModeText, fields: fields}, nil
+		}
+
+		if mode == searchModeText {
+			if len(textFields) == 0 {
+				return nil, fmt.Errorf("%s has no text index", req.EntityType)
+			}
+			return nil, fmt.Errorf("search_fields are not covered by the text index of %s", req.EntityType)
+		}
+
+		if len(req.SearchFields) == 0 {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("%s has no text index, set search_fields to search it", req.EntityType))
+		}
+		return &searchPlan{mode: searchModeRegex, fields: req.SearchFields}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported search_mode %q", req.SearchMode)
+	}
+}
+
+func coversFields(indexed []string, fields []string) bool {
+	for _, field := range fields {
+		found := false
+		for _, f := range indexed {
+			if f == field || f == "$**" {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func buildSearchHits(entities []*structpb.Struct, search *searchPlan, req *pb.QueryRequest) []*pb.SearchHit {
+	terms := helper.SearchTerms(req.Query)
+	if search.mode == searchModeRegex {
+		// Regex mode matches the whole query as a phrase
+		terms = []string{req.Query}
+	}
+
+	hits := make([]*pb.SearchHit, len(entities))
+	for i, entity := range entities {
+		hit := &pb.SearchHit{}
+		hits[i] = hit
+		if entity == nil {
+			continue
+		}
+
+		hit.Id = entity.Fields["_id"].GetStringValue()
+		if score, ok := entity.Fields[scoreField]; ok {
+			hit.Score = score.GetNumberValue()
+			delete(entity.Fields, scoreField)
+		}
+
+		if !req.Highlight {
+			continue
+		}
+		doc := entity.AsMap()
+		for _, field := range search.fields {
+			value, ok := helper.LookupPath(doc, field)
+			if !ok {
+				continue
+			}
+
+			var snippets []string
+			switch v := value.(type) {
+			case string:
+				snippets = helper.Highlight(v, terms, req.AccentSensitive, maxSnippetsPerField)
+			case []interface{}:
+				for _, item := range v {
+					if str, ok := item.(string); ok {
+						snippets = append(snippets, helper.Highlight(str, terms, req.AccentSensitive, maxSnippetsPerField)...)
+					}
+				}
+			}
+
+			if len(snippets) > 0 {
+				hit.Highlights = append(hit.Highlights, &pb.Highlight{Field: field, Snippets: snippets})
+			}
+		}
+	}
+	return hits
 }
@@ -1,17 +1,85 @@
 package resolvers
 
 import (
+	"context"
+	"errors"
 	pb "thaily/proto/common"
+	"thaily/services/_common/authn"
+	"thaily/services/_common/history"
+	"thaily/services/_common/hooks"
+	"thaily/services/_common/integrity"
+	"thaily/services/_common/search"
+	"thaily/services/_common/sequence"
+	"thaily/services/_common/storage"
+	"thaily/services/_common/workflow"
 	"thaily/services/adapter"
+
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
+// errWriteFailed aborts a hooked write whose adapter response already
+// describes the failure; the response is returned to the client unchanged
+var errWriteFailed = errors.New("write failed")
+
 type CommonService struct {
 	pb.UnimplementedCommonServiceServer
-	adapter *adapter.MongoDBAdapter
+	adapter     *adapter.MongoDBAdapter
+	searchIndex *search.Index
+	fileStore   *storage.FileStore
+	history     *history.Store
+	integrity   *integrity.Checker
+	sequence    *sequence.Generator
+	workflow    *workflow.Engine
+	tasks       hooks.TaskEnqueuer
 }
 
 func NewCommonService(adapter *adapter.MongoDBAdapter) *CommonService {
 	return &CommonService{
-		adapter: adapter,
+		adapter:   adapter,
+		integrity: integrity.NewChecker(adapter.GetDatabase()),
+		history:   history.NewStore(adapter.GetDatabase()),
+		workflow:  workflow.NewEngine(adapter.GetDatabase()),
+		sequence:  sequence.NewGenerator(adapter.GetDatabase()),
+	}
+}
+
+// SetSearchIndex enables the Search RPC and keeps the index in sync on writes
+func (s *CommonService) SetSearchIndex(index *search.Index) {
+	s.searchIndex = index
+}
+
+// SetFileStore enables removal of attachments when their owning entity is deleted
+func (s *CommonService) SetFileStore(store *storage.FileStore) {
+	s.fileStore = store
+}
+
+// SetTaskQueue lets hooks enqueue background tasks
+func (s *CommonService) SetTaskQueue(tasks hooks.TaskEnqueuer) {
+	s.tasks = tasks
+}
+
+// newOperation prepares a hooked write. user_id and roles of meta always come
+// from the verified access token, whatever the client sent.
+func (s *CommonService) newOperation(ctx context.Context, entityType string, event hooks.Event, meta *structpb.Struct) *hooks.Operation {
+	op := hooks.NewOperation(entityType, event, s.adapter.GetDatabase(), s.tasks)
+	op.Meta = map[string]interface{}{}
+	if meta != nil {
+		op.Meta = meta.AsMap()
+	}
+	delete(op.Meta, "user_id")
+	delete(op.Meta, "roles")
+	if claims := authn.FromContext(ctx); claims != nil {
+		op.Meta["user_id"] = claims.UserID
+		op.Meta["roles"] = claims.Roles
+	}
+	return op
+}
+
+// withHooks runs fn in a transaction when hooks are registered for
+// entityType, so whatever the hooks write commits or rolls back with the entity
+func (s *CommonService) withHooks(ctx context.Context, entityType string, fn func(ctx context.Context) error) error {
+	if !hooks.Has(entityType) {
+		return fn(ctx)
 	}
+	return s.adapter.RunInTransaction(ctx, fn)
 }
@@ -0,0 +1,173 @@
+package resolvers
+
+import (
+	"context"
+	"log"
+	pb "thaily/proto/common"
+	"thaily/services/_common/search"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func (s *CommonService) Search(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResponse, error) {
+	if s.searchIndex == nil {
+		return &pb.SearchResponse{
+			Success: false,
+			Message: "search index is not enabled",
+		}, nil
+	}
+
+	if req.Query == "" {
+		return &pb.SearchResponse{
+			Success: false,
+			Message: "query is required",
+		}, nil
+	}
+
+	for _, entityType := range req.EntityTypes {
+		if !search.Handles(entityType) {
+			return &pb.SearchResponse{
+				Success: false,
+				Message: "entity_type " + entityType + " is not searchable",
+			}, nil
+		}
+	}
+
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	} else if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+
+	searchReq := search.Request{
+		Query:       req.Query,
+		EntityTypes: req.EntityTypes,
+		Filters:     req.Filters,
+		Facets:      req.Facets,
+		Highlight:   req.Highlight,
+		Page:        int(req.Page),
+		PageSize:    int(req.PageSize),
+	}
+	if req.Fuzziness != nil {
+		fuzziness := int(req.Fuzziness.GetValue())
+		searchReq.Fuzziness = &fuzziness
+	}
+
+	startTime := time.Now()
+	result, err := s.searchIndex.Search(searchReq)
+	if err != nil {
+		return &pb.SearchResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	// Load the live documents for the hits, grouped per collection
+	idsByType := map[string][]primitive.ObjectID{}
+	for _, hit := range result.Hits {
+		if id, err := primitive.ObjectIDFromHex(hit.ID); err == nil {
+			idsByType[hit.EntityType] = append(idsByType[hit.EntityType], id)
+		}
+	}
+	entitiesByType := map[string]map[string]*structpb.Struct{}
+	for entityType, ids := range idsByType {
+		entities, err := s.adapter.FindByIds(ctx, entityType, ids, nil)
+		if err != nil {
+			return &pb.SearchResponse{
+				Success: false,
+				Message: err.Error(),
+			}, nil
+		}
+		entitiesByType[entityType] = entities
+	}
+
+	hits := make([]*pb.SearchHit, len(result.Hits))
+	entities := make([]*structpb.Struct, len(result.Hits))
+	for i, hit := range result.Hits {
+		hits[i] = &pb.SearchHit{
+			Id:         hit.ID,
+			EntityType: hit.EntityType,
+			Score:      hit.Score,
+		}
+		for field, fragments := range hit.Highlights {
+			hits[i].Highlights = append(hits[i].Highlights, &pb.Highlight{Field: field, Snippets: fragments})
+		}
+		entities[i] = entitiesByType[hit.EntityType][hit.ID]
+	}
+
+	facets := make([]*pb.Facet, 0, len(result.Facets))
+	for _, name := range req.Facets {
+		facet := &pb.Facet{Name: name}
+		for _, term := range result.Facets[name] {
+			facet.Terms = append(facet.Terms, &pb.FacetTerm{Term: term.Term, Count: int64(term.Count)})
+		}
+		facets = append(facets, facet)
+	}
+
+	totalPages := int32((result.Total + uint64(req.PageSize) - 1) / uint64(req.PageSize))
+
+	return &pb.SearchResponse{
+		Success:  true,
+		Message:  "Search executed successfully",
+		Hits:     hits,
+		Entities: entities,
+		Pagination: &pb.Pagination{
+			CurrentPage: req.Page,
+			PageSize:    req.PageSize,
+			TotalPages:  totalPages,
+			TotalItems:  int64(result.Total),
+		},
+		Facets:          facets,
+		ExecutionTimeMs: time.Since(startTime).Milliseconds(),
+	}, nil
+}
+
+// indexSearch pushes written entities into the search index. Failures are
+// only logged: the index can always be rebuilt from MongoDB.
+func (s *CommonService) indexSearch(entityType string, entities ...*structpb.Struct) {
+	if s.searchIndex == nil || !search.Handles(entityType) {
+		return
+	}
+	for _, entity := range entities {
+		if entity == nil {
+			continue
+		}
+		if err := s.searchIndex.IndexEntity(entityType, entity.AsMap()); err != nil {
+			log.Printf("Failed to index %s: %v", entityType, err)
+		}
+	}
+}
+
+func (s *CommonService) reindexSearch(ctx context.Context, entityType string, ids []string) {
+	if s.searchIndex == nil || !search.Handles(entityType) || len(ids) == 0 {
+		return
+	}
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+	entities, err := s.adapter.FindByIds(ctx, entityType, objectIDs, nil)
+	if err != nil {
+		log.Printf("Failed to load %s for indexing: %v", entityType, err)
+		return
+	}
+	for _, entity := range entities {
+		s.indexSearch(entityType, entity)
+	}
+}
+
+func (s *CommonService) unindexSearch(entityType string, ids ...string) {
+	if s.searchIndex == nil || !search.Handles(entityType) || len(ids) == 0 {
+		return
+	}
+	if err := s.searchIndex.DeleteEntities(entityType, ids...); err != nil {
+		log.Printf("Failed to remove %s from search index: %v", entityType, err)
+	}
+}
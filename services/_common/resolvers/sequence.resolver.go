@@ -0,0 +1,48 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	pb "thaily/proto/common"
+)
+
+const maxSequenceCount = 1000
+
+func (s *CommonService) NextSequence(ctx context.Context, req *pb.NextSequenceRequest) (*pb.NextSequenceResponse, error) {
+	if req.Name == "" {
+		return &pb.NextSequenceResponse{
+			Success: false,
+			Message: "name is required",
+		}, nil
+	}
+
+	count := req.Count
+	if count <= 0 {
+		count = 1
+	}
+	if count > maxSequenceCount {
+		return &pb.NextSequenceResponse{
+			Success: false,
+			Message: fmt.Sprintf("count cannot exceed %d", maxSequenceCount),
+		}, nil
+	}
+
+	first, err := s.sequence.NextN(ctx, req.Name, int64(count))
+	if err != nil {
+		return &pb.NextSequenceResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	values := make([]int64, count)
+	for i := range values {
+		values[i] = first + int64(i)
+	}
+
+	return &pb.NextSequenceResponse{
+		Success: true,
+		Message: "Sequence reserved successfully",
+		Values:  values,
+	}, nil
+}
@@ -0,0 +1,140 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	pb "thaily/proto/common"
+	"thaily/services/_common/similarity"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *CommonService) CheckSimilarity(ctx context.Context, req *pb.CheckSimilarityRequest) (*pb.SimilarityReportResponse, error) {
+	entityType := req.EntityType
+	if entityType == "" {
+		entityType = similarity.SourceSubmissions
+	}
+	if entityType != similarity.SourceSubmissions && entityType != similarity.SourceArchivedTheses {
+		return &pb.SimilarityReportResponse{
+			Success: false,
+			Message: fmt.Sprintf("entity_type must be %s or %s", similarity.SourceSubmissions, similarity.SourceArchivedTheses),
+		}, nil
+	}
+	if _, err := primitive.ObjectIDFromHex(req.Id); err != nil {
+		return &pb.SimilarityReportResponse{
+			Success: false,
+			Message: fmt.Sprintf("invalid ID format: %v", err),
+		}, nil
+	}
+	if s.fileStore == nil {
+		return &pb.SimilarityReportResponse{
+			Success: false,
+			Message: "file storage is not configured",
+		}, nil
+	}
+
+	checker := similarity.NewChecker(s.adapter.GetDatabase(), s.fileStore)
+	if entityType == similarity.SourceArchivedTheses {
+		fp, err := checker.Fingerprint(ctx, entityType, req.Id)
+		if err != nil {
+			return &pb.SimilarityReportResponse{Success: false, Message: err.Error()}, nil
+		}
+		if fp == nil {
+			return &pb.SimilarityReportResponse{
+				Success: false,
+				Message: fmt.Sprintf("%s %s not found", entityType, req.Id),
+			}, nil
+		}
+		return &pb.SimilarityReportResponse{
+			Success: true,
+			Message: fmt.Sprintf("fingerprint of %d words stored", fp.Words),
+		}, nil
+	}
+
+	report, err := checker.Check(ctx, req.Id)
+	if report == nil && err == nil {
+		return &pb.SimilarityReportResponse{
+			Success: false,
+			Message: fmt.Sprintf("submission %s not found", req.Id),
+		}, nil
+	}
+	if err != nil {
+		return &pb.SimilarityReportResponse{
+			Success: false,
+			Message: fmt.Sprintf("similarity check failed: %v", err),
+			Report:  reportToProto(report),
+		}, nil
+	}
+	return &pb.SimilarityReportResponse{
+		Success: true,
+		Message: fmt.Sprintf("%d matches, score %.2f", len(report.Matches), report.Score),
+		Report:  reportToProto(report),
+	}, nil
+}
+
+func (s *CommonService) GetSimilarityReport(ctx context.Context, req *pb.GetSimilarityReportRequest) (*pb.SimilarityReportResponse, error) {
+	if _, err := primitive.ObjectIDFromHex(req.SubmissionId); err != nil {
+		return &pb.SimilarityReportResponse{
+			Success: false,
+			Message: fmt.Sprintf("invalid submission_id: %v", err),
+		}, nil
+	}
+
+	report, err := similarity.NewChecker(s.adapter.GetDatabase(), s.fileStore).Report(ctx, req.SubmissionId)
+	if err != nil {
+		return &pb.SimilarityReportResponse{Success: false, Message: err.Error()}, nil
+	}
+	if report == nil {
+		return &pb.SimilarityReportResponse{
+			Success: false,
+			Message: fmt.Sprintf("no similarity report for submission %s", req.SubmissionId),
+		}, nil
+	}
+	return &pb.SimilarityReportResponse{
+		Success: true,
+		Message: "Similarity report retrieved successfully",
+		Report:  reportToProto(report),
+	}, nil
+}
+
+func reportToProto(report *similarity.Report) *pb.SimilarityReport {
+	if report == nil {
+		return nil
+	}
+	result := &pb.SimilarityReport{
+		SubmissionId: report.SubmissionID,
+		ThesisId:     report.ThesisID,
+		Status:       report.Status,
+		Score:        report.Score,
+		Flagged:      report.Flagged,
+		Words:        int32(report.Words),
+		Matches:      make([]*pb.SimilarityMatch, len(report.Matches)),
+		Error:        report.Error,
+	}
+	if report.CheckedAt != nil {
+		result.CheckedAt = timestamppb.New(*report.CheckedAt)
+	}
+	for i, m := range report.Matches {
+		match := &pb.SimilarityMatch{
+			Source:      m.Source,
+			SourceId:    m.SourceID,
+			ThesisId:    m.ThesisID,
+			Title:       m.Title,
+			Similarity:  m.Similarity,
+			Containment: m.Containment,
+			Passages:    make([]*pb.OverlapPassage, len(m.Passages)),
+		}
+		for j, p := range m.Passages {
+			match.Passages[j] = &pb.OverlapPassage{
+				Start:      int32(p.Start),
+				End:        int32(p.End),
+				MatchStart: int32(p.MatchStart),
+				MatchEnd:   int32(p.MatchEnd),
+				Text:       p.Text,
+			}
+		}
+		result.Matches[i] = match
+	}
+	return result
+}
@@ -2,9 +2,11 @@ package resolvers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	pb "thaily/proto/common"
 	"thaily/services/_common/helper"
+	"thaily/services/_common/hooks"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -43,14 +45,58 @@ func (s *CommonService) Update(ctx context.Context, req *pb.UpdateRequest) (*pb.
 
 	updateDoc := helper.StructToDoc(req.Data)
 	updateDoc["updatedAt"] = time.Now()
-
-	var update bson.M
-	if req.PartialUpdate {
-		update = bson.M{"$set": updateDoc}
-	} else {
+	if !req.PartialUpdate {
 		delete(updateDoc, "_id")
-		update = bson.M{"$set": updateDoc}
 	}
 
-	return s.adapter.Update(ctx, req.EntityType, id, update)
+	op := s.newOperation(ctx, req.EntityType, hooks.BeforeUpdate, req.Meta)
+	op.ID = req.Id
+	op.Data = updateDoc
+
+	var resp *pb.GenericResponse
+	err = s.withHooks(ctx, req.EntityType, func(ctx context.Context) error {
+		var err error
+		resp, err = s.applyUpdate(ctx, op, id)
+		return err
+	})
+	if errors.Is(err, errWriteFailed) {
+		return resp, nil
+	}
+	if err != nil {
+		rejection := hooks.AsRejection(err)
+		return &pb.GenericResponse{
+			Success: false,
+			Message: rejection.Message,
+			Errors:  rejection.Details,
+		}, nil
+	}
+
+	op.Commit(ctx)
+	s.indexSearch(req.EntityType, resp.Entity)
+	hideEntity(req.EntityType, resp.Entity)
+	return resp, nil
+}
+
+// applyUpdate runs the update hooks and reference checks around $set of
+// op.Data, inside the transaction of the caller
+func (s *CommonService) applyUpdate(ctx context.Context, op *hooks.Operation, id primitive.ObjectID) (*pb.GenericResponse, error) {
+	if err := hooks.Run(ctx, op); err != nil {
+		return nil, err
+	}
+
+	if message, details := s.checkReferences(ctx, op.EntityType, op.Data); message != "" {
+		return nil, &hooks.Rejection{Message: message, Details: details}
+	}
+
+	resp, err := s.adapter.Update(ctx, op.EntityType, id, bson.M{"$set": op.Data})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return resp, errWriteFailed
+	}
+
+	after := op.Next(hooks.AfterUpdate)
+	after.Entity = resp.Entity.AsMap()
+	return resp, hooks.Run(ctx, after)
 }
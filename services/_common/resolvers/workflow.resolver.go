@@ -0,0 +1,138 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	pb "thaily/proto/common"
+	"thaily/services/_common/hooks"
+	"thaily/services/_common/workflow"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *CommonService) TransitionThesis(ctx context.Context, req *pb.TransitionThesisRequest) (*pb.TransitionThesisResponse, error) {
+	if req.ThesisId == "" {
+		return &pb.TransitionThesisResponse{
+			Success: false,
+			Message: "thesis_id is required",
+		}, nil
+	}
+
+	to := workflow.State(req.ToState)
+	if !workflow.IsState(req.ToState) {
+		return &pb.TransitionThesisResponse{
+			Success: false,
+			Message: fmt.Sprintf("unknown state %q", req.ToState),
+		}, nil
+	}
+
+	id, err := primitive.ObjectIDFromHex(req.ThesisId)
+	if err != nil {
+		return &pb.TransitionThesisResponse{
+			Success: false,
+			Message: fmt.Sprintf("invalid ID format: %v", err),
+		}, nil
+	}
+
+	op := s.newOperation(ctx, "theses", hooks.BeforeUpdate, req.Meta)
+	op.ID = req.ThesisId
+	actor, ok := workflow.ActorFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "access token is required")
+	}
+
+	var from workflow.State
+	var resp *pb.GenericResponse
+	err = s.withHooks(ctx, "theses", func(ctx context.Context) error {
+		thesis, err := s.currentDocument(ctx, "theses", id)
+		if err != nil {
+			return err
+		}
+		if thesis == nil {
+			return hooks.Reject("Entity not found")
+		}
+
+		from, err = s.workflow.StateOf(ctx, thesis["status_id"])
+		if err != nil {
+			return err
+		}
+		if from == "" {
+			return hooks.Reject("the current status of the thesis is not part of the workflow")
+		}
+
+		if _, err := s.workflow.Authorize(ctx, thesis, from, to, actor); err != nil {
+			return err
+		}
+		statusID, err := s.workflow.StatusID(ctx, to)
+		if err != nil {
+			return err
+		}
+
+		op.Data = map[string]interface{}{
+			"status_id": statusID,
+			"updatedAt": time.Now(),
+		}
+		workflow.MarkTransition(op, from, to)
+		if err := hooks.Run(ctx, op); err != nil {
+			return err
+		}
+
+		resp, err = s.adapter.Update(ctx, "theses", id, bson.M{"$set": op.Data})
+		if err != nil {
+			return err
+		}
+		if !resp.Success {
+			return errWriteFailed
+		}
+
+		if err := s.workflow.LogTransition(ctx, id, from, to, actor, req.Reason); err != nil {
+			return err
+		}
+
+		after := op.Next(hooks.AfterUpdate)
+		after.Entity = resp.Entity.AsMap()
+		return hooks.Run(ctx, after)
+	})
+	if errors.Is(err, errWriteFailed) {
+		return &pb.TransitionThesisResponse{
+			Success:   false,
+			Message:   resp.Message,
+			FromState: string(from),
+			Errors:    resp.Errors,
+		}, nil
+	}
+	if err != nil {
+		rejection := hooks.AsRejection(err)
+		return &pb.TransitionThesisResponse{
+			Success:       false,
+			Message:       rejection.Message,
+			FromState:     string(from),
+			Errors:        rejection.Details,
+			AllowedStates: allowedStates(from),
+		}, nil
+	}
+
+	op.Commit(ctx)
+	s.indexSearch("theses", resp.Entity)
+	return &pb.TransitionThesisResponse{
+		Success:       true,
+		Message:       fmt.Sprintf("Thesis moved from %s to %s", from, to),
+		FromState:     string(from),
+		ToState:       string(to),
+		Entity:        resp.Entity,
+		AllowedStates: allowedStates(to),
+	}, nil
+}
+
+func allowedStates(state workflow.State) []string {
+	result := []string{}
+	for _, next := range workflow.AllowedFrom(state) {
+		result = append(result, string(next))
+	}
+	return result
+}
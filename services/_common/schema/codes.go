@@ -0,0 +1,49 @@
+package schema
+
+// CodeTemplate generates a human-readable code into Field when a document is
+// created without one. Pattern placeholders:
+//
+//	{seq}, {seq:04}  next value of the sequence, optionally zero padded
+//	{year}           current year
+//	{<field>}        value of another field of the document, e.g. {major}
+//
+// Each distinct rendering of the non-sequence parts has its own counter, so
+// LV{year}-{seq:04} restarts every year and SV{major}{seq} counts per major.
+type CodeTemplate struct {
+	Field   string
+	Pattern string
+	// When restricts the template to documents whose fields equal these values
+	When map[string]string
+}
+
+// Mẫu đầu tiên khớp với document sẽ được dùng
+var CodeTemplates = map[string][]CodeTemplate{
+	"theses": {
+		{Field: "code", Pattern: "LV{year}-{seq:04}"},
+	},
+	"users": {
+		{Field: "code", Pattern: "SV{major}{seq:04}", When: map[string]string{"role": "student"}},
+		{Field: "code", Pattern: "GV{seq:03}", When: map[string]string{"role": "teacher"}},
+		{Field: "code", Pattern: "USR{seq:05}"},
+	},
+	"defense_schedules": {
+		{Field: "code", Pattern: "BV{year}-{seq:03}"},
+	},
+}
+
+// CodeTemplateFor returns the template applying to doc, if any
+func CodeTemplateFor(entityType string, doc map[string]interface{}) (CodeTemplate, bool) {
+	for _, template := range CodeTemplates[entityType] {
+		matched := true
+		for field, value := range template.When {
+			if v, _ := doc[field].(string); v != value {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return template, true
+		}
+	}
+	return CodeTemplate{}, false
+}
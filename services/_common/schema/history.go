@@ -0,0 +1,16 @@
+package schema
+
+// Các entity bật lưu lịch sử: mỗi lần Update/Delete, bản trước đó được lưu
+// vào collection <entity>_history
+var HistoryEntities = map[string]bool{
+	"theses":  true,
+	"reviews": true,
+}
+
+func HistoryEnabled(entityType string) bool {
+	return HistoryEntities[entityType]
+}
+
+func HistoryCollection(entityType string) string {
+	return entityType + "_history"
+}
@@ -0,0 +1,30 @@
+package schema
+
+// Các collection chứa bí mật của auth service (private key, refresh token,
+// state đăng nhập SSO, token đặt lại mật khẩu), quyền của vai trò (chỉ đổi
+// qua AuthService), bộ đếm mã (sequence.CountersCollection) và các migration
+// đã chạy cùng khóa migration (migrations.CollectionName). CRUD chung không
+// được chạm tới
+var ProtectedCollections = map[string]bool{
+	"signing_keys":    true,
+	"refresh_tokens":  true,
+	"oidc_states":     true,
+	"password_resets": true,
+	"roles":           true,
+	"_counters":       true,
+	"_migrations":     true,
+}
+
+func Protected(entityType string) bool {
+	return ProtectedCollections[entityType]
+}
+
+// Các field không bao giờ trả ra qua CRUD chung, kể cả qua $lookup từ
+// collection khác
+var HiddenFields = map[string][]string{
+	"users": {"password"},
+}
+
+func Hidden(entityType string) []string {
+	return HiddenFields[entityType]
+}
@@ -0,0 +1,80 @@
+package schema
+
+// OnDelete decides what happens to referencing documents when the referenced
+// one is deleted
+type OnDelete string
+
+const (
+	// Restrict refuses the delete while references exist
+	Restrict OnDelete = "restrict"
+	// Cascade deletes the referencing documents too
+	Cascade OnDelete = "cascade"
+	// SetNull clears the reference field
+	SetNull OnDelete = "set_null"
+)
+
+// Relation declares that Entity.Field holds the _id of a document in Target
+type Relation struct {
+	Entity   string
+	Field    string
+	Target   string
+	OnDelete OnDelete
+}
+
+// Quan hệ giữa các collection, theo dữ liệu trong mongodb-data-generator
+var Relations = []Relation{
+	{Entity: "users", Field: "role_id", Target: "roles", OnDelete: Restrict},
+	{Entity: "departments", Field: "head_id", Target: "users", OnDelete: SetNull},
+
+	{Entity: "theses", Field: "status_id", Target: "thesis_statuses", OnDelete: Restrict},
+	{Entity: "theses", Field: "student_id", Target: "users", OnDelete: Restrict},
+	{Entity: "theses", Field: "supervisor_id", Target: "users", OnDelete: Restrict},
+
+	{Entity: "supervisor_assignments", Field: "thesis_id", Target: "theses", OnDelete: Cascade},
+	{Entity: "supervisor_assignments", Field: "supervisor_id", Target: "users", OnDelete: Cascade},
+	{Entity: "supervisor_assignments", Field: "assigned_by", Target: "users", OnDelete: SetNull},
+
+	{Entity: "submissions", Field: "thesis_id", Target: "theses", OnDelete: Cascade},
+	{Entity: "submissions", Field: "submitted_by", Target: "users", OnDelete: Restrict},
+
+	{Entity: "reviews", Field: "submission_id", Target: "submissions", OnDelete: Cascade},
+	{Entity: "reviews", Field: "reviewer_id", Target: "users", OnDelete: Restrict},
+	{Entity: "similarity_reports", Field: "submission_id", Target: "submissions", OnDelete: Cascade},
+
+	{Entity: "defense_schedules", Field: "thesis_id", Target: "theses", OnDelete: Cascade},
+	{Entity: "defense_scores", Field: "defense_schedule_id", Target: "defense_schedules", OnDelete: Cascade},
+	{Entity: "defense_scores", Field: "scorer_id", Target: "users", OnDelete: Restrict},
+
+	// Điểm đã tính là hồ sơ học vụ, phải xoá tường minh trước khi xoá luận văn
+	{Entity: "final_grades", Field: "thesis_id", Target: "theses", OnDelete: Restrict},
+	{Entity: "grade_computations", Field: "thesis_id", Target: "theses", OnDelete: Restrict},
+
+	{Entity: "event_logs", Field: "user_id", Target: "users", OnDelete: SetNull},
+
+	// Bản lưu trữ giữ lại snapshot nên không ràng buộc với theses/submissions gốc
+	{Entity: "archived_theses", Field: "archived_by", Target: "users", OnDelete: SetNull},
+	{Entity: "archived_submissions", Field: "archived_thesis_id", Target: "archived_theses", OnDelete: Cascade},
+	{Entity: "archived_reviews", Field: "archived_submission_id", Target: "archived_submissions", OnDelete: Cascade},
+}
+
+// References returns the reference fields declared on entityType
+func References(entityType string) []Relation {
+	var result []Relation
+	for _, relation := range Relations {
+		if relation.Entity == entityType {
+			result = append(result, relation)
+		}
+	}
+	return result
+}
+
+// Dependents returns the relations pointing at entityType
+func Dependents(entityType string) []Relation {
+	var result []Relation
+	for _, relation := range Relations {
+		if relation.Target == entityType {
+			result = append(result, relation)
+		}
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package schema
+
+import "strings"
+
+// UniqueConstraint is enforced by a unique index named IndexName(). Documents
+// missing one of the fields are not constrained.
+type UniqueConstraint struct {
+	Fields []string
+	// CaseInsensitive builds the index with a strength-2 collation, so
+	// "A@x.vn" and "a@x.vn" collide
+	CaseInsensitive bool
+}
+
+func (u UniqueConstraint) IndexName() string {
+	return "uniq_" + strings.Join(u.Fields, "_")
+}
+
+// Ràng buộc duy nhất theo entity type, index được tạo khi service khởi động
+var UniqueConstraints = map[string][]UniqueConstraint{
+	"users": {
+		{Fields: []string{"email"}, CaseInsensitive: true},
+		{Fields: []string{"code"}},
+	},
+	"roles": {
+		{Fields: []string{"name"}, CaseInsensitive: true},
+	},
+	"departments": {
+		{Fields: []string{"code"}, CaseInsensitive: true},
+	},
+	"thesis_statuses": {
+		{Fields: []string{"name"}},
+		{Fields: []string{"key"}},
+	},
+	"theses": {
+		{Fields: []string{"code"}},
+	},
+	"defense_schedules": {
+		{Fields: []string{"code"}},
+	},
+	"supervisor_assignments": {
+		{Fields: []string{"thesis_id", "supervisor_id"}},
+	},
+	"reviews": {
+		{Fields: []string{"submission_id", "reviewer_id"}},
+	},
+	"final_grades": {
+		{Fields: []string{"thesis_id"}},
+	},
+}
+
+// UniqueConstraintByIndex finds the declared constraint behind an index name
+func UniqueConstraintByIndex(entityType, indexName string) (UniqueConstraint, bool) {
+	for _, constraint := range UniqueConstraints[entityType] {
+		if constraint.IndexName() == indexName {
+			return constraint, true
+		}
+	}
+	return UniqueConstraint{}, false
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"thaily/services/_common/helper"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/v2/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/v2/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/registry"
+	"github.com/blevesearch/bleve/v2/search/highlight"
+	htmlFormatter "github.com/blevesearch/bleve/v2/search/highlight/format/html"
+	simpleFragmenter "github.com/blevesearch/bleve/v2/search/highlight/fragmenter/simple"
+	simpleHighlighter "github.com/blevesearch/bleve/v2/search/highlight/highlighter/simple"
+)
+
+const (
+	foldFilterName  = "vi_fold"
+	analyzerName    = "vi"
+	highlighterName = "vi_em"
+)
+
+// vietnameseFoldFilter bỏ dấu tiếng Việt để "luan van" khớp với "luận văn"
+type vietnameseFoldFilter struct{}
+
+func (vietnameseFoldFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		token.Term = []byte(helper.FoldVietnamese(string(token.Term)))
+	}
+	return input
+}
+
+func init() {
+	err := registry.RegisterTokenFilter(foldFilterName, func(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+		return vietnameseFoldFilter{}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Same markup as the highlights returned by Query
+	err = registry.RegisterHighlighter(highlighterName, func(config map[string]interface{}, cache *registry.Cache) (highlight.Highlighter, error) {
+		fragmenter, err := cache.FragmenterNamed(simpleFragmenter.Name)
+		if err != nil {
+			return nil, err
+		}
+		return simpleHighlighter.NewHighlighter(
+			fragmenter,
+			htmlFormatter.NewFragmentFormatter("<em>", "</em>"),
+			simpleHighlighter.DefaultSeparator,
+		), nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func addVietnameseAnalyzer(m *mapping.IndexMappingImpl) error {
+	return m.AddCustomAnalyzer(analyzerName, map[string]interface{}{
+		"type":      custom.Name,
+		"tokenizer": unicode.Name,
+		"token_filters": []string{
+			lowercase.Name,
+			foldFilterName,
+		},
+	})
+}
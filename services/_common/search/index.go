@@ -0,0 +1,251 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"thaily/services/_common/helper"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Các collection được đồng bộ vào search index
+var IndexedEntityTypes = []string{"theses", "archived_theses"}
+
+const rebuildBatchSize = 500
+
+// Index is an embedded Bleve index over theses and archived theses
+type Index struct {
+	path string
+	// mu guards index, which Rebuild closes and replaces
+	mu    sync.RWMutex
+	index bleve.Index
+}
+
+// document is what gets stored in Bleve for one thesis
+type document struct {
+	EntityType string   `json:"entity_type"`
+	Title      string   `json:"title"`
+	Abstract   string   `json:"abstract"`
+	Keywords   []string `json:"keywords"`
+	Major      string   `json:"major"`
+	Year       string   `json:"year"`
+}
+
+// Open opens the index at path, creating it when it does not exist yet
+func Open(path string) (*Index, error) {
+	index, err := bleve.Open(path)
+	if err == bleve.ErrorIndexPathDoesNotExist {
+		index, err = newIndex(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open search index %s: %w", path, err)
+	}
+	return &Index{path: path, index: index}, nil
+}
+
+func newIndex(path string) (bleve.Index, error) {
+	indexMapping, err := buildMapping()
+	if err != nil {
+		return nil, err
+	}
+	return bleve.New(path, indexMapping)
+}
+
+func buildMapping() (*mapping.IndexMappingImpl, error) {
+	indexMapping := bleve.NewIndexMapping()
+	if err := addVietnameseAnalyzer(indexMapping); err != nil {
+		return nil, err
+	}
+	indexMapping.DefaultAnalyzer = analyzerName
+
+	text := bleve.NewTextFieldMapping()
+	text.Analyzer = analyzerName
+
+	keywordField := bleve.NewTextFieldMapping()
+	keywordField.Analyzer = keyword.Name
+
+	docMapping := bleve.NewDocumentMapping()
+	docMapping.AddFieldMappingsAt("title", text)
+	docMapping.AddFieldMappingsAt("abstract", text)
+	docMapping.AddFieldMappingsAt("keywords", text)
+	docMapping.AddFieldMappingsAt("entity_type", keywordField)
+	docMapping.AddFieldMappingsAt("major", keywordField)
+	docMapping.AddFieldMappingsAt("year", keywordField)
+
+	indexMapping.DefaultMapping = docMapping
+	return indexMapping, nil
+}
+
+func (i *Index) Close() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.index.Close()
+}
+
+// Handles reports whether entityType is kept in the search index
+func Handles(entityType string) bool {
+	for _, t := range IndexedEntityTypes {
+		if t == entityType {
+			return true
+		}
+	}
+	return false
+}
+
+func docID(entityType, id string) string {
+	return entityType + "/" + id
+}
+
+func splitDocID(docID string) (string, string) {
+	entityType, id, _ := strings.Cut(docID, "/")
+	return entityType, id
+}
+
+// IndexEntity adds or replaces one entity. doc is the entity as returned by
+// the resolvers (ObjectIDs as hex strings, dates as RFC3339 strings).
+func (i *Index) IndexEntity(entityType string, doc map[string]interface{}) error {
+	id, _ := doc["_id"].(string)
+	if id == "" {
+		return fmt.Errorf("entity has no _id")
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.index.Index(docID(entityType, id), toDocument(entityType, doc))
+}
+
+// DeleteEntities removes entities from the index
+func (i *Index) DeleteEntities(entityType string, ids ...string) error {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if len(ids) == 1 {
+		return i.index.Delete(docID(entityType, ids[0]))
+	}
+	batch := i.index.NewBatch()
+	for _, id := range ids {
+		batch.Delete(docID(entityType, id))
+	}
+	return i.index.Batch(batch)
+}
+
+func toDocument(entityType string, doc map[string]interface{}) document {
+	d := document{EntityType: entityType}
+	d.Title, _ = doc["title"].(string)
+	d.Major, _ = doc["major"].(string)
+
+	d.Abstract, _ = doc["abstract"].(string)
+	if d.Abstract == "" {
+		d.Abstract, _ = doc["description"].(string)
+	}
+
+	switch keywords := doc["keywords"].(type) {
+	case []interface{}:
+		for _, k := range keywords {
+			if s, ok := k.(string); ok {
+				d.Keywords = append(d.Keywords, s)
+			}
+		}
+	case string:
+		for _, k := range strings.Split(keywords, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				d.Keywords = append(d.Keywords, k)
+			}
+		}
+	}
+
+	// archived_theses có graduation_year, theses có academic_year "2023-2024"
+	switch year := doc["graduation_year"].(type) {
+	case float64:
+		d.Year = strconv.Itoa(int(year))
+	case string:
+		d.Year = year
+	}
+	if d.Year == "" {
+		if academicYear, ok := doc["academic_year"].(string); ok {
+			d.Year, _, _ = strings.Cut(academicYear, "-")
+		}
+	}
+	return d
+}
+
+// Rebuild drops every document and reindexes all indexed collections from
+// MongoDB. Searches and writes wait until it is done.
+func (i *Index) Rebuild(ctx context.Context, db *mongo.Database) (int, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if err := i.index.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close index: %w", err)
+	}
+	if err := os.RemoveAll(i.path); err != nil {
+		return 0, fmt.Errorf("failed to remove index: %w", err)
+	}
+	index, err := newIndex(i.path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recreate index: %w", err)
+	}
+	i.index = index
+
+	total := 0
+	for _, entityType := range IndexedEntityTypes {
+		count, err := i.reindexCollection(ctx, db, entityType)
+		total += count
+		if err != nil {
+			return total, err
+		}
+		log.Printf("Indexed %d %s", count, entityType)
+	}
+	return total, nil
+}
+
+func (i *Index) reindexCollection(ctx context.Context, db *mongo.Database, entityType string) (int, error) {
+	cursor, err := db.Collection(entityType).Find(ctx, bson.M{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", entityType, err)
+	}
+	defer cursor.Close(ctx)
+
+	count := 0
+	batch := i.index.NewBatch()
+	for cursor.Next(ctx) {
+		var raw bson.M
+		if err := cursor.Decode(&raw); err != nil {
+			return count, fmt.Errorf("failed to decode %s: %w", entityType, err)
+		}
+		entity, err := helper.DocToStruct(raw)
+		if err != nil {
+			continue
+		}
+		doc := entity.AsMap()
+		id, _ := doc["_id"].(string)
+		if err := batch.Index(docID(entityType, id), toDocument(entityType, doc)); err != nil {
+			return count, err
+		}
+		count++
+
+		if batch.Size() >= rebuildBatchSize {
+			if err := i.index.Batch(batch); err != nil {
+				return count, err
+			}
+			batch.Reset()
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return count, err
+	}
+	if batch.Size() > 0 {
+		if err := i.index.Batch(batch); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,166 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// Trọng số cho từng field khi tính điểm
+var fieldBoosts = map[string]float64{
+	"title":    3,
+	"keywords": 2,
+	"abstract": 1,
+}
+
+// Các facet được hỗ trợ
+var facetFields = map[string]bool{
+	"entity_type": true,
+	"major":       true,
+	"year":        true,
+}
+
+type Request struct {
+	Query       string
+	EntityTypes []string
+	// Fuzziness is the max edit distance per term, nil = based on term length
+	Fuzziness *int
+	// Filters matches keyword fields exactly, e.g. major=CNTT
+	Filters   map[string]string
+	Facets    []string
+	Highlight bool
+	Page      int
+	PageSize  int
+}
+
+type Hit struct {
+	EntityType string
+	ID         string
+	Score      float64
+	Highlights map[string][]string
+}
+
+type FacetTerm struct {
+	Term  string
+	Count int
+}
+
+type Result struct {
+	Hits   []Hit
+	Total  uint64
+	Facets map[string][]FacetTerm
+}
+
+func (i *Index) Search(req Request) (*Result, error) {
+	if req.Query == "" {
+		return nil, fmt.Errorf("query is required")
+	}
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+
+	if req.Fuzziness != nil && (*req.Fuzziness < 0 || *req.Fuzziness > 2) {
+		return nil, fmt.Errorf("fuzziness must be between 0 and 2")
+	}
+
+	// Khớp theo từng field với trọng số, cộng điểm khi khớp nguyên cụm trong title
+	matches := make([]query.Query, 0, len(fieldBoosts)+1)
+	for field, boost := range fieldBoosts {
+		match := bleve.NewMatchQuery(req.Query)
+		match.SetField(field)
+		if req.Fuzziness != nil {
+			match.SetFuzziness(*req.Fuzziness)
+		} else {
+			match.SetAutoFuzziness(true)
+		}
+		match.SetBoost(boost)
+		matches = append(matches, match)
+	}
+	phrase := bleve.NewMatchPhraseQuery(req.Query)
+	phrase.SetField("title")
+	phrase.SetBoost(fieldBoosts["title"] * 2)
+	matches = append(matches, phrase)
+
+	conjuncts := []query.Query{bleve.NewDisjunctionQuery(matches...)}
+
+	if len(req.EntityTypes) > 0 {
+		types := make([]query.Query, len(req.EntityTypes))
+		for j, entityType := range req.EntityTypes {
+			term := bleve.NewTermQuery(entityType)
+			term.SetField("entity_type")
+			types[j] = term
+		}
+		conjuncts = append(conjuncts, bleve.NewDisjunctionQuery(types...))
+	}
+
+	for field, value := range req.Filters {
+		if !facetFields[field] {
+			return nil, fmt.Errorf("unsupported filter %q", field)
+		}
+		term := bleve.NewTermQuery(value)
+		term.SetField(field)
+		conjuncts = append(conjuncts, term)
+	}
+
+	searchReq := bleve.NewSearchRequestOptions(
+		bleve.NewConjunctionQuery(conjuncts...),
+		req.PageSize,
+		(req.Page-1)*req.PageSize,
+		false,
+	)
+	for _, field := range req.Facets {
+		if !facetFields[field] {
+			return nil, fmt.Errorf("unsupported facet %q", field)
+		}
+		searchReq.AddFacet(field, bleve.NewFacetRequest(field, 20))
+	}
+	if req.Highlight {
+		searchReq.Highlight = bleve.NewHighlightWithStyle(highlighterName)
+		searchReq.Highlight.Fields = []string{"title", "abstract", "keywords"}
+	}
+
+	i.mu.RLock()
+	res, err := i.index.Search(searchReq)
+	i.mu.RUnlock()
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	result := &Result{
+		Hits:   make([]Hit, len(res.Hits)),
+		Total:  res.Total,
+		Facets: map[string][]FacetTerm{},
+	}
+	for j, hit := range res.Hits {
+		entityType, id := splitDocID(hit.ID)
+		result.Hits[j] = Hit{
+			EntityType: entityType,
+			ID:         id,
+			Score:      hit.Score,
+			Highlights: map[string][]string{},
+		}
+		for field, fragments := range hit.Fragments {
+			for _, fragment := range fragments {
+				// Bleve also returns unmatched stored fields
+				if strings.Contains(fragment, "<em>") {
+					result.Hits[j].Highlights[field] = append(result.Hits[j].Highlights[field], fragment)
+				}
+			}
+		}
+	}
+	for name, facet := range res.Facets {
+		terms := []FacetTerm{}
+		if facet.Terms != nil {
+			for _, t := range facet.Terms.Terms() {
+				terms = append(terms, FacetTerm{Term: t.Term, Count: t.Count})
+			}
+		}
+		result.Facets[name] = terms
+	}
+	return result, nil
+}
@@ -0,0 +1,187 @@
+package sequence
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"thaily/services/_common/schema"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CounterName is the _counters id used for one rendering of a code template,
+// e.g. "theses.code:LV2025-{seq}"
+func CounterName(entityType, field, prefix string) string {
+	return entityType + "." + field + ":" + prefix
+}
+
+// renderedCode is a template with everything but the sequence filled in
+type renderedCode struct {
+	key    string
+	before string
+	after  string
+	width  int
+}
+
+func (r renderedCode) format(seq int64) string {
+	return fmt.Sprintf("%s%0*d%s", r.before, r.width, seq, r.after)
+}
+
+// render fills the non-sequence placeholders of pattern from doc
+func render(pattern string, doc map[string]interface{}, now time.Time) (renderedCode, error) {
+	var (
+		result  renderedCode
+		b       strings.Builder
+		seqSeen bool
+	)
+
+	rest := pattern
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			b.WriteString(rest)
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return result, fmt.Errorf("unclosed placeholder in %q", pattern)
+		}
+		b.WriteString(rest[:start])
+		name := rest[start+1 : start+end]
+		rest = rest[start+end+1:]
+
+		switch {
+		case name == "seq" || strings.HasPrefix(name, "seq:"):
+			if seqSeen {
+				return result, fmt.Errorf("pattern %q has more than one {seq}", pattern)
+			}
+			seqSeen = true
+			if width, ok := strings.CutPrefix(name, "seq:"); ok {
+				n, err := strconv.Atoi(width)
+				if err != nil || n < 0 || n > 18 {
+					return result, fmt.Errorf("invalid sequence width in %q", pattern)
+				}
+				result.width = n
+			}
+			result.before = b.String()
+			b.Reset()
+		case name == "year":
+			b.WriteString(strconv.Itoa(now.Year()))
+		default:
+			value := strings.Join(strings.Fields(fmt.Sprint(doc[name])), "")
+			if doc[name] == nil || value == "" {
+				return result, fmt.Errorf("%s is required to generate the code", name)
+			}
+			b.WriteString(strings.ToUpper(value))
+		}
+	}
+
+	if !seqSeen {
+		return result, fmt.Errorf("pattern %q has no {seq}", pattern)
+	}
+	result.after = b.String()
+	result.key = result.before + "{seq}" + result.after
+	return result, nil
+}
+
+// AssignCodes fills the code field of docs that match a template and do not
+// already carry a code. Documents sharing a counter reserve their values in a
+// single $inc, so concurrent batches never receive the same code.
+func (g *Generator) AssignCodes(ctx context.Context, entityType string, docs ...map[string]interface{}) error {
+	type group struct {
+		field string
+		code  renderedCode
+		docs  []map[string]interface{}
+	}
+	groups := map[string]*group{}
+	var order []string
+
+	now := time.Now()
+	for _, doc := range docs {
+		template, ok := schema.CodeTemplateFor(entityType, doc)
+		if !ok {
+			continue
+		}
+		if existing, _ := doc[template.Field].(string); existing != "" {
+			continue
+		}
+
+		code, err := render(template.Pattern, doc, now)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", entityType, template.Field, err)
+		}
+
+		name := CounterName(entityType, template.Field, code.key)
+		if groups[name] == nil {
+			groups[name] = &group{field: template.Field, code: code}
+			order = append(order, name)
+		}
+		groups[name].docs = append(groups[name].docs, doc)
+	}
+
+	for _, name := range order {
+		group := groups[name]
+		first, err := g.NextN(ctx, name, int64(len(group.docs)))
+		if err != nil {
+			return err
+		}
+		for i, doc := range group.docs {
+			doc[group.field] = group.code.format(first + int64(i))
+		}
+	}
+	return nil
+}
+
+// SyncWithExisting raises the counters of every template whose rendering does
+// not depend on document fields above the highest code already stored, so
+// generated codes never collide with imported data
+func (g *Generator) SyncWithExisting(ctx context.Context, db *mongo.Database) error {
+	now := time.Now()
+	for entityType, templates := range schema.CodeTemplates {
+		for _, template := range templates {
+			code, err := render(template.Pattern, map[string]interface{}{}, now)
+			if err != nil {
+				// Phụ thuộc vào field của document, không xác định được counter
+				continue
+			}
+
+			pattern := "^" + regexp.QuoteMeta(code.before) + `(\d+)` + regexp.QuoteMeta(code.after) + "$"
+			re := regexp.MustCompile(pattern)
+			cursor, err := db.Collection(entityType).Find(ctx,
+				bson.M{template.Field: bson.M{"$regex": pattern}},
+				options.Find().SetProjection(bson.M{template.Field: 1}),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to scan %s.%s: %w", entityType, template.Field, err)
+			}
+
+			var highest int64
+			for cursor.Next(ctx) {
+				value, _ := cursor.Current.Lookup(template.Field).StringValueOK()
+				if match := re.FindStringSubmatch(value); match != nil {
+					if n, err := strconv.ParseInt(match[1], 10, 64); err == nil && n > highest {
+						highest = n
+					}
+				}
+			}
+			err = cursor.Err()
+			cursor.Close(ctx)
+			if err != nil {
+				return err
+			}
+
+			if highest > 0 {
+				if err := g.EnsureAtLeast(ctx, CounterName(entityType, template.Field, code.key), highest); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package sequence
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Mỗi document là một bộ đếm: {_id: tên, seq: giá trị đã cấp gần nhất}
+const CountersCollection = "_counters"
+
+type Generator struct {
+	counters *mongo.Collection
+}
+
+func NewGenerator(db *mongo.Database) *Generator {
+	return &Generator{counters: db.Collection(CountersCollection)}
+}
+
+// Next reserves the next value of the named sequence, starting at 1
+func (g *Generator) Next(ctx context.Context, name string) (int64, error) {
+	return g.NextN(ctx, name, 1)
+}
+
+// NextN atomically reserves n consecutive values and returns the first one
+func (g *Generator) NextN(ctx context.Context, name string, n int64) (int64, error) {
+	if name == "" {
+		return 0, fmt.Errorf("sequence name is required")
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("count must be positive")
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+
+	var err error
+	// Hai upsert đồng thời trên counter mới có thể trùng _id, thử lại là đủ
+	for attempt := 0; attempt < 3; attempt++ {
+		err = g.counters.FindOneAndUpdate(ctx,
+			bson.M{"_id": name},
+			bson.M{"$inc": bson.M{"seq": n}},
+			opts,
+		).Decode(&counter)
+		if !mongo.IsDuplicateKeyError(err) {
+			break
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment sequence %s: %w", name, err)
+	}
+	return counter.Seq - n + 1, nil
+}
+
+// Current returns the last value handed out, 0 for unused sequences
+func (g *Generator) Current(ctx context.Context, name string) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := g.counters.FindOne(ctx, bson.M{"_id": name}).Decode(&counter)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	return counter.Seq, err
+}
+
+// EnsureAtLeast raises a sequence so the next value is above value, used when
+// existing data already holds codes
+func (g *Generator) EnsureAtLeast(ctx context.Context, name string, value int64) error {
+	_, err := g.counters.UpdateOne(ctx,
+		bson.M{"_id": name},
+		bson.M{"$max": bson.M{"seq": value}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
@@ -0,0 +1,449 @@
+package similarity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"thaily/services/_common/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Chữ ký MinHash và văn bản của từng tài liệu đã trích xuất
+	FingerprintsCollection = "similarity_fingerprints"
+	ReportsCollection      = "similarity_reports"
+)
+
+// Tài liệu được so sánh: bài nộp hiện tại và luận văn đã lưu trữ
+const (
+	SourceSubmissions    = "submissions"
+	SourceArchivedTheses = "archived_theses"
+)
+
+// Trạng thái báo cáo
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+	StatusNoText    = "no_text"
+	StatusFailed    = "failed"
+)
+
+const (
+	DefaultTopMatches = 5
+	// DefaultFlagThreshold is the share of a submission found in one document
+	// from which the submission is flagged
+	DefaultFlagThreshold = 0.3
+	// Files larger than this are not read
+	maxFileBytes     = 50 << 20
+	passagesPerMatch = 10
+)
+
+type Fingerprint struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty"`
+	Source   string             `bson:"source"`
+	SourceID string             `bson:"source_id"`
+	// Luận văn gốc, để không so bài nộp với các phiên bản của chính nó
+	ThesisID  string    `bson:"thesis_id"`
+	Title     string    `bson:"title"`
+	Files     []string  `bson:"files"`
+	Text      string    `bson:"text,omitempty"`
+	Words     int       `bson:"words"`
+	Signature []int64   `bson:"signature"`
+	Bands     []string  `bson:"bands"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+func (f *Fingerprint) signature() []uint32 {
+	sig := make([]uint32, len(f.Signature))
+	for i, v := range f.Signature {
+		sig[i] = uint32(v)
+	}
+	return sig
+}
+
+type Match struct {
+	Source   string `bson:"source" json:"source"`
+	SourceID string `bson:"source_id" json:"source_id"`
+	ThesisID string `bson:"thesis_id" json:"thesis_id"`
+	Title    string `bson:"title" json:"title"`
+	// Jaccard similarity of the two documents
+	Similarity float64 `bson:"similarity" json:"similarity"`
+	// Share of the submission found in the match
+	Containment float64   `bson:"containment" json:"containment"`
+	Passages    []Passage `bson:"passages" json:"passages"`
+}
+
+type Report struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	SubmissionID string             `bson:"submission_id"`
+	ThesisID     string             `bson:"thesis_id"`
+	Status       string             `bson:"status"`
+	// Score is the highest containment among the matches
+	Score     float64    `bson:"score"`
+	Flagged   bool       `bson:"flagged"`
+	Words     int        `bson:"words"`
+	Matches   []Match    `bson:"matches"`
+	Error     string     `bson:"error,omitempty"`
+	CheckedAt *time.Time `bson:"checked_at,omitempty"`
+	UpdatedAt time.Time  `bson:"updatedAt"`
+}
+
+type Checker struct {
+	db            *mongo.Database
+	files         *storage.FileStore
+	TopMatches    int
+	FlagThreshold float64
+}
+
+func NewChecker(db *mongo.Database, files *storage.FileStore) *Checker {
+	return &Checker{db: db, files: files, TopMatches: DefaultTopMatches, FlagThreshold: DefaultFlagThreshold}
+}
+
+func (c *Checker) EnsureIndexes(ctx context.Context) error {
+	_, err := c.db.Collection(FingerprintsCollection).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "source", Value: 1}, {Key: "source_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "bands", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create similarity fingerprint indexes: %w", err)
+	}
+	_, err = c.db.Collection(ReportsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "submission_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create similarity report index: %w", err)
+	}
+	return nil
+}
+
+// MarkPending records that a check of the submission is queued
+func MarkPending(ctx context.Context, db *mongo.Database, submissionID, thesisID string) error {
+	now := time.Now()
+	_, err := db.Collection(ReportsCollection).UpdateOne(ctx,
+		bson.M{"submission_id": submissionID},
+		bson.M{
+			"$set":         bson.M{"status": StatusPending, "thesis_id": thesisID, "updatedAt": now},
+			"$setOnInsert": bson.M{"matches": bson.A{}, "score": 0.0, "flagged": false, "words": 0},
+		},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to queue similarity check: %w", err)
+	}
+	return nil
+}
+
+// Report returns the stored report of a submission, nil when never checked
+func (c *Checker) Report(ctx context.Context, submissionID string) (*Report, error) {
+	var report Report
+	err := c.db.Collection(ReportsCollection).FindOne(ctx, bson.M{"submission_id": submissionID}).Decode(&report)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load similarity report: %w", err)
+	}
+	return &report, nil
+}
+
+// Check fingerprints a submission and compares it with the archive and the
+// other submissions. The report is stored whatever the outcome; it is nil
+// when the submission does not exist.
+func (c *Checker) Check(ctx context.Context, submissionID string) (*Report, error) {
+	report := &Report{SubmissionID: submissionID, Status: StatusCompleted, Matches: []Match{}}
+	fp, err := c.Fingerprint(ctx, SourceSubmissions, submissionID)
+	if err == nil && fp == nil {
+		return nil, nil
+	}
+	if err == nil {
+		report.ThesisID = fp.ThesisID
+		report.Words = fp.Words
+		if fp.Words < ShingleSize {
+			report.Status = StatusNoText
+		} else {
+			report.Matches, err = c.matches(ctx, fp)
+		}
+	}
+	if err != nil {
+		report.Status = StatusFailed
+		report.Error = err.Error()
+	}
+
+	for _, m := range report.Matches {
+		if m.Containment > report.Score {
+			report.Score = m.Containment
+		}
+	}
+	report.Flagged = report.Score >= c.FlagThreshold
+	now := time.Now()
+	report.CheckedAt = &now
+	report.UpdatedAt = now
+
+	_, saveErr := c.db.Collection(ReportsCollection).ReplaceOne(ctx, bson.M{"submission_id": submissionID}, report, options.Replace().SetUpsert(true))
+	if saveErr != nil {
+		return nil, fmt.Errorf("failed to save similarity report: %w", saveErr)
+	}
+	return report, err
+}
+
+// matches ranks the candidates sharing an LSH band with fp and finds the
+// overlapping passages of the best ones
+func (c *Checker) matches(ctx context.Context, fp *Fingerprint) ([]Match, error) {
+	filter := bson.M{
+		"bands": bson.M{"$in": fp.Bands},
+		"_id":   bson.M{"$ne": fp.ID},
+	}
+	if fp.ThesisID != "" {
+		filter["thesis_id"] = bson.M{"$ne": fp.ThesisID}
+	}
+	cursor, err := c.db.Collection(FingerprintsCollection).Find(ctx, filter, options.Find().SetProjection(bson.M{"text": 0}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fingerprints: %w", err)
+	}
+	var candidates []Fingerprint
+	if err := cursor.All(ctx, &candidates); err != nil {
+		return nil, fmt.Errorf("failed to load fingerprints: %w", err)
+	}
+
+	signature := fp.signature()
+	estimates := make(map[primitive.ObjectID]float64, len(candidates))
+	for _, candidate := range candidates {
+		estimates[candidate.ID] = Estimate(signature, candidate.signature())
+	}
+	sort.Slice(candidates, func(i, j int) bool { return estimates[candidates[i].ID] > estimates[candidates[j].ID] })
+	// Ước lượng có sai số nên lấy dư ứng viên trước khi so chính xác
+	if limit := 4 * c.TopMatches; len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	words := Words(fp.Text)
+	shingles := Shingles(words)
+	var matches []Match
+	for _, candidate := range candidates {
+		var full Fingerprint
+		err := c.db.Collection(FingerprintsCollection).FindOne(ctx, bson.M{"_id": candidate.ID}).Decode(&full)
+		if err == mongo.ErrNoDocuments {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fingerprint: %w", err)
+		}
+		alive, err := c.sourceExists(ctx, full.Source, full.SourceID)
+		if err != nil {
+			return nil, err
+		}
+		if !alive {
+			// Tài liệu gốc đã bị xóa (xóa dây chuyền không chạy hook)
+			c.db.Collection(FingerprintsCollection).DeleteOne(ctx, bson.M{"_id": full.ID})
+			continue
+		}
+
+		other := Words(full.Text)
+		jaccard, containment := Overlap(shingles, Shingles(other))
+		if containment == 0 {
+			continue
+		}
+		matches = append(matches, Match{
+			Source:      full.Source,
+			SourceID:    full.SourceID,
+			ThesisID:    full.ThesisID,
+			Title:       full.Title,
+			Similarity:  round(jaccard),
+			Containment: round(containment),
+			Passages:    Passages(words, other, MinPassageWords, passagesPerMatch),
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Containment > matches[j].Containment })
+	if len(matches) > c.TopMatches {
+		matches = matches[:c.TopMatches]
+	}
+	if matches == nil {
+		matches = []Match{}
+	}
+	return matches, nil
+}
+
+func (c *Checker) sourceExists(ctx context.Context, source, id string) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, nil
+	}
+	count, err := c.db.Collection(source).CountDocuments(ctx, bson.M{"_id": oid}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	return count > 0, nil
+}
+
+// Fingerprint extracts the text of a document's files and stores its
+// signature. It returns nil when the document does not exist.
+func (c *Checker) Fingerprint(ctx context.Context, source, id string) (*Fingerprint, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id %q", id)
+	}
+	var doc bson.M
+	err = c.db.Collection(source).FindOne(ctx, bson.M{"_id": oid}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", source, err)
+	}
+
+	fp := &Fingerprint{Source: source, SourceID: id, UpdatedAt: time.Now()}
+	owners := []storage.FileInfo{{EntityType: source, EntityID: id}}
+	switch source {
+	case SourceSubmissions:
+		fp.ThesisID = idString(doc["thesis_id"])
+		fp.Title, _ = doc["file_name"].(string)
+	case SourceArchivedTheses:
+		fp.ThesisID = idString(doc["original_thesis_id"])
+		fp.Title, _ = doc["title"].(string)
+		// File của luận văn lưu trữ có thể nằm ở bản nộp đã lưu trữ hoặc bản nộp gốc
+		owners, err = c.archivedOwners(ctx, oid, owners)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("similarity check does not support %s", source)
+	}
+
+	var texts []string
+	for _, owner := range owners {
+		files, err := c.files.List(ctx, owner.EntityType, owner.EntityID)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			text, err := c.readText(ctx, file)
+			if errors.Is(err, ErrUnsupported) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", file.FileName, err)
+			}
+			texts = append(texts, text)
+			fp.Files = append(fp.Files, file.ID.Hex())
+		}
+	}
+	fp.Text = truncate(strings.Join(texts, "\n"))
+
+	words := Words(fp.Text)
+	fp.Words = len(words)
+	signature := Signature(Shingles(words))
+	fp.Signature = make([]int64, len(signature))
+	for i, v := range signature {
+		fp.Signature[i] = int64(v)
+	}
+	fp.Bands = []string{}
+	if fp.Words >= ShingleSize {
+		fp.Bands = BandKeys(signature)
+	}
+
+	result := c.db.Collection(FingerprintsCollection).FindOneAndReplace(ctx,
+		bson.M{"source": source, "source_id": id}, fp,
+		options.FindOneAndReplace().SetUpsert(true).SetReturnDocument(options.After).SetProjection(bson.M{"_id": 1}))
+	var saved struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := result.Decode(&saved); err != nil {
+		return nil, fmt.Errorf("failed to save fingerprint: %w", err)
+	}
+	fp.ID = saved.ID
+	return fp, nil
+}
+
+func (c *Checker) archivedOwners(ctx context.Context, archivedID primitive.ObjectID, owners []storage.FileInfo) ([]storage.FileInfo, error) {
+	cursor, err := c.db.Collection("archived_submissions").Find(ctx, bson.M{"archived_thesis_id": archivedID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archived submissions: %w", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to read archived submissions: %w", err)
+	}
+	for _, doc := range docs {
+		owners = append(owners, storage.FileInfo{EntityType: "archived_submissions", EntityID: idString(doc["_id"])})
+		if original := idString(doc["original_submission_id"]); original != "" {
+			owners = append(owners, storage.FileInfo{EntityType: SourceSubmissions, EntityID: original})
+		}
+	}
+	return owners, nil
+}
+
+func (c *Checker) readText(ctx context.Context, file storage.FileInfo) (string, error) {
+	if file.Size > maxFileBytes || !Supported(file.MimeType, file.FileName) {
+		return "", ErrUnsupported
+	}
+	_, rc, err := c.files.Open(ctx, file.ID)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxFileBytes))
+	if err != nil {
+		return "", err
+	}
+	return Extract(file.MimeType, file.FileName, data)
+}
+
+// IndexArchive fingerprints the archived theses that have none yet
+func (c *Checker) IndexArchive(ctx context.Context) (int, error) {
+	done, err := c.db.Collection(FingerprintsCollection).Distinct(ctx, "source_id", bson.M{"source": SourceArchivedTheses})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load fingerprints: %w", err)
+	}
+	skip := make(map[string]bool, len(done))
+	for _, id := range done {
+		skip[idString(id)] = true
+	}
+
+	cursor, err := c.db.Collection(SourceArchivedTheses).Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load archived theses: %w", err)
+	}
+	defer cursor.Close(ctx)
+	count := 0
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return count, err
+		}
+		if skip[doc.ID.Hex()] {
+			continue
+		}
+		if _, err := c.Fingerprint(ctx, SourceArchivedTheses, doc.ID.Hex()); err != nil {
+			return count, fmt.Errorf("archived thesis %s: %w", doc.ID.Hex(), err)
+		}
+		count++
+	}
+	return count, cursor.Err()
+}
+
+func round(v float64) float64 {
+	return float64(int64(v*10000+0.5)) / 10000
+}
+
+func idString(v interface{}) string {
+	switch id := v.(type) {
+	case primitive.ObjectID:
+		return id.Hex()
+	case string:
+		return id
+	}
+	return ""
+}
@@ -0,0 +1,117 @@
+package similarity
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+)
+
+const (
+	// ShingleSize is the number of words per shingle
+	ShingleSize = 5
+	// NumHashes is the length of a MinHash signature
+	NumHashes = 128
+	// Bands of the LSH index; two documents are compared when one band of
+	// their signatures is equal, likely from a Jaccard similarity of about
+	// 0.15 so that partly copied documents are still found
+	Bands       = 64
+	rowsPerBand = NumHashes / Bands
+)
+
+// Hệ số cố định để chữ ký lưu trong DB so sánh được giữa các lần chạy
+var hashA, hashB = func() ([NumHashes]uint64, [NumHashes]uint64) {
+	var a, b [NumHashes]uint64
+	r := rand.New(rand.NewSource(20240601))
+	for i := range a {
+		a[i] = r.Uint64() | 1
+		b[i] = r.Uint64()
+	}
+	return a, b
+}()
+
+// Shingles hashes every run of ShingleSize consecutive words, by position
+func Shingles(words []string) []uint64 {
+	if len(words) < ShingleSize {
+		return nil
+	}
+	shingles := make([]uint64, len(words)-ShingleSize+1)
+	for i := range shingles {
+		h := fnv.New64a()
+		for _, word := range words[i : i+ShingleSize] {
+			h.Write([]byte(word))
+			h.Write([]byte{0})
+		}
+		shingles[i] = h.Sum64()
+	}
+	return shingles
+}
+
+// Signature is the MinHash signature of a set of shingles
+func Signature(shingles []uint64) []uint32 {
+	signature := make([]uint32, NumHashes)
+	for i := range signature {
+		signature[i] = math.MaxUint32
+	}
+	for _, shingle := range shingles {
+		for i := range signature {
+			// multiply-shift hashing
+			if v := uint32((hashA[i]*shingle + hashB[i]) >> 32); v < signature[i] {
+				signature[i] = v
+			}
+		}
+	}
+	return signature
+}
+
+// Estimate is the Jaccard similarity estimated from two signatures
+func Estimate(a, b []uint32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(a))
+}
+
+// BandKeys are the LSH keys of a signature, indexed to find candidates
+func BandKeys(signature []uint32) []string {
+	keys := make([]string, 0, Bands)
+	for band := 0; band < Bands && (band+1)*rowsPerBand <= len(signature); band++ {
+		h := fnv.New32a()
+		for _, v := range signature[band*rowsPerBand : (band+1)*rowsPerBand] {
+			h.Write([]byte{byte(v), byte(v >> 8), byte(v >> 16), byte(v >> 24)})
+		}
+		keys = append(keys, fmt.Sprintf("%02d%08x", band, h.Sum32()))
+	}
+	return keys
+}
+
+// Overlap returns the exact Jaccard similarity of two shingle lists and the
+// share of a's shingles found in b
+func Overlap(a, b []uint64) (jaccard, containment float64) {
+	setA := make(map[uint64]struct{}, len(a))
+	for _, s := range a {
+		setA[s] = struct{}{}
+	}
+	setB := make(map[uint64]struct{}, len(b))
+	for _, s := range b {
+		setB[s] = struct{}{}
+	}
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0, 0
+	}
+	common := 0
+	for s := range setA {
+		if _, ok := setB[s]; ok {
+			common++
+		}
+	}
+	jaccard = float64(common) / float64(len(setA)+len(setB)-common)
+	containment = float64(common) / float64(len(setA))
+	return jaccard, containment
+}
@@ -0,0 +1,86 @@
+package similarity
+
+import (
+	"sort"
+	"strings"
+)
+
+// MinPassageWords is the shortest run of shared words reported as a passage
+const MinPassageWords = 12
+
+// maxPassageText caps the text returned per passage
+const maxPassageText = 600
+
+// Passage is a run of words shared by the checked document and a match,
+// positions are word indexes
+type Passage struct {
+	Start      int    `bson:"start" json:"start"`
+	End        int    `bson:"end" json:"end"`
+	MatchStart int    `bson:"match_start" json:"match_start"`
+	MatchEnd   int    `bson:"match_end" json:"match_end"`
+	Text       string `bson:"text" json:"text"`
+}
+
+func (p Passage) Words() int {
+	return p.End - p.Start
+}
+
+// Passages finds the longest runs of at least minWords words of words that
+// also appear in other, longest first
+func Passages(words, other []string, minWords, limit int) []Passage {
+	if minWords < ShingleSize {
+		minWords = ShingleSize
+	}
+	positions := map[uint64][]int{}
+	for j, s := range Shingles(other) {
+		// Giới hạn vị trí cho mỗi shingle để văn bản lặp nhiều không làm chậm
+		if len(positions[s]) < 8 {
+			positions[s] = append(positions[s], j)
+		}
+	}
+
+	var passages []Passage
+	shingles := Shingles(words)
+	for i := 0; i < len(shingles); {
+		best, bestAt := 0, -1
+		for _, j := range positions[shingles[i]] {
+			n := 0
+			for i+n < len(words) && j+n < len(other) && words[i+n] == other[j+n] {
+				n++
+			}
+			if n > best {
+				best, bestAt = n, j
+			}
+		}
+		if best < minWords {
+			i++
+			continue
+		}
+		passages = append(passages, Passage{
+			Start:      i,
+			End:        i + best,
+			MatchStart: bestAt,
+			MatchEnd:   bestAt + best,
+			Text:       snippet(words[i : i+best]),
+		})
+		i += best
+	}
+
+	sort.SliceStable(passages, func(a, b int) bool { return passages[a].Words() > passages[b].Words() })
+	if limit > 0 && len(passages) > limit {
+		passages = passages[:limit]
+	}
+	return passages
+}
+
+func snippet(words []string) string {
+	text := strings.Join(words, " ")
+	if len(text) <= maxPassageText {
+		return text
+	}
+	cut := strings.LastIndex(text[:maxPassageText], " ")
+	if cut <= 0 {
+		cut = maxPassageText
+	}
+	return text[:cut] + " …"
+}
@@ -0,0 +1,28 @@
+package similarity
+
+import (
+	"context"
+
+	"thaily/services/_common/hooks"
+	"thaily/services/_common/tasks"
+)
+
+// Bài nộp mới được kiểm tra trùng lặp ở nền; luận văn lưu trữ mới được lấy
+// dấu vân tay để các bài nộp sau so sánh được
+func init() {
+	hooks.Register(hooks.Hook{Name: "submissions.similarity", EntityType: SourceSubmissions, Event: hooks.AfterCreate, Fn: queueCheck})
+	hooks.Register(hooks.Hook{Name: "archived_theses.similarity", EntityType: SourceArchivedTheses, Event: hooks.AfterCreate, Fn: queueCheck})
+}
+
+func queueCheck(ctx context.Context, op *hooks.Operation) error {
+	if op.Tasks == nil {
+		return nil
+	}
+	if op.EntityType == SourceSubmissions {
+		if err := MarkPending(ctx, op.DB, op.ID, idString(op.Entity["thesis_id"])); err != nil {
+			return err
+		}
+	}
+	op.EnqueueAfterCommit(tasks.TypeSimilarityCheck, tasks.SimilarityPayload{EntityType: op.EntityType, ID: op.ID})
+	return nil
+}
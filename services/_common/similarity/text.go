@@ -0,0 +1,147 @@
+package similarity
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+)
+
+var ErrUnsupported = errors.New("unsupported document type")
+
+// MaxTextBytes caps the text kept per document
+const MaxTextBytes = 2 << 20
+
+const docxMimeType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// Supported reports whether Extract can read a document of this type
+func Supported(mimeType, fileName string) bool {
+	return documentKind(mimeType, fileName) != ""
+}
+
+func documentKind(mimeType, fileName string) string {
+	ext := strings.ToLower(path.Ext(fileName))
+	switch {
+	case mimeType == "application/pdf" || ext == ".pdf":
+		return "pdf"
+	case mimeType == docxMimeType || ext == ".docx":
+		return "docx"
+	case strings.HasPrefix(mimeType, "text/") || ext == ".txt" || ext == ".md" || ext == ".tex":
+		return "text"
+	}
+	return ""
+}
+
+// Extract returns the plain text of a PDF, DOCX or text document
+func Extract(mimeType, fileName string, data []byte) (string, error) {
+	switch documentKind(mimeType, fileName) {
+	case "pdf":
+		return extractPDF(data)
+	case "docx":
+		return extractDOCX(data)
+	case "text":
+		if !utf8.Valid(data) {
+			return "", fmt.Errorf("%s is not UTF-8 text", fileName)
+		}
+		return truncate(string(data)), nil
+	}
+	return "", ErrUnsupported
+}
+
+func extractPDF(data []byte) (text string, err error) {
+	// Thư viện pdf panic với một số file hỏng
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed pdf: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to read pdf: %w", err)
+	}
+	plain, err := reader.GetPlainText()
+	if err != nil {
+		return "", fmt.Errorf("failed to read pdf text: %w", err)
+	}
+	out, err := io.ReadAll(io.LimitReader(plain, MaxTextBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to read pdf text: %w", err)
+	}
+	return string(out), nil
+}
+
+func extractDOCX(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to read docx: %w", err)
+	}
+	for _, file := range archive.File {
+		if file.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return "", fmt.Errorf("failed to read docx: %w", err)
+		}
+		defer rc.Close()
+
+		var b strings.Builder
+		decoder := xml.NewDecoder(io.LimitReader(rc, 8*MaxTextBytes))
+		inText := false
+		for b.Len() < MaxTextBytes {
+			token, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("failed to read docx: %w", err)
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				inText = t.Name.Local == "t"
+				if t.Name.Local == "tab" {
+					b.WriteByte(' ')
+				}
+			case xml.EndElement:
+				inText = false
+				if t.Name.Local == "p" {
+					b.WriteByte('\n')
+				}
+			case xml.CharData:
+				if inText {
+					b.Write(t)
+				}
+			}
+		}
+		return truncate(b.String()), nil
+	}
+	return "", fmt.Errorf("docx has no word/document.xml")
+}
+
+func truncate(text string) string {
+	if len(text) <= MaxTextBytes {
+		return text
+	}
+	text = text[:MaxTextBytes]
+	for !utf8.ValidString(text) {
+		text = text[:len(text)-1]
+	}
+	return text
+}
+
+// Words splits text into lowercase words, dropping punctuation so that
+// formatting changes do not hide copied passages
+func Words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore stores the raw bytes of attachments. Metadata always stays in the
+// attachments collection; Key is the attachment id.
+type BlobStore interface {
+	// Name identifies the backend in attachment metadata
+	Name() string
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+type BlobConfig struct {
+	// gridfs, fs or s3
+	Backend string
+	Dir     string
+	S3      S3Config
+}
+
+// NewBlobStore builds the configured backend
+func NewBlobStore(db *mongo.Database, config BlobConfig) (BlobStore, error) {
+	switch config.Backend {
+	case "", GridFSBackend:
+		return NewGridFSBlobStore(db)
+	case FilesystemBackend:
+		return NewFilesystemBlobStore(config.Dir)
+	case S3Backend:
+		return NewS3BlobStore(config.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob backend %q", config.Backend)
+	}
+}
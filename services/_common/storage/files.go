@@ -0,0 +1,271 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Metadata của file đính kèm, _id cũng là key của blob trong BlobStore
+const AttachmentsCollection = "attachments"
+
+var (
+	ErrNotFound         = errors.New("file not found")
+	ErrTooLarge         = errors.New("file exceeds the maximum size")
+	ErrTypeNotAllowed   = errors.New("file type is not allowed")
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// FileInfo is the metadata stored in the attachments collection
+type FileInfo struct {
+	ID         primitive.ObjectID `bson:"_id"`
+	FileName   string             `bson:"file_name"`
+	MimeType   string             `bson:"mime_type"`
+	Size       int64              `bson:"size"`
+	Checksum   string             `bson:"checksum"`
+	OwnerID    string             `bson:"owner_id"`
+	EntityType string             `bson:"entity_type"`
+	EntityID   string             `bson:"entity_id"`
+	CreatedAt  time.Time          `bson:"createdAt"`
+	// Backend giữ nội dung file, rỗng với dữ liệu cũ (GridFS)
+	Storage string `bson:"storage,omitempty"`
+	// Kết quả lần kiểm tra checksum gần nhất, xem Verifier
+	Integrity  string     `bson:"integrity,omitempty"`
+	VerifiedAt *time.Time `bson:"verifiedAt,omitempty"`
+}
+
+func (info *FileInfo) backend() string {
+	if info.Storage == "" {
+		return GridFSBackend
+	}
+	return info.Storage
+}
+
+type FileStore struct {
+	files *mongo.Collection
+	// New uploads go to blobs; older files are read from the backend recorded
+	// in their metadata
+	blobs    BlobStore
+	backends map[string]BlobStore
+}
+
+func NewFileStore(ctx context.Context, db *mongo.Database, blobs BlobStore) (*FileStore, error) {
+	files := db.Collection(AttachmentsCollection)
+	_, err := files.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "entity_type", Value: 1}, {Key: "entity_id", Value: 1}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachments index: %w", err)
+	}
+
+	backends := map[string]BlobStore{blobs.Name(): blobs}
+	if _, ok := backends[GridFSBackend]; !ok {
+		gridFS, err := NewGridFSBlobStore(db)
+		if err != nil {
+			return nil, err
+		}
+		backends[GridFSBackend] = gridFS
+	}
+
+	return &FileStore{files: files, blobs: blobs, backends: backends}, nil
+}
+
+func (f *FileStore) backendFor(info *FileInfo) (BlobStore, error) {
+	blobs, ok := f.backends[info.backend()]
+	if !ok {
+		return nil, fmt.Errorf("file %s is stored in the %q backend which is not configured", info.ID.Hex(), info.backend())
+	}
+	return blobs, nil
+}
+
+// Upload streams r into the blob store while enforcing the limits of the owning
+// entity type. When expectedChecksum (sha256 hex) is given the upload is
+// rejected if the received bytes do not match it.
+func (f *FileStore) Upload(ctx context.Context, info FileInfo, r io.Reader, expectedChecksum string) (*FileInfo, error) {
+	limits := LimitsFor(info.EntityType)
+	if !limits.Allows(info.MimeType) {
+		return nil, fmt.Errorf("%w: %s", ErrTypeNotAllowed, info.MimeType)
+	}
+
+	// Kiểm tra nội dung thực tế thay vì chỉ tin vào mime_type client gửi
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if sniffed := sniffType(head); sniffed != "" && !limits.Allows(sniffed) {
+		return nil, fmt.Errorf("%w: content looks like %s", ErrTypeNotAllowed, sniffed)
+	}
+
+	info.ID = primitive.NewObjectID()
+	info.CreatedAt = time.Now()
+	info.Storage = f.blobs.Name()
+	key := info.ID.Hex()
+
+	counter := &countingReader{r: br, hash: sha256.New(), max: limits.MaxSize}
+	if err := f.blobs.Put(ctx, key, counter); err != nil {
+		if counter.tooLarge {
+			return nil, fmt.Errorf("%w of %d bytes", ErrTooLarge, limits.MaxSize)
+		}
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+
+	info.Size = counter.size
+	info.Checksum = hex.EncodeToString(counter.hash.Sum(nil))
+	if expectedChecksum != "" && !strings.EqualFold(expectedChecksum, info.Checksum) {
+		f.blobs.Delete(ctx, key)
+		return nil, ErrChecksumMismatch
+	}
+
+	if _, err := f.files.InsertOne(ctx, info); err != nil {
+		f.blobs.Delete(ctx, key)
+		return nil, fmt.Errorf("failed to save file metadata: %w", err)
+	}
+	return &info, nil
+}
+
+// countingReader hashes and counts the bytes handed to the blob store, failing
+// the read as soon as the size limit is exceeded so the backend aborts the write
+type countingReader struct {
+	r        io.Reader
+	hash     hash.Hash
+	size     int64
+	max      int64
+	tooLarge bool
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.size += int64(n)
+	c.hash.Write(p[:n])
+	if c.size > c.max {
+		c.tooLarge = true
+		return 0, ErrTooLarge
+	}
+	return n, err
+}
+
+func (f *FileStore) Get(ctx context.Context, id primitive.ObjectID) (*FileInfo, error) {
+	var info FileInfo
+	err := f.files.FindOne(ctx, bson.M{"_id": id}).Decode(&info)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// Open returns the metadata and a reader over the file content
+func (f *FileStore) Open(ctx context.Context, id primitive.ObjectID) (*FileInfo, io.ReadCloser, error) {
+	info, err := f.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blobs, err := f.backendFor(info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader, err := blobs.Get(ctx, id.Hex())
+	if err == ErrBlobNotFound {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return info, reader, nil
+}
+
+// List returns the files attached to one entity
+func (f *FileStore) List(ctx context.Context, entityType, entityID string) ([]FileInfo, error) {
+	cursor, err := f.files.Find(ctx,
+		bson.M{"entity_type": entityType, "entity_id": entityID},
+		options.Find().SetSort(bson.M{"createdAt": -1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	files := []FileInfo{}
+	if err := cursor.All(ctx, &files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (f *FileStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	var info FileInfo
+	err := f.files.FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&info)
+	if err == mongo.ErrNoDocuments {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	blobs, err := f.backendFor(&info)
+	if err != nil {
+		return err
+	}
+	if err := blobs.Delete(ctx, id.Hex()); err != nil && err != ErrBlobNotFound {
+		return err
+	}
+	return nil
+}
+
+// DeleteByEntity removes every file attached to the given entities, used when
+// the owning entities are deleted
+func (f *FileStore) DeleteByEntity(ctx context.Context, entityType string, entityIDs []string) (int, error) {
+	cursor, err := f.files.Find(ctx,
+		bson.M{"entity_type": entityType, "entity_id": bson.M{"$in": entityIDs}},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var files []FileInfo
+	if err := cursor.All(ctx, &files); err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, file := range files {
+		if err := f.Delete(ctx, file.ID); err != nil && err != ErrNotFound {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// sniffType returns the detected MIME type, or "" when detection is inconclusive
+func sniffType(head []byte) string {
+	if len(head) == 0 {
+		return ""
+	}
+	sniffed, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	switch sniffed {
+	case "application/octet-stream", "text/plain":
+		return ""
+	}
+	return sniffed
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	GridFSBackend = "gridfs"
+	bucketName    = "attachments"
+)
+
+// GridFSBlobStore keeps blobs inside MongoDB, keyed by the attachment ObjectID
+type GridFSBlobStore struct {
+	bucket *gridfs.Bucket
+}
+
+func NewGridFSBlobStore(db *mongo.Database) (*GridFSBlobStore, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(bucketName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GridFS bucket: %w", err)
+	}
+	return &GridFSBlobStore{bucket: bucket}, nil
+}
+
+func (g *GridFSBlobStore) Name() string {
+	return GridFSBackend
+}
+
+func (g *GridFSBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	id, err := primitive.ObjectIDFromHex(key)
+	if err != nil {
+		return fmt.Errorf("invalid blob key: %w", err)
+	}
+
+	stream, err := g.bucket.OpenUploadStreamWithID(id, key)
+	if err != nil {
+		return fmt.Errorf("failed to open upload stream: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		stream.SetWriteDeadline(deadline)
+	}
+
+	if _, err := io.Copy(stream, r); err != nil {
+		stream.Abort()
+		return err
+	}
+	return stream.Close()
+}
+
+func (g *GridFSBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	id, err := primitive.ObjectIDFromHex(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blob key: %w", err)
+	}
+
+	stream, err := g.bucket.OpenDownloadStream(id)
+	if err == gridfs.ErrFileNotFound {
+		return nil, ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (g *GridFSBlobStore) Delete(ctx context.Context, key string) error {
+	id, err := primitive.ObjectIDFromHex(key)
+	if err != nil {
+		return fmt.Errorf("invalid blob key: %w", err)
+	}
+
+	err = g.bucket.DeleteContext(ctx, id)
+	if err == gridfs.ErrFileNotFound {
+		return ErrBlobNotFound
+	}
+	return err
+}
+
+func (g *GridFSBlobStore) Exists(ctx context.Context, key string) (bool, error) {
+	id, err := primitive.ObjectIDFromHex(key)
+	if err != nil {
+		return false, fmt.Errorf("invalid blob key: %w", err)
+	}
+
+	count, err := g.bucket.GetFilesCollection().CountDocuments(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DownloadPath is the prefix served by DownloadHandler: /files/<id>?token=...
+const DownloadPath = "/files/"
+
+// DownloadHandler serves file content to holders of a valid download token,
+// so browsers can fetch attachments without going through gRPC
+func DownloadHandler(store *FileStore, signer *TokenSigner) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, DownloadPath)
+		fileID, err := signer.Verify(r.URL.Query().Get("token"))
+		if err != nil || fileID != id {
+			http.Error(w, ErrInvalidToken.Error(), http.StatusForbidden)
+			return
+		}
+
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			http.Error(w, "invalid file id", http.StatusBadRequest)
+			return
+		}
+
+		info, reader, err := store.Open(r.Context(), objectID)
+		if err == ErrNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer reader.Close()
+
+		w.Header().Set("Content-Type", info.MimeType)
+		w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": info.FileName}))
+		w.Header().Set("ETag", fmt.Sprintf("%q", info.Checksum))
+		w.Header().Set("Cache-Control", "private, no-store")
+		if r.Method == http.MethodHead {
+			return
+		}
+
+		if _, err := io.Copy(w, reader); err != nil {
+			log.Printf("download of %s interrupted: %v", id, err)
+		}
+	})
+}
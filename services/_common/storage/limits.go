@@ -0,0 +1,62 @@
+package storage
+
+import "strings"
+
+const mb = 1 << 20
+
+type Limits struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+var documentTypes = []string{
+	"application/pdf",
+	"application/msword",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+}
+
+var archiveTypes = []string{
+	"application/zip",
+	"application/x-zip-compressed",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-tar",
+	"application/x-7z-compressed",
+	"application/vnd.rar",
+	"application/x-rar-compressed",
+}
+
+// DefaultLimits applies to entity types without their own entry in EntityLimits
+var DefaultLimits = Limits{
+	MaxSize:      20 * mb,
+	AllowedTypes: append(append([]string{"image/png", "image/jpeg"}, documentTypes...), archiveTypes...),
+}
+
+// Giới hạn riêng theo entity type: bài nộp gồm file PDF và mã nguồn nén
+var EntityLimits = map[string]Limits{
+	"submissions": {
+		MaxSize:      200 * mb,
+		AllowedTypes: append(append([]string{}, documentTypes...), archiveTypes...),
+	},
+	"users": {
+		MaxSize:      2 * mb,
+		AllowedTypes: []string{"image/png", "image/jpeg", "image/webp"},
+	},
+}
+
+func LimitsFor(entityType string) Limits {
+	if limits, ok := EntityLimits[entityType]; ok {
+		return limits
+	}
+	return DefaultLimits
+}
+
+func (l Limits) Allows(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	for _, allowed := range l.AllowedTypes {
+		if allowed == mimeType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is a path-style S3 server keeping objects in memory. It accepts only
+// requests signed with the secret of verifier.
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	verifier *S3BlobStore
+}
+
+func newFakeS3(t *testing.T, config S3Config) (*fakeS3, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewUnstartedServer(nil)
+	config.Endpoint = "http://" + server.Listener.Addr().String()
+	verifier, err := NewS3BlobStore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeS3{objects: map[string][]byte{}, verifier: verifier}
+	server.Config.Handler = f
+	server.Start()
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if msg := f.checkSignature(r, body); msg != "" {
+		http.Error(w, msg, http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	object, exists := f.objects[r.URL.Path]
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.Path] = body
+	case http.MethodGet, http.MethodHead:
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.Write(object)
+		}
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+	}
+}
+
+// checkSignature signs the received request again with the server secret
+// and compares the Authorization headers
+func (f *fakeS3) checkSignature(r *http.Request, body []byte) string {
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	sum := sha256.Sum256(body)
+	if payloadHash != hex.EncodeToString(sum[:]) {
+		return "payload hash does not match the body"
+	}
+	signedAt, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return "missing x-amz-date"
+	}
+	expected, err := http.NewRequest(r.Method, "http://"+r.Host+r.URL.RequestURI(), nil)
+	if err != nil {
+		return err.Error()
+	}
+	f.verifier.sign(expected, payloadHash, signedAt)
+	if r.Header.Get("Authorization") != expected.Header.Get("Authorization") {
+		return "signature does not match"
+	}
+	return ""
+}
+
+func testS3Config() S3Config {
+	return S3Config{
+		Region:    "ap-southeast-1",
+		Bucket:    "theses",
+		AccessKey: "access",
+		SecretKey: "secret",
+		PathStyle: true,
+		Prefix:    "files/",
+	}
+}
+
+func TestS3BlobStore(t *testing.T) {
+	fake, server := newFakeS3(t, testS3Config())
+	config := testS3Config()
+	config.Endpoint = server.URL
+	store, err := NewS3BlobStore(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	content := []byte("thesis final version")
+
+	if err := store.Put(ctx, "2024/report v2.pdf", bytes.NewReader(content)); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, ok := fake.objects["/theses/files/2024/report v2.pdf"]; !ok {
+		t.Fatalf("object stored under %v, want the bucket path with the prefix", fake.objects)
+	}
+
+	r, err := store.Get(ctx, "2024/report v2.pdf")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	got, _ := io.ReadAll(r)
+	r.Close()
+	if !bytes.Equal(got, content) {
+		t.Errorf("Get() = %q, want %q", got, content)
+	}
+
+	if exists, err := store.Exists(ctx, "2024/report v2.pdf"); err != nil || !exists {
+		t.Errorf("Exists() = %v, %v, want true", exists, err)
+	}
+	if err := store.Delete(ctx, "2024/report v2.pdf"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if exists, err := store.Exists(ctx, "2024/report v2.pdf"); err != nil || exists {
+		t.Errorf("Exists() after Delete = %v, %v, want false", exists, err)
+	}
+}
+
+func TestS3BlobStoreErrors(t *testing.T) {
+	_, server := newFakeS3(t, testS3Config())
+
+	tests := []struct {
+		name      string
+		secretKey string
+		call      func(ctx context.Context, store *S3BlobStore) error
+		wantErr   error
+		// wantText is checked when wantErr is nil
+		wantText string
+	}{
+		{
+			name: "get missing object",
+			call: func(ctx context.Context, store *S3BlobStore) error {
+				_, err := store.Get(ctx, "missing.pdf")
+				return err
+			},
+			wantErr: ErrBlobNotFound,
+		},
+		{
+			name: "delete missing object",
+			call: func(ctx context.Context, store *S3BlobStore) error {
+				return store.Delete(ctx, "missing.pdf")
+			},
+			wantErr: ErrBlobNotFound,
+		},
+		{
+			name:      "wrong secret key",
+			secretKey: "another-secret",
+			call: func(ctx context.Context, store *S3BlobStore) error {
+				return store.Put(ctx, "report.pdf", strings.NewReader("content"))
+			},
+			wantText: "403",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := testS3Config()
+			config.Endpoint = server.URL
+			if tt.secretKey != "" {
+				config.SecretKey = tt.secretKey
+			}
+			store, err := NewS3BlobStore(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = tt.call(context.Background(), store)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (err == nil || !strings.Contains(err.Error(), tt.wantText)) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired download token")
+
+// TokenSigner issues short-lived download links, signed with a secret of
+// their own (DOWNLOAD_SECRET) rather than the JWT secret
+type TokenSigner struct {
+	secret []byte
+}
+
+func NewTokenSigner(secret string) *TokenSigner {
+	return &TokenSigner{secret: []byte(secret)}
+}
+
+// Sign returns a token of the form <fileID>.<expiresUnix>.<signature>
+func (t *TokenSigner) Sign(fileID string, ttl time.Duration) (string, time.Time) {
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+	payload := fileID + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + t.signature(payload), expiresAt
+}
+
+// Verify checks the signature and expiry and returns the file id
+func (t *TokenSigner) Verify(token string) (string, error) {
+	idx := strings.LastIndexByte(token, '.')
+	if idx < 0 {
+		return "", ErrInvalidToken
+	}
+	payload, signature := token[:idx], token[idx+1:]
+	if !hmac.Equal([]byte(signature), []byte(t.signature(payload))) {
+		return "", ErrInvalidToken
+	}
+
+	fileID, expires, ok := strings.Cut(payload, ".")
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return "", ErrInvalidToken
+	}
+	return fileID, nil
+}
+
+func (t *TokenSigner) signature(payload string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte("download:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenSigner(t *testing.T) {
+	signer := NewTokenSigner("download-secret")
+
+	tests := []struct {
+		name   string
+		token  func() string
+		wantID string
+	}{
+		{
+			name: "valid",
+			token: func() string {
+				token, _ := signer.Sign("file-1", time.Minute)
+				return token
+			},
+			wantID: "file-1",
+		},
+		{
+			name: "expired",
+			token: func() string {
+				token, _ := signer.Sign("file-1", -2*time.Second)
+				return token
+			},
+		},
+		{
+			name: "signed with another secret",
+			token: func() string {
+				token, _ := NewTokenSigner("jwt-secret").Sign("file-1", time.Minute)
+				return token
+			},
+		},
+		{
+			name: "file id swapped",
+			token: func() string {
+				token, _ := signer.Sign("file-1", time.Minute)
+				return strings.Replace(token, "file-1", "file-2", 1)
+			},
+		},
+		{
+			name: "expiry extended",
+			token: func() string {
+				token, expiresAt := signer.Sign("file-1", time.Minute)
+				parts := strings.Split(token, ".")
+				parts[1] = strconv.FormatInt(expiresAt.Add(time.Hour).Unix(), 10)
+				return strings.Join(parts, ".")
+			},
+		},
+		{
+			name:  "malformed",
+			token: func() string { return "file-1" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileID, err := signer.Verify(tt.token())
+			if tt.wantID == "" {
+				if err != ErrInvalidToken {
+					t.Fatalf("Verify() = %q, %v, want ErrInvalidToken", fileID, err)
+				}
+				return
+			}
+			if err != nil || fileID != tt.wantID {
+				t.Fatalf("Verify() = %q, %v, want %q", fileID, err, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestTokenSignerExpiresAt(t *testing.T) {
+	token, expiresAt := NewTokenSigner("download-secret").Sign("file-1", 15*time.Minute)
+	if until := time.Until(expiresAt); until <= 14*time.Minute || until > 15*time.Minute {
+		t.Errorf("expiresAt is %v away, want about 15m", until)
+	}
+	if !strings.HasPrefix(token, "file-1.") {
+		t.Errorf("token = %q, want the file id first", token)
+	}
+}
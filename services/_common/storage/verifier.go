@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Giá trị của FileInfo.Integrity sau khi kiểm tra
+const (
+	IntegrityOK      = "ok"
+	IntegrityMissing = "missing"
+	IntegrityCorrupt = "corrupt"
+)
+
+type VerifyReport struct {
+	Checked int
+	OK      int
+	Missing []string
+	Corrupt []string
+	Errors  []string
+}
+
+// Verify re-reads every blob, compares it with the stored checksum and records
+// the outcome on the attachment. Files verified within maxAge are skipped.
+func (f *FileStore) Verify(ctx context.Context, maxAge time.Duration) (*VerifyReport, error) {
+	filter := bson.M{}
+	if maxAge > 0 {
+		filter["$or"] = bson.A{
+			bson.M{"verifiedAt": bson.M{"$exists": false}},
+			bson.M{"verifiedAt": bson.M{"$lt": time.Now().Add(-maxAge)}},
+		}
+	}
+
+	cursor, err := f.files.Find(ctx, filter, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	report := &VerifyReport{}
+	for cursor.Next(ctx) {
+		var info FileInfo
+		if err := cursor.Decode(&info); err != nil {
+			return report, err
+		}
+		report.Checked++
+
+		integrity, err := f.verifyOne(ctx, &info)
+		if err != nil {
+			report.Errors = append(report.Errors, info.ID.Hex()+": "+err.Error())
+			continue
+		}
+
+		switch integrity {
+		case IntegrityOK:
+			report.OK++
+		case IntegrityMissing:
+			report.Missing = append(report.Missing, info.ID.Hex())
+		case IntegrityCorrupt:
+			report.Corrupt = append(report.Corrupt, info.ID.Hex())
+		}
+
+		_, err = f.files.UpdateOne(ctx, bson.M{"_id": info.ID}, bson.M{
+			"$set": bson.M{"integrity": integrity, "verifiedAt": time.Now()},
+		})
+		if err != nil {
+			return report, err
+		}
+	}
+	return report, cursor.Err()
+}
+
+func (f *FileStore) verifyOne(ctx context.Context, info *FileInfo) (string, error) {
+	blobs, err := f.backendFor(info)
+	if err != nil {
+		return "", err
+	}
+
+	reader, err := blobs.Get(ctx, info.ID.Hex())
+	if err == ErrBlobNotFound {
+		return IntegrityMissing, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, reader)
+	if err != nil {
+		return "", err
+	}
+	if size != info.Size || hex.EncodeToString(hash.Sum(nil)) != info.Checksum {
+		return IntegrityCorrupt, nil
+	}
+	return IntegrityOK, nil
+}
+
+// StartVerifier runs Verify every interval until ctx is cancelled, so each
+// file is re-checked roughly once per interval
+func (f *FileStore) StartVerifier(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				report, err := f.Verify(ctx, interval)
+				if err != nil {
+					log.Printf("file verification failed: %v", err)
+					continue
+				}
+				if len(report.Missing) > 0 || len(report.Corrupt) > 0 || len(report.Errors) > 0 {
+					log.Printf("file verification: %d checked, %d missing %v, %d corrupt %v, %d errors",
+						report.Checked, len(report.Missing), report.Missing,
+						len(report.Corrupt), report.Corrupt, len(report.Errors))
+				}
+			}
+		}
+	}()
+}
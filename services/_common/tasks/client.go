@@ -0,0 +1,52 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// Queue names, shared with the workers of the asynq service
+const (
+	QueueCritical = "critical"
+	QueueDefault  = "default"
+	QueueLow      = "low"
+)
+
+// Client enqueues background tasks into the Redis instance used by the asynq
+// service, which runs the workers
+type Client struct {
+	client *asynq.Client
+}
+
+func NewClient(redisAddr string, redisDB int) *Client {
+	return &Client{
+		client: asynq.NewClient(asynq.RedisClientOpt{Addr: redisAddr, DB: redisDB}),
+	}
+}
+
+// Enqueue serializes payload as JSON and queues it on the default queue
+func (c *Client) Enqueue(ctx context.Context, taskType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	_, err = c.client.EnqueueContext(ctx, asynq.NewTask(taskType, data),
+		asynq.Queue(QueueDefault),
+		asynq.MaxRetry(3),
+		asynq.Timeout(30*time.Minute),
+		asynq.Retention(24*time.Hour),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue %s: %w", taskType, err)
+	}
+	return nil
+}
+
+func (c *Client) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,10 @@
+package tasks
+
+// TypeSimilarityCheck fingerprints a submission or an archived thesis; for
+// submissions the similarity report is computed too
+const TypeSimilarityCheck = "similarity:check"
+
+type SimilarityPayload struct {
+	EntityType string `json:"entity_type"`
+	ID         string `json:"id"`
+}
@@ -0,0 +1,42 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+
+	"thaily/services/_common/authn"
+)
+
+// Vai trò hệ thống được coi là giáo vụ
+var StaffRoles = []string{"staff", "admin"}
+
+// Actor is the caller of a transition
+type Actor struct {
+	UserID string
+	Roles  []string
+}
+
+// ActorFromContext is the caller of a request, taken from its verified access
+// token; ok is false when the call carried no user token
+func ActorFromContext(ctx context.Context) (actor Actor, ok bool) {
+	claims := authn.FromContext(ctx)
+	if claims == nil || claims.UserID == "" {
+		return Actor{}, false
+	}
+	return Actor{UserID: claims.UserID, Roles: claims.RoleNames()}, true
+}
+
+func (a Actor) HasRole(roles ...string) bool {
+	for _, have := range a.Roles {
+		for _, want := range roles {
+			if strings.EqualFold(have, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (a Actor) IsStaff() bool {
+	return a.HasRole(StaffRoles...)
+}
@@ -0,0 +1,166 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "thaily/proto/common"
+	"thaily/services/_common/audit"
+	"thaily/services/_common/hooks"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	StatusesCollection = "thesis_statuses"
+
+	// action của event_logs cho mỗi lần chuyển trạng thái
+	TransitionAction = "transition_thesis"
+)
+
+type Engine struct {
+	db *mongo.Database
+}
+
+func NewEngine(db *mongo.Database) *Engine {
+	return &Engine{db: db}
+}
+
+// StateOf returns the state of a thesis_statuses id, empty for statuses that
+// are not part of the workflow
+func (e *Engine) StateOf(ctx context.Context, statusID interface{}) (State, error) {
+	filter := bson.M{"_id": statusID}
+	if hex, ok := statusID.(string); ok {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return "", nil
+		}
+		filter["_id"] = id
+	}
+
+	var status struct {
+		Key string `bson:"key"`
+	}
+	err := e.db.Collection(StatusesCollection).FindOne(ctx, filter).Decode(&status)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read thesis status: %w", err)
+	}
+	return State(status.Key), nil
+}
+
+// StatusID returns the id of the thesis_statuses document of state
+func (e *Engine) StatusID(ctx context.Context, state State) (primitive.ObjectID, error) {
+	var status struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	err := e.db.Collection(StatusesCollection).FindOne(ctx, bson.M{"key": string(state)}).Decode(&status)
+	if err == mongo.ErrNoDocuments {
+		return primitive.NilObjectID, fmt.Errorf("thesis status %q does not exist, run the migrations", state)
+	}
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("failed to read thesis status: %w", err)
+	}
+	return status.ID, nil
+}
+
+// Authorize checks that the transition is declared, that actor holds one of
+// its roles and that its preconditions hold. Refusals are hooks.Rejection.
+func (e *Engine) Authorize(ctx context.Context, thesis bson.M, from, to State, actor Actor) (Transition, error) {
+	transition, ok := FindTransition(from, to)
+	if !ok {
+		allowed := make([]string, 0)
+		for _, state := range AllowedFrom(from) {
+			allowed = append(allowed, string(state))
+		}
+		message := fmt.Sprintf("cannot move a thesis from %q to %q", from, to)
+		if len(allowed) > 0 {
+			message += fmt.Sprintf(", allowed: %s", strings.Join(allowed, ", "))
+		}
+		return Transition{}, hooks.Reject(message, &pb.ErrorDetail{
+			Code:    "INVALID_TRANSITION",
+			Field:   "to_state",
+			Value:   string(to),
+			Message: message,
+		})
+	}
+
+	allowed := false
+	for _, role := range transition.Roles {
+		if e.actorHasRole(thesis, actor, role) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		roles := make([]string, len(transition.Roles))
+		for i, role := range transition.Roles {
+			roles[i] = string(role)
+		}
+		message := fmt.Sprintf("only %s can move a thesis to %q", strings.Join(roles, " or "), to)
+		return Transition{}, hooks.Reject(message, &pb.ErrorDetail{
+			Code:    "FORBIDDEN",
+			Field:   "to_state",
+			Value:   string(to),
+			Message: message,
+		})
+	}
+
+	for _, precondition := range transition.Preconditions {
+		reason, err := precondition(ctx, e.db, thesis)
+		if err != nil {
+			return Transition{}, err
+		}
+		if reason != "" {
+			return Transition{}, hooks.Reject(reason, &pb.ErrorDetail{
+				Code:    "PRECONDITION_FAILED",
+				Field:   "to_state",
+				Value:   string(to),
+				Message: reason,
+			})
+		}
+	}
+	return transition, nil
+}
+
+func (e *Engine) actorHasRole(thesis bson.M, actor Actor, role Role) bool {
+	switch role {
+	case RoleStaff:
+		return actor.IsStaff()
+	case RoleStudent:
+		return actor.UserID != "" && sameID(thesis["student_id"], actor.UserID)
+	case RoleSupervisor:
+		return actor.UserID != "" && sameID(thesis["supervisor_id"], actor.UserID)
+	}
+	return false
+}
+
+// LogTransition writes the event_logs entry of a transition
+func (e *Engine) LogTransition(ctx context.Context, thesisID primitive.ObjectID, from, to State, actor Actor, reason string) error {
+	return audit.Log(ctx, e.db, audit.Entry{
+		UserID:     actor.UserID,
+		Action:     TransitionAction,
+		EntityType: "theses",
+		EntityID:   thesisID,
+		Details: bson.M{
+			"from":   string(from),
+			"to":     string(to),
+			"reason": reason,
+		},
+	})
+}
+
+func sameID(value interface{}, hex string) bool {
+	switch id := value.(type) {
+	case primitive.ObjectID:
+		return id.Hex() == hex
+	case string:
+		return id == hex
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Precondition returns a client facing reason when the thesis is not ready
+// for a transition, or an error when the check itself failed
+type Precondition func(ctx context.Context, db *mongo.Database, thesis bson.M) (string, error)
+
+// Tham chiếu có thể được lưu dạng ObjectID hoặc chuỗi hex
+func idValues(id primitive.ObjectID) bson.A {
+	return bson.A{id, id.Hex()}
+}
+
+func exists(ctx context.Context, db *mongo.Database, collection string, filter bson.M) (bool, error) {
+	err := db.Collection(collection).FindOne(ctx, filter).Err()
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s: %w", collection, err)
+	}
+	return true, nil
+}
+
+func hasSupervisor(ctx context.Context, db *mongo.Database, thesis bson.M) (string, error) {
+	if thesis["supervisor_id"] == nil || thesis["supervisor_id"] == "" {
+		return "the thesis has no supervisor", nil
+	}
+	return "", nil
+}
+
+func hasFinalSubmission(ctx context.Context, db *mongo.Database, thesis bson.M) (string, error) {
+	id, _ := thesis["_id"].(primitive.ObjectID)
+	ok, err := exists(ctx, db, "submissions", bson.M{"thesis_id": bson.M{"$in": idValues(id)}, "type": "final"})
+	if err != nil || ok {
+		return "", err
+	}
+	return "a final submission is required", nil
+}
+
+func hasDefenseSchedule(ctx context.Context, db *mongo.Database, thesis bson.M) (string, error) {
+	id, _ := thesis["_id"].(primitive.ObjectID)
+	ok, err := exists(ctx, db, "defense_schedules", bson.M{
+		"thesis_id": bson.M{"$in": idValues(id)},
+		"status":    bson.M{"$ne": "cancelled"},
+	})
+	if err != nil || ok {
+		return "", err
+	}
+	return "a defense schedule is required", nil
+}
+
+func hasDefenseScores(ctx context.Context, db *mongo.Database, thesis bson.M) (string, error) {
+	id, _ := thesis["_id"].(primitive.ObjectID)
+	cursor, err := db.Collection("defense_schedules").Find(ctx, bson.M{"thesis_id": bson.M{"$in": idValues(id)}})
+	if err != nil {
+		return "", fmt.Errorf("failed to check defense_schedules: %w", err)
+	}
+	var schedules []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &schedules); err != nil {
+		return "", fmt.Errorf("failed to check defense_schedules: %w", err)
+	}
+
+	scheduleIDs := bson.A{}
+	for _, schedule := range schedules {
+		scheduleIDs = append(scheduleIDs, idValues(schedule.ID)...)
+	}
+	if len(scheduleIDs) > 0 {
+		ok, err := exists(ctx, db, "defense_scores", bson.M{"defense_schedule_id": bson.M{"$in": scheduleIDs}})
+		if err != nil || ok {
+			return "", err
+		}
+	}
+	return "defense scores are required", nil
+}
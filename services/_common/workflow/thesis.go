@@ -0,0 +1,98 @@
+package workflow
+
+// State is the key of a thesis_statuses document
+type State string
+
+const (
+	StateProposed            State = "proposed"
+	StateApproved            State = "approved"
+	StateInProgress          State = "in_progress"
+	StateSubmitted           State = "submitted"
+	StateUnderReview         State = "under_review"
+	StateScheduledForDefense State = "scheduled_for_defense"
+	StateDefended            State = "defended"
+	StateArchived            State = "archived"
+)
+
+// InitialState is the state of every new thesis
+const InitialState = StateProposed
+
+// Role allowed to perform a transition. Supervisor and student are relative to
+// the thesis, staff comes from the roles of the caller.
+type Role string
+
+const (
+	RoleStudent    Role = "student"
+	RoleSupervisor Role = "supervisor"
+	RoleStaff      Role = "staff"
+)
+
+// StatusInfo describes the thesis_statuses document of a state
+type StatusInfo struct {
+	State       State
+	Name        string
+	Description string
+	Color       string
+}
+
+// Statuses in lifecycle order, their position is the order field
+var Statuses = []StatusInfo{
+	{StateProposed, "Chờ duyệt", "Đề tài đang chờ phê duyệt", "#FFA500"},
+	{StateApproved, "Đã duyệt", "Đề tài đã được giảng viên hướng dẫn phê duyệt", "#20B2AA"},
+	{StateInProgress, "Đang thực hiện", "Đề tài đang được thực hiện", "#0080FF"},
+	{StateSubmitted, "Đã nộp", "Sinh viên đã nộp bản cuối", "#4B0082"},
+	{StateUnderReview, "Đang phản biện", "Bản cuối đang được phản biện", "#DAA520"},
+	{StateScheduledForDefense, "Chờ bảo vệ", "Đã xếp lịch bảo vệ", "#800080"},
+	{StateDefended, "Hoàn thành", "Đã bảo vệ thành công", "#008000"},
+	{StateArchived, "Lưu trữ", "Luận văn đã được lưu trữ", "#808080"},
+}
+
+type Transition struct {
+	From  State
+	To    State
+	Roles []Role
+	// Preconditions are checked in order, the first failing one is reported
+	Preconditions []Precondition
+}
+
+var ThesisTransitions = []Transition{
+	{From: StateProposed, To: StateApproved, Roles: []Role{RoleSupervisor}, Preconditions: []Precondition{hasSupervisor}},
+	{From: StateApproved, To: StateInProgress, Roles: []Role{RoleStudent, RoleSupervisor}},
+	{From: StateInProgress, To: StateSubmitted, Roles: []Role{RoleStudent}, Preconditions: []Precondition{hasFinalSubmission}},
+	{From: StateSubmitted, To: StateUnderReview, Roles: []Role{RoleSupervisor, RoleStaff}, Preconditions: []Precondition{hasFinalSubmission}},
+	// Yêu cầu chỉnh sửa sau phản biện
+	{From: StateUnderReview, To: StateInProgress, Roles: []Role{RoleSupervisor}},
+	{From: StateUnderReview, To: StateScheduledForDefense, Roles: []Role{RoleStaff}, Preconditions: []Precondition{hasDefenseSchedule}},
+	{From: StateScheduledForDefense, To: StateDefended, Roles: []Role{RoleStaff}, Preconditions: []Precondition{hasDefenseScores}},
+	{From: StateDefended, To: StateArchived, Roles: []Role{RoleStaff}},
+}
+
+// FindTransition returns the declared transition between two states
+func FindTransition(from, to State) (Transition, bool) {
+	for _, t := range ThesisTransitions {
+		if t.From == from && t.To == to {
+			return t, true
+		}
+	}
+	return Transition{}, false
+}
+
+// AllowedFrom lists the states reachable from state
+func AllowedFrom(state State) []State {
+	var result []State
+	for _, t := range ThesisTransitions {
+		if t.From == state {
+			result = append(result, t.To)
+		}
+	}
+	return result
+}
+
+func IsState(value string) bool {
+	for _, status := range Statuses {
+		if string(status.State) == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+
+	pb "thaily/proto/common"
+	"thaily/services/_common/hooks"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const transitionKey = "workflow.transition"
+
+// status_id của luận văn chỉ được đổi qua TransitionThesis
+func init() {
+	hooks.Register(hooks.Hook{Name: "theses.initial_status", EntityType: "theses", Event: hooks.BeforeCreate, Fn: initialStatus})
+	hooks.Register(hooks.Hook{Name: "theses.guard_status", EntityType: "theses", Event: hooks.BeforeUpdate, Fn: guardStatus})
+}
+
+// MarkTransition allows the write of op to change status_id
+func MarkTransition(op *hooks.Operation, from, to State) {
+	op.State[transitionKey] = [2]State{from, to}
+}
+
+func statusChangeRejection(value interface{}) error {
+	message := "status_id can only be changed with TransitionThesis"
+	return hooks.Reject(message, &pb.ErrorDetail{
+		Code:    "INVALID_TRANSITION",
+		Field:   "status_id",
+		Value:   fmt.Sprint(value),
+		Message: message,
+	})
+}
+
+func initialStatus(ctx context.Context, op *hooks.Operation) error {
+	statusID, err := NewEngine(op.DB).StatusID(ctx, InitialState)
+	if err != nil {
+		return err
+	}
+
+	if value, ok := op.Data["status_id"]; ok && value != nil && value != "" && !sameID(value, statusID.Hex()) {
+		return statusChangeRejection(value)
+	}
+	op.Data["status_id"] = statusID
+	return nil
+}
+
+func guardStatus(ctx context.Context, op *hooks.Operation) error {
+	value, ok := op.Data["status_id"]
+	if !ok {
+		return nil
+	}
+	if _, ok := op.State[transitionKey]; ok {
+		return nil
+	}
+
+	id, err := primitive.ObjectIDFromHex(op.ID)
+	if err != nil {
+		return nil
+	}
+	var current struct {
+		StatusID interface{} `bson:"status_id"`
+	}
+	err = op.DB.Collection(op.EntityType).FindOne(ctx, bson.M{"_id": id}).Decode(&current)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read thesis: %w", err)
+	}
+
+	// Gửi lại đúng status_id hiện tại (update toàn bộ document) vẫn hợp lệ
+	if hex, ok := value.(string); ok && sameID(current.StatusID, hex) {
+		op.Data["status_id"] = current.StatusID
+		return nil
+	}
+	if id, ok := value.(primitive.ObjectID); ok && sameID(current.StatusID, id.Hex()) {
+		return nil
+	}
+	return statusChangeRejection(value)
+}
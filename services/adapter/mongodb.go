@@ -2,7 +2,10 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	pb "thaily/proto/common"
@@ -20,6 +23,18 @@ import (
 type MongoDBAdapter struct {
 	client   *mongo.Client
 	database *mongo.Database
+
+	textIndexMu    sync.Mutex
+	textIndexCache map[string]textIndexEntry
+
+	transactionsMu        sync.Mutex
+	transactionsProbed    bool
+	transactionsSupported bool
+}
+
+type textIndexEntry struct {
+	fields    []string
+	expiresAt time.Time
 }
 
 func NewMongoDBAdapter(uri, dbName string) (*MongoDBAdapter, error) {
@@ -36,11 +51,103 @@ func NewMongoDBAdapter(uri, dbName string) (*MongoDBAdapter, error) {
 	}
 
 	return &MongoDBAdapter{
-		client:   client,
-		database: client.Database(dbName),
+		client:         client,
+		database:       client.Database(dbName),
+		textIndexCache: make(map[string]textIndexEntry),
 	}, nil
 }
 
+func (m *MongoDBAdapter) GetDatabase() *mongo.Database {
+	return m.database
+}
+
+// TextIndexFields returns the fields covered by the collection's text index,
+// or nil when it has none. Results are cached for a minute.
+func (m *MongoDBAdapter) TextIndexFields(ctx context.Context, _collection string) ([]string, error) {
+	m.textIndexMu.Lock()
+	entry, ok := m.textIndexCache[_collection]
+	m.textIndexMu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.fields, nil
+	}
+
+	cursor, err := m.database.Collection(_collection).Indexes().List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var indexes []bson.M
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+
+	var fields []string
+	for _, index := range indexes {
+		weights, ok := index["weights"].(bson.M)
+		if !ok {
+			continue
+		}
+		for field := range weights {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	m.textIndexMu.Lock()
+	m.textIndexCache[_collection] = textIndexEntry{fields: fields, expiresAt: time.Now().Add(time.Minute)}
+	m.textIndexMu.Unlock()
+
+	return fields, nil
+}
+
+// ErrTransactionsUnsupported is returned by RunInTransaction on a standalone
+// server
+var ErrTransactionsUnsupported = errors.New("MongoDB is a standalone server, multi-document transactions need a replica set or mongos")
+
+// SupportsTransactions reports whether the server is a replica set member or
+// mongos; standalone servers cannot run multi-document transactions. Only a
+// successful probe is cached.
+func (m *MongoDBAdapter) SupportsTransactions(ctx context.Context) (bool, error) {
+	m.transactionsMu.Lock()
+	defer m.transactionsMu.Unlock()
+	if m.transactionsProbed {
+		return m.transactionsSupported, nil
+	}
+
+	var hello bson.M
+	if err := m.database.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, fmt.Errorf("failed to check transaction support: %w", err)
+	}
+	_, isReplicaSet := hello["setName"]
+	m.transactionsSupported = isReplicaSet || hello["msg"] == "isdbgrid"
+	m.transactionsProbed = true
+	return m.transactionsSupported, nil
+}
+
+// RunInTransaction runs fn inside a multi-document transaction. It fails
+// rather than running fn without one.
+func (m *MongoDBAdapter) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	supported, err := m.SupportsTransactions(ctx)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return ErrTransactionsUnsupported
+	}
+
+	session, err := m.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
 func (m *MongoDBAdapter) Close() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -51,6 +158,13 @@ func (m *MongoDBAdapter) Create(ctx context.Context, _collection string, doc bso
 	collection := m.database.Collection(_collection)
 	result, err := collection.InsertOne(ctx, doc)
 	if err != nil {
+		if message, details, ok := m.duplicateResponse(ctx, _collection, err); ok {
+			return &pb.GenericResponse{
+				Success: false,
+				Message: message,
+				Errors:  details,
+			}, nil
+		}
 		return &pb.GenericResponse{
 			Success: false,
 			Message: fmt.Sprintf("failed to create entity: %v", err),
@@ -84,15 +198,8 @@ func (m *MongoDBAdapter) CreateMany(ctx context.Context, _collection string, doc
 	result, err := collection.InsertMany(ctx, docs, opts)
 
 	if err != nil {
-		if ordered {
-			return &pb.BatchResponse{
-				Success: false,
-				Message: fmt.Sprintf("batch insert failed: %v", err),
-			}, nil
-		}
-
 		bulkErr, ok := err.(mongo.BulkWriteException)
-		if !ok {
+		if !ok || (ordered && len(duplicateKeyErrors(err)) == 0) {
 			return &pb.BatchResponse{
 				Success: false,
 				Message: fmt.Sprintf("batch insert failed: %v", err),
@@ -101,23 +208,40 @@ func (m *MongoDBAdapter) CreateMany(ctx context.Context, _collection string, doc
 
 		errors := make([]*pb.ErrorDetail, len(bulkErr.WriteErrors))
 		for i, we := range bulkErr.WriteErrors {
+			if we.Code == 11000 || we.Code == 11001 {
+				errors[i] = m.duplicateDetail(ctx, _collection, we.WriteError)
+				continue
+			}
 			errors[i] = &pb.ErrorDetail{
 				Code:    fmt.Sprintf("%d", we.Code),
 				Field:   "",
 				Message: we.Message,
+				Index:   int32(we.Index),
 			}
 		}
 
-		ids := make([]string, len(result.InsertedIDs))
-		for i, id := range result.InsertedIDs {
-			ids[i] = id.(primitive.ObjectID).Hex()
+		// InsertedIDs lists every attempted document, drop the ones that failed
+		// and, for ordered inserts, everything after the first failure
+		failed := make(map[int]bool, len(bulkErr.WriteErrors))
+		stopAt := len(result.InsertedIDs)
+		for _, we := range bulkErr.WriteErrors {
+			failed[we.Index] = true
+			if ordered && we.Index < stopAt {
+				stopAt = we.Index
+			}
+		}
+		ids := make([]string, 0, len(result.InsertedIDs))
+		for i, id := range result.InsertedIDs[:stopAt] {
+			if !failed[i] {
+				ids = append(ids, id.(primitive.ObjectID).Hex())
+			}
 		}
 
 		return &pb.BatchResponse{
 			Success:      false,
 			Message:      "Partial batch insert completed with errors",
 			Ids:          ids,
-			CreatedCount: int32(len(result.InsertedIDs)),
+			CreatedCount: int32(len(ids)),
 			Errors:       errors,
 		}, nil
 	}
@@ -354,6 +478,13 @@ func (m *MongoDBAdapter) Update(ctx context.Context, _collection string, _id pri
 				Message: "Entity not found",
 			}, nil
 		}
+		if message, details, ok := m.duplicateResponse(ctx, _collection, err); ok {
+			return &pb.GenericResponse{
+				Success: false,
+				Message: message,
+				Errors:  details,
+			}, nil
+		}
 		return &pb.GenericResponse{
 			Success: false,
 			Message: fmt.Sprintf("failed to update entity: %v", err),
@@ -404,39 +535,36 @@ func (m *MongoDBAdapter) Delete(ctx context.Context, _collection string, _id pri
 
 func (m *MongoDBAdapter) DeleteMany(ctx context.Context, _collection string, ids []primitive.ObjectID, pipeline bson.A) (*pb.DeleteManyResponse, error) {
 	collection := m.database.Collection(_collection)
-	filter := bson.M{}
-	filter["_id"] = bson.M{"$in": ids}
-
-	if len(pipeline) > 0 {
-
-		pipeline = append(pipeline, bson.M{"$match": filter})
-
-		cursor, err := collection.Aggregate(ctx, pipeline)
-		if err != nil {
-			return &pb.DeleteManyResponse{
-				Success: false,
-				Message: fmt.Sprintf("failed to execute pipeline: %v", err),
-			}, nil
-		}
-		defer cursor.Close(ctx)
+	if len(ids) == 0 && len(pipeline) == 0 {
+		return &pb.DeleteManyResponse{
+			Success: false,
+			Message: "either ids or pipeline must be provided",
+		}, nil
+	}
 
-		var docsToDelete []bson.M
-		if err := cursor.All(ctx, &docsToDelete); err != nil {
-			return &pb.DeleteManyResponse{
-				Success: false,
-				Message: fmt.Sprintf("failed to get documents: %v", err),
-			}, nil
-		}
+	targetIDs, err := m.ResolveIds(ctx, _collection, ids, pipeline)
+	if err != nil {
+		return &pb.DeleteManyResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
 
-		ids := make([]primitive.ObjectID, len(docsToDelete))
-		for i, doc := range docsToDelete {
-			ids[i] = doc["_id"].(primitive.ObjectID)
-		}
+	deletedIDs := make([]string, len(targetIDs))
+	for i, id := range targetIDs {
+		deletedIDs[i] = id.Hex()
+	}
 
-		filter = bson.M{"_id": bson.M{"$in": ids}}
+	if len(targetIDs) == 0 {
+		return &pb.DeleteManyResponse{
+			Success:    true,
+			Message:    "No entities matched",
+			FailedIds:  []string{},
+			DeletedIds: []string{},
+		}, nil
 	}
 
-	result, err := collection.DeleteMany(ctx, filter)
+	result, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": targetIDs}})
 	if err != nil {
 		return &pb.DeleteManyResponse{
 			Success: false,
@@ -449,9 +577,90 @@ func (m *MongoDBAdapter) DeleteMany(ctx context.Context, _collection string, ids
 		Message:      "Entities deleted successfully",
 		DeletedCount: int32(result.DeletedCount),
 		FailedIds:    []string{},
+		DeletedIds:   deletedIDs,
 	}, nil
 }
 
+// ResolveIds returns the ids matched by ids and/or pipeline, so callers know
+// exactly which documents a bulk operation will touch
+func (m *MongoDBAdapter) ResolveIds(ctx context.Context, _collection string, ids []primitive.ObjectID, pipeline bson.A) ([]primitive.ObjectID, error) {
+	collection := m.database.Collection(_collection)
+
+	filter := bson.M{}
+	if len(ids) > 0 {
+		filter["_id"] = bson.M{"$in": ids}
+	}
+
+	var docs []bson.M
+	if len(pipeline) > 0 {
+		stages := append(bson.A{}, pipeline...)
+		if len(ids) > 0 {
+			stages = append(stages, bson.M{"$match": filter})
+		}
+		stages = append(stages, bson.M{"$project": bson.M{"_id": 1}})
+
+		cursor, err := collection.Aggregate(ctx, stages)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute pipeline: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		if err := cursor.All(ctx, &docs); err != nil {
+			return nil, fmt.Errorf("failed to get documents: %w", err)
+		}
+	} else {
+		cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find entities: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		if err := cursor.All(ctx, &docs); err != nil {
+			return nil, fmt.Errorf("failed to get documents: %w", err)
+		}
+	}
+
+	result := make([]primitive.ObjectID, 0, len(docs))
+	for _, doc := range docs {
+		if id, ok := doc["_id"].(primitive.ObjectID); ok {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// FindByIds loads documents by id, keyed by their hex id
+func (m *MongoDBAdapter) FindByIds(ctx context.Context, _collection string, ids []primitive.ObjectID, projection bson.M) (map[string]*structpb.Struct, error) {
+	collection := m.database.Collection(_collection)
+
+	opts := options.Find()
+	if len(projection) > 0 {
+		opts.SetProjection(projection)
+	}
+	cursor, err := collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entities: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+
+	entities := make(map[string]*structpb.Struct, len(docs))
+	for _, doc := range docs {
+		id, ok := doc["_id"].(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		if entityStruct, err := helper.DocToStruct(doc); err == nil {
+			entities[id.Hex()] = entityStruct
+		}
+	}
+	return entities, nil
+}
+
 func (m *MongoDBAdapter) Aggregate(ctx context.Context, _collection string, allowDiskUse bool, maxTimeMs int32, pipeline bson.A) (*pb.AggregateResponse, error) {
 	collection := m.database.Collection(_collection)
 	opts := options.Aggregate()
@@ -0,0 +1,194 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	pb "thaily/proto/common"
+	"thaily/services/_common/schema"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Thời gian tối đa tìm document trùng khi ctx không có deadline
+const duplicateLookupTimeout = 5 * time.Second
+
+// Collation dùng cho ràng buộc không phân biệt hoa thường
+var caseInsensitiveCollation = &options.Collation{Locale: "vi", Strength: 2}
+
+// EnsureUniqueIndexes creates the unique indexes declared in
+// schema.UniqueConstraints. Indexes that cannot be built, usually because the
+// collection already holds duplicates, are logged and skipped.
+func (m *MongoDBAdapter) EnsureUniqueIndexes(ctx context.Context) error {
+	var failed []string
+	for entityType, constraints := range schema.UniqueConstraints {
+		for _, constraint := range constraints {
+			keys := bson.D{}
+			partial := bson.M{}
+			for _, field := range constraint.Fields {
+				keys = append(keys, bson.E{Key: field, Value: 1})
+				partial[field] = bson.M{"$exists": true}
+			}
+
+			opts := options.Index().
+				SetName(constraint.IndexName()).
+				SetUnique(true).
+				SetPartialFilterExpression(partial)
+			if constraint.CaseInsensitive {
+				opts.SetCollation(caseInsensitiveCollation)
+			}
+
+			_, err := m.database.Collection(entityType).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys, Options: opts})
+			if err != nil {
+				log.Printf("Failed to create unique index %s on %s: %v", constraint.IndexName(), entityType, err)
+				failed = append(failed, entityType+"."+constraint.IndexName())
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to create unique indexes: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+var (
+	dupIndexPattern = regexp.MustCompile(`index: (\S+) dup key: (\{.*\})`)
+	dupKeyPattern   = regexp.MustCompile(`([\w.]+): ("(?:[^"\\]|\\.)*"|[^,}]+)`)
+)
+
+// duplicateKeyErrors extracts the duplicate key write errors from err, keyed
+// by their index in the batch
+func duplicateKeyErrors(err error) []mongo.WriteError {
+	var result []mongo.WriteError
+
+	var writeErr mongo.WriteException
+	if errors.As(err, &writeErr) {
+		for _, we := range writeErr.WriteErrors {
+			if we.Code == 11000 || we.Code == 11001 {
+				result = append(result, we)
+			}
+		}
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) {
+		for _, we := range bulkErr.WriteErrors {
+			if we.Code == 11000 || we.Code == 11001 {
+				result = append(result, we.WriteError)
+			}
+		}
+	}
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Code == 11000 || cmdErr.Code == 11001) {
+		result = append(result, mongo.WriteError{Code: int(cmdErr.Code), Message: cmdErr.Message, Raw: cmdErr.Raw})
+	}
+	return result
+}
+
+// duplicateDetail turns a duplicate key error into an ErrorDetail naming the
+// conflicting field, value and the id of the document already holding it
+func (m *MongoDBAdapter) duplicateDetail(ctx context.Context, _collection string, we mongo.WriteError) *pb.ErrorDetail {
+	indexName := ""
+	if match := dupIndexPattern.FindStringSubmatch(we.Message); match != nil {
+		indexName = match[1]
+	}
+
+	// Server từ 4.4 trả keyValue, bản cũ hơn chỉ có trong message
+	key := bson.D{}
+	if raw, err := we.Raw.LookupErr("keyValue"); err == nil {
+		if doc, ok := raw.DocumentOK(); ok {
+			bson.Unmarshal(doc, &key)
+		}
+	}
+	if len(key) == 0 {
+		if match := dupIndexPattern.FindStringSubmatch(we.Message); match != nil {
+			for _, kv := range dupKeyPattern.FindAllStringSubmatch(match[2], -1) {
+				value := strings.Trim(strings.TrimSpace(kv[2]), `"`)
+				if id, err := primitive.ObjectIDFromHex(strings.TrimSuffix(strings.TrimPrefix(value, "ObjectId('"), "')")); err == nil {
+					key = append(key, bson.E{Key: kv[1], Value: id})
+					continue
+				}
+				key = append(key, bson.E{Key: kv[1], Value: value})
+			}
+		}
+	}
+
+	fields := make([]string, len(key))
+	values := make([]string, len(key))
+	for i, e := range key {
+		fields[i] = e.Key
+		values[i] = formatKeyValue(e.Value)
+	}
+	field := strings.Join(fields, ",")
+	value := strings.Join(values, ",")
+
+	detail := &pb.ErrorDetail{
+		Code:    "DUPLICATE",
+		Field:   field,
+		Value:   value,
+		Index:   int32(we.Index),
+		Message: fmt.Sprintf("%s %q already exists", field, value),
+	}
+	if len(key) == 0 {
+		detail.Message = we.Message
+		return detail
+	}
+
+	opts := options.FindOne().SetProjection(bson.M{"_id": 1})
+	if constraint, ok := schema.UniqueConstraintByIndex(_collection, indexName); ok && constraint.CaseInsensitive {
+		opts.SetCollation(caseInsensitiveCollation)
+	}
+	// Lỗi duplicate key đã hủy transaction của ctx, nên tìm document đang giữ
+	// giá trị ngoài session
+	lookupCtx, cancel := withoutSession(ctx)
+	defer cancel()
+	var existing bson.M
+	if err := m.database.Collection(_collection).FindOne(lookupCtx, key, opts).Decode(&existing); err == nil {
+		if id, ok := existing["_id"].(primitive.ObjectID); ok {
+			detail.ExistingId = id.Hex()
+		}
+	}
+	return detail
+}
+
+// withoutSession returns a context keeping the deadline of ctx but not its
+// session
+func withoutSession(ctx context.Context) (context.Context, context.CancelFunc) {
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(context.Background(), deadline)
+	}
+	return context.WithTimeout(context.Background(), duplicateLookupTimeout)
+}
+
+// duplicateResponse builds the message and details for a single-document write
+func (m *MongoDBAdapter) duplicateResponse(ctx context.Context, _collection string, err error) (string, []*pb.ErrorDetail, bool) {
+	dups := duplicateKeyErrors(err)
+	if len(dups) == 0 {
+		return "", nil, false
+	}
+	detail := m.duplicateDetail(ctx, _collection, dups[0])
+	return detail.Message, []*pb.ErrorDetail{detail}, true
+}
+
+func formatKeyValue(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case primitive.ObjectID:
+		return value.Hex()
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(value)
+	}
+}
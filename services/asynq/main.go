@@ -20,6 +20,7 @@ var (
 	dbName   = flag.String("db-name", getEnv("DB_NAME", "asynq"), "Database name")
 	redisAddr = flag.String("redis-addr", getEnv("REDIS_ADDR", "localhost:6379"), "Redis address")
 	redisDB   = flag.Int("redis-db", 0, "Redis database number")
+	serviceToken = flag.String("service-token", getEnv("SERVICE_TOKEN", ""), "Token authenticating background calls to the common service")
 )
 
 func getEnv(key, defaultValue string) string {
@@ -41,6 +42,7 @@ func main() {
 
 	// Create asynq service with Redis
 	asynqService := resolvers.NewAsynqService(mongoAdapter, *redisAddr, *redisDB)
+	asynqService.SetServiceToken(*serviceToken)
 
 	// Initialize gRPC clients from config
 	if err := asynqService.InitializeClients(); err != nil {
@@ -12,6 +12,9 @@ import (
 const (
 	// Task types
 	TypeWorkflowExecution = "workflow:execute"
+
+	// Client that runs the tasks enqueued by the common service
+	CommonServiceClient = "common-service"
 	
 	// Queue names
 	QueueCritical = "critical"
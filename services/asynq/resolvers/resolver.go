@@ -28,6 +28,9 @@ type AsynqService struct {
 	// Context for background processing
 	ctx    context.Context
 	cancel context.CancelFunc
+
+	// Sent as x-service-token on calls to the internal methods of other services
+	serviceToken string
 }
 
 type WorkflowTask struct {
@@ -53,6 +56,12 @@ func NewAsynqService(adapter *adapter.MongoDBAdapter, redisAddr string, redisDB
 	return s
 }
 
+// SetServiceToken sets the token authenticating background calls to other
+// services, see authn.Options.ServiceToken
+func (s *AsynqService) SetServiceToken(token string) {
+	s.serviceToken = token
+}
+
 // InitializeClients sets up gRPC client connections
 func (s *AsynqService) InitializeClients() error {
 	s.clientsMu.Lock()
@@ -61,8 +70,9 @@ func (s *AsynqService) InitializeClients() error {
 	// TODO: Load client configurations from config file or environment
 	// For now, using hardcoded example clients
 	clientConfigs := map[string]string{
-		"auth-service": "localhost:50052",
-		"user-service": "localhost:50053",
+		"auth-service":      "localhost:50052",
+		"user-service":      "localhost:50053",
+		CommonServiceClient: "localhost:50051",
 		// Add more services as needed
 	}
 
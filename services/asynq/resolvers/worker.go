@@ -7,10 +7,14 @@ import (
 	"log"
 
 	pb "thaily/proto/asynq"
+	commonpb "thaily/proto/common"
+	"thaily/services/_common/authn"
+	"thaily/services/_common/tasks"
 	"thaily/services/asynq/utils"
 
 	"github.com/hibiken/asynq"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/structpb"
 )
 
@@ -44,6 +48,7 @@ func NewWorkflowWorker(redisAddr string, redisDB int, service *AsynqService) *Wo
 
 	// Register handlers
 	mux.HandleFunc(TypeWorkflowExecution, worker.HandleWorkflowExecution)
+	mux.HandleFunc(tasks.TypeSimilarityCheck, worker.HandleSimilarityCheck)
 
 	return worker
 }
@@ -141,3 +146,33 @@ func (w *WorkflowWorker) HandleWorkflowExecution(ctx context.Context, t *asynq.T
 	log.Printf("Workflow execution completed: %s", payload.ExecutionID)
 	return nil
 }
+
+// HandleSimilarityCheck runs the similarity check of a document on the common
+// service, which owns the documents and their files
+func (w *WorkflowWorker) HandleSimilarityCheck(ctx context.Context, t *asynq.Task) error {
+	var payload tasks.SimilarityPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	conn, ok := w.service.GetClient(CommonServiceClient)
+	if !ok {
+		return fmt.Errorf("client %s is not configured", CommonServiceClient)
+	}
+
+	log.Printf("Processing similarity check: %s %s", payload.EntityType, payload.ID)
+	ctx = metadata.AppendToOutgoingContext(ctx, authn.ServiceTokenHeader, w.service.serviceToken)
+	resp, err := commonpb.NewCommonServiceClient(conn).CheckSimilarity(ctx, &commonpb.CheckSimilarityRequest{
+		EntityType: payload.EntityType,
+		Id:         payload.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("similarity check failed: %w", err)
+	}
+	if !resp.Success {
+		return fmt.Errorf("similarity check failed: %s", resp.Message)
+	}
+
+	log.Printf("Similarity check completed: %s %s: %s", payload.EntityType, payload.ID, resp.Message)
+	return nil
+}
@@ -59,6 +59,11 @@ func getDefaultConfig() *ServiceConfig {
 				Address: "localhost:50053",
 				Timeout: 30,
 			},
+			{
+				Name:    "common-service",
+				Address: "localhost:50051",
+				Timeout: 30,
+			},
 		},
 	}
 }
\ No newline at end of file
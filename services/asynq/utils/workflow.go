@@ -53,17 +53,13 @@ func ExecuteWorkflowSteps(ctx context.Context, workflow *Workflow, input *pb.Dyn
 			}
 		}
 
-		// Get client for this step
-		client, ok := clients[step.ClientName]
-		if !ok {
+		// Check client for this step
+		if _, ok := clients[step.ClientName]; !ok {
 			return nil, fmt.Errorf("client %s not found for step %s", step.ClientName, step.ID)
 		}
 
-		// Prepare input for this step
-		stepInput := prepareStepInput(step.InputMapping, stepResults)
-
 		// Execute step (placeholder for actual gRPC call)
-		// TODO: Implement actual gRPC method invocation
+		// TODO: Invoke step.Method on the client with prepareStepInput(step.InputMapping, stepResults)
 		result := &structpb.Value{
 			Kind: &structpb.Value_StringValue{
 				StringValue: fmt.Sprintf("Result from step %s", step.ID),
@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers account emails (verification, password reset)
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+const (
+	LogBackend  = "log"
+	FileBackend = "file"
+	SMTPBackend = "smtp"
+)
+
+type Config struct {
+	// log, file or smtp
+	Backend string
+	From    string
+	// Directory of the file backend
+	Dir  string
+	SMTP SMTPConfig
+}
+
+// New builds the configured mailer
+func New(config Config) (Mailer, error) {
+	switch config.Backend {
+	case "", LogBackend:
+		return LogMailer{}, nil
+	case FileBackend:
+		return NewFileMailer(config.Dir, config.From)
+	case SMTPBackend:
+		return NewSMTPMailer(config.SMTP, config.From)
+	default:
+		return nil, fmt.Errorf("unknown mailer %q", config.Backend)
+	}
+}
+
+// LogMailer prints emails to the log, for local development
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("Mail to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
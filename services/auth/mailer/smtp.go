@@ -0,0 +1,58 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// SMTPMailer sends through an SMTP relay, using STARTTLS when offered
+type SMTPMailer struct {
+	config SMTPConfig
+	from   string
+}
+
+func NewSMTPMailer(config SMTPConfig, from string) (*SMTPMailer, error) {
+	if config.Host == "" {
+		return nil, fmt.Errorf("smtp mailer needs a host")
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", from, err)
+	}
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	return &SMTPMailer{config: config, from: from}, nil
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	// Chặn header injection qua địa chỉ hoặc tiêu đề
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return fmt.Errorf("invalid email header")
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
+	}
+	from, _ := mail.ParseAddress(m.from)
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+	msg.Subject = mime.QEncoding.Encode("utf-8", msg.Subject)
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	return smtp.SendMail(addr, auth, from.Address, []string{to.Address}, compose(m.from, msg))
+}
@@ -1 +1,47 @@
 package models
+
+import (
+	pb "thaily/proto/auth"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const RolesCollection = "roles"
+
+// AllPermissions grants every permission, e.g. to the Admin role
+const AllPermissions = "*"
+
+// Role is a document of the roles collection, users reference it by role_id
+type Role struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Permissions []string           `bson:"permissions" json:"permissions"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updatedAt,omitempty" json:"updated_at,omitempty"`
+}
+
+func (r *Role) HasPermission(permission string) bool {
+	for _, have := range r.Permissions {
+		if have == AllPermissions || have == permission {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Role) ToProto() *pb.Roles {
+	role := &pb.Roles{
+		Name:        r.Name,
+		Description: r.Description,
+		Permission:  r.Permissions,
+	}
+	if !r.ID.IsZero() {
+		role.Id = r.ID.Hex()
+	}
+	if !r.CreatedAt.IsZero() {
+		role.CreatedAt = r.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	return role
+}
@@ -1 +1,54 @@
 package models
+
+import (
+	pb "thaily/proto/auth"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const UsersCollection = "users"
+
+// User is a document of the users collection. Role is the role name of
+// accounts created before the roles collection, RoleID takes precedence.
+type User struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Code          string              `bson:"code,omitempty" json:"code,omitempty"`
+	Email         string              `bson:"email" json:"email"`
+	Password      string              `bson:"password,omitempty" json:"-"`
+	FullName      string              `bson:"full_name,omitempty" json:"full_name,omitempty"`
+	Phone         string              `bson:"phone,omitempty" json:"phone,omitempty"`
+	RoleID        *primitive.ObjectID `bson:"role_id,omitempty" json:"role_id,omitempty"`
+	Role          string              `bson:"roles,omitempty" json:"roles,omitempty"`
+	Major         string              `bson:"major,omitempty" json:"major,omitempty"`
+	Status        string              `bson:"status,omitempty" json:"status,omitempty"`
+	EmailVerified bool                `bson:"email_verified,omitempty" json:"email_verified,omitempty"`
+	AvatarURL     string              `bson:"avatarUrl,omitempty" json:"avatar_url,omitempty"`
+	Identities    map[string]string   `bson:"identities,omitempty" json:"-"`
+	CreatedAt     time.Time           `bson:"createdAt,omitempty" json:"created_at,omitempty"`
+	UpdatedAt     time.Time           `bson:"updatedAt,omitempty" json:"updated_at,omitempty"`
+}
+
+// ToProto converts the user, role is its resolved role document
+func (u *User) ToProto(role *Role) *pb.User {
+	user := &pb.User{
+		Id:        u.ID.Hex(),
+		Code:      u.Code,
+		Email:     u.Email,
+		FullName:  u.FullName,
+		Phone:     u.Phone,
+		Major:     u.Major,
+		Status:    u.Status,
+		AvatarUrl: u.AvatarURL,
+	}
+	if role != nil {
+		user.Role = role.ToProto()
+	}
+	if !u.CreatedAt.IsZero() {
+		user.CreatedAt = u.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	if !u.UpdatedAt.IsZero() {
+		user.UpdatedAt = u.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	return user
+}
@@ -0,0 +1,99 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ParseTrustedProxies parses a comma separated list of IPs and CIDRs
+func ParseTrustedProxies(list string) ([]*net.IPNet, error) {
+	var result []*net.IPNet
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", item)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			result = append(result, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", item, err)
+		}
+		result = append(result, network)
+	}
+	return result, nil
+}
+
+// clientIP is the address of the end user. x-forwarded-for and x-real-ip are
+// only honoured when the peer is one of the trusted proxies; the first
+// forwarded address that is not itself a trusted proxy, from the right, is
+// the client. Anyone else gets the peer address, whatever it sends.
+func clientIP(ctx context.Context, trusted []*net.IPNet) string {
+	addr := peerIP(ctx)
+	if addr == "" || !isTrusted(addr, trusted) {
+		return addr
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var hops []string
+	for _, value := range md.Get("x-forwarded-for") {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); net.ParseIP(hop) != nil {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !isTrusted(hops[i], trusted) {
+			return hops[i]
+		}
+	}
+	if len(hops) > 0 {
+		return hops[0]
+	}
+	if values := md.Get("x-real-ip"); len(values) > 0 {
+		if ip := strings.TrimSpace(values[0]); net.ParseIP(ip) != nil {
+			return ip
+		}
+	}
+	return addr
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+func isTrusted(addr string, trusted []*net.IPNet) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
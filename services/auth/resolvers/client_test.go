@@ -0,0 +1,124 @@
+package resolver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		peer string
+		md   metadata.MD
+		want string
+	}{
+		{
+			name: "direct client",
+			peer: "203.0.113.7:51000",
+			want: "203.0.113.7",
+		},
+		{
+			name: "untrusted peer cannot spoof",
+			peer: "203.0.113.7:51000",
+			md:   metadata.Pairs("x-forwarded-for", "198.51.100.1", "x-real-ip", "198.51.100.2"),
+			want: "203.0.113.7",
+		},
+		{
+			name: "trusted gateway",
+			peer: "10.0.0.5:51000",
+			md:   metadata.Pairs("x-forwarded-for", "203.0.113.7"),
+			want: "203.0.113.7",
+		},
+		{
+			name: "client prepends a fake hop",
+			peer: "10.0.0.5:51000",
+			md:   metadata.Pairs("x-forwarded-for", "198.51.100.1, 203.0.113.7"),
+			want: "203.0.113.7",
+		},
+		{
+			name: "chain of trusted proxies",
+			peer: "192.168.1.10:51000",
+			md:   metadata.Pairs("x-forwarded-for", "203.0.113.7, 10.1.2.3"),
+			want: "203.0.113.7",
+		},
+		{
+			name: "only trusted hops",
+			peer: "10.0.0.5:51000",
+			md:   metadata.Pairs("x-forwarded-for", "10.1.2.3, 10.4.5.6"),
+			want: "10.1.2.3",
+		},
+		{
+			name: "x-real-ip from a trusted gateway",
+			peer: "10.0.0.5:51000",
+			md:   metadata.Pairs("x-real-ip", "203.0.113.7"),
+			want: "203.0.113.7",
+		},
+		{
+			name: "garbage forwarded address",
+			peer: "10.0.0.5:51000",
+			md:   metadata.Pairs("x-forwarded-for", "not-an-ip"),
+			want: "10.0.0.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := net.ResolveTCPAddr("tcp", tt.peer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			if got := clientIP(ctx, trusted); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []string
+		wantErr bool
+	}{
+		{list: "", want: nil},
+		{list: "10.0.0.0/8", want: []string{"10.0.0.0/8"}},
+		{list: " 192.168.1.10 , ::1", want: []string{"192.168.1.10/32", "::1/128"}},
+		{list: "10.0.0.0/33", wantErr: true},
+		{list: "gateway", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		networks, err := ParseTrustedProxies(tt.list)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTrustedProxies(%q) error = %v", tt.list, err)
+			continue
+		}
+		var got []string
+		for _, network := range networks {
+			got = append(got, network.String())
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseTrustedProxies(%q) = %v, want %v", tt.list, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseTrustedProxies(%q) = %v, want %v", tt.list, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package resolver
+
+import (
+	"context"
+	"log"
+	pb "thaily/proto/auth"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *AuthService) GoogleLogin(ctx context.Context, req *pb.GoogleLoginRequest) (*pb.LoginResponse, error) {
+	if req.IdToken == "" {
+		return &pb.LoginResponse{
+			Success: false,
+			Message: "ID token is required",
+		}, nil
+	}
+	if s.google == nil {
+		return &pb.LoginResponse{
+			Success: false,
+			Message: "Google login is not configured",
+		}, nil
+	}
+
+	claims, err := s.google.Verify(ctx, req.IdToken)
+	if err != nil {
+		log.Printf("Rejected Google ID token: %v", err)
+		return &pb.LoginResponse{
+			Success: false,
+			Message: "Invalid Google ID token",
+		}, nil
+	}
+
+	config := s.google.Config()
+	user, err := s.linkIdentity(ctx, ExternalIdentity{
+		Provider:  "google",
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		FullName:  claims.Name,
+		Picture:   claims.Picture,
+		Provision: config.AutoProvision,
+		Role:      config.DefaultRole,
+	})
+	if err != nil {
+		if message := identityMessage(err); message != "" {
+			return &pb.LoginResponse{Success: false, Message: message}, nil
+		}
+		return nil, status.Error(codes.Internal, "Failed to find the account")
+	}
+
+	if status, ok := user["status"].(string); ok && status != "active" {
+		return &pb.LoginResponse{
+			Success: false,
+			Message: "Your account is not active",
+		}, nil
+	}
+	return s.issueSession(ctx, user)
+}
@@ -0,0 +1,145 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	errIdentityConflict = errors.New("this account is linked to another identity")
+	errNoAccount        = errors.New("no account exists for this email")
+	errProvision        = errors.New("the account cannot be created")
+)
+
+// ExternalIdentity is a user authenticated by an external provider
+type ExternalIdentity struct {
+	// Provider names the field of users.identities holding the subject
+	Provider string
+	Subject  string
+	Email    string
+	FullName string
+	Picture  string
+	// Provision creates the account when no user has the identity or email
+	Provision bool
+	Role      string
+	// Fields mapped from provider claims (code, major...), set on provisioned
+	// accounts and filled in on linked accounts where still empty
+	Fields map[string]interface{}
+}
+
+// Các field không bao giờ lấy từ claim của nhà cung cấp
+var protectedFields = map[string]bool{
+	"_id": true, "email": true, "password": true, "identities": true,
+	"status": true, "role": true, "roles": true, "role_id": true,
+	"createdAt": true, "updatedAt": true,
+}
+
+// identityMessage is the message shown to the user for a linkIdentity error,
+// empty for internal errors
+func identityMessage(err error) string {
+	if errors.Is(err, errProvision) || errors.Is(err, errNoAccount) || errors.Is(err, errIdentityConflict) {
+		return err.Error()
+	}
+	return ""
+}
+
+// linkIdentity finds the user of an external identity. A user found by email
+// gets the identity linked; with Provision set a missing user is created.
+func (s *AuthService) linkIdentity(ctx context.Context, identity ExternalIdentity) (bson.M, error) {
+	users := s.adapter.GetDatabase().Collection("users")
+	field := "identities." + identity.Provider
+
+	var user bson.M
+	err := users.FindOne(ctx, bson.M{field: identity.Subject}).Decode(&user)
+	if err == nil {
+		return user, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(identity.Email))
+	err = users.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		if !identity.Provision {
+			return nil, errNoAccount
+		}
+		return s.provisionUser(ctx, identity, email)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Liên kết chỉ khi tài khoản chưa gắn với subject khác của cùng nhà cung cấp
+	id, _ := user["_id"].(primitive.ObjectID)
+	set := bson.M{field: identity.Subject, "updatedAt": time.Now()}
+	if avatar, _ := user["avatarUrl"].(string); avatar == "" && identity.Picture != "" {
+		set["avatarUrl"] = identity.Picture
+	}
+	for field, value := range identity.Fields {
+		if current, _ := user[field].(string); current == "" && !protectedFields[field] {
+			set[field] = value
+		}
+	}
+	result, err := users.UpdateOne(ctx,
+		bson.M{"_id": id, field: bson.M{"$in": bson.A{nil, identity.Subject}}},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, errIdentityConflict
+	}
+	for key, value := range set {
+		user[key] = value
+	}
+	return user, nil
+}
+
+func (s *AuthService) provisionUser(ctx context.Context, identity ExternalIdentity, email string) (bson.M, error) {
+	now := time.Now()
+	user := bson.M{
+		"_id":        primitive.NewObjectID(),
+		"email":      email,
+		"status":     "active",
+		"identities": bson.M{identity.Provider: identity.Subject},
+		"createdAt":  now,
+		"updatedAt":  now,
+	}
+	if identity.FullName != "" {
+		user["full_name"] = identity.FullName
+	}
+	for field, value := range identity.Fields {
+		if _, exists := user[field]; !exists && !protectedFields[field] {
+			user[field] = value
+		}
+	}
+	if identity.Picture != "" {
+		user["avatarUrl"] = identity.Picture
+	}
+	if err := s.applyRole(ctx, user, identity.Role); err != nil {
+		return nil, err
+	}
+	if err := s.sequence.AssignCodes(ctx, "users", user); err != nil {
+		return nil, fmt.Errorf("%w: %v", errProvision, err)
+	}
+
+	_, err := s.adapter.GetDatabase().Collection("users").InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		// Một yêu cầu đồng thời đã tạo tài khoản với email này
+		identity.Provision = false
+		return s.linkIdentity(ctx, identity)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,111 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLinkIdentity(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing bson.M
+		identity ExternalIdentity
+		wantErr  error
+		// wantSubject is the subject stored on the account afterwards
+		wantSubject string
+	}{
+		{
+			name:        "links an unlinked account by email",
+			existing:    bson.M{"email": "student@example.edu"},
+			identity:    ExternalIdentity{Provider: "keycloak", Subject: "kc-1", Email: "Student@Example.edu "},
+			wantSubject: "kc-1",
+		},
+		{
+			name:        "same subject signs in again",
+			existing:    bson.M{"email": "student@example.edu", "identities": bson.M{"keycloak": "kc-1"}},
+			identity:    ExternalIdentity{Provider: "keycloak", Subject: "kc-1", Email: "student@example.edu"},
+			wantSubject: "kc-1",
+		},
+		{
+			name:        "another subject with the same email",
+			existing:    bson.M{"email": "student@example.edu", "identities": bson.M{"keycloak": "kc-1"}},
+			identity:    ExternalIdentity{Provider: "keycloak", Subject: "kc-2", Email: "student@example.edu"},
+			wantErr:     errIdentityConflict,
+			wantSubject: "kc-1",
+		},
+		{
+			name:        "other providers do not collide",
+			existing:    bson.M{"email": "student@example.edu", "identities": bson.M{"google": "g-1"}},
+			identity:    ExternalIdentity{Provider: "keycloak", Subject: "kc-1", Email: "student@example.edu"},
+			wantSubject: "kc-1",
+		},
+		{
+			name:     "no account without provisioning",
+			identity: ExternalIdentity{Provider: "keycloak", Subject: "kc-1", Email: "student@example.edu"},
+			wantErr:  errNoAccount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			ctx := context.Background()
+			if tt.existing != nil {
+				insertUser(t, s, tt.existing["email"].(string), tt.existing)
+			}
+
+			_, err := s.linkIdentity(ctx, tt.identity)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("linkIdentity() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantSubject == "" {
+				return
+			}
+
+			var user bson.M
+			err = s.adapter.GetDatabase().Collection("users").
+				FindOne(ctx, bson.M{"email": "student@example.edu"}).Decode(&user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			identities, _ := user["identities"].(bson.M)
+			if got := identities[tt.identity.Provider]; got != tt.wantSubject {
+				t.Errorf("identities.%s = %v, want %s", tt.identity.Provider, got, tt.wantSubject)
+			}
+		})
+	}
+}
+
+func TestLinkIdentityConcurrent(t *testing.T) {
+	s := newTestService(t)
+	insertUser(t, s, "student@example.edu", nil)
+
+	// Nhiều subject cùng liên kết một email, chỉ một được thắng
+	subjects := []string{"kc-1", "kc-2", "kc-3", "kc-4"}
+	errs := make(chan error, len(subjects))
+	for _, subject := range subjects {
+		go func() {
+			_, err := s.linkIdentity(context.Background(), ExternalIdentity{
+				Provider: "keycloak", Subject: subject, Email: "student@example.edu",
+			})
+			errs <- err
+		}()
+	}
+
+	linked := 0
+	for range subjects {
+		err := <-errs
+		switch {
+		case err == nil:
+			linked++
+		case !errors.Is(err, errIdentityConflict):
+			t.Errorf("linkIdentity() error = %v", err)
+		}
+	}
+	if linked != 1 {
+		t.Errorf("%d subjects were linked, want 1", linked)
+	}
+}
@@ -0,0 +1,29 @@
+package resolver
+
+import (
+	"context"
+	pb "thaily/proto/auth"
+)
+
+func (s *AuthService) GetJWKS(ctx context.Context, req *pb.GetJWKSRequest) (*pb.GetJWKSResponse, error) {
+	set := s.jwtManager.JWKS()
+	keys := make([]*pb.JWK, len(set.Keys))
+	for i, key := range set.Keys {
+		keys[i] = &pb.JWK{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+			Y:   key.Y,
+		}
+	}
+	return &pb.GetJWKSResponse{
+		Success: true,
+		Message: "Signing keys retrieved successfully",
+		Keys:    keys,
+	}, nil
+}
@@ -0,0 +1,150 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	pb "thaily/proto/auth"
+	"thaily/services/_common/audit"
+	"thaily/services/auth/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func lockedResponse(until time.Time) *pb.LoginResponse {
+	return &pb.LoginResponse{
+		Success:  false,
+		Message:  "Too many failed login attempts, please try again later",
+		UnlockAt: until.UTC().Format(time.RFC3339),
+	}
+}
+
+// loginAllowed applies the lockout and the progressive delay before the
+// password is checked. Errors of the attempt store only get logged, logins
+// keep working while Redis is down.
+func (s *AuthService) loginAllowed(ctx context.Context, email, ip string) (*pb.LoginResponse, error) {
+	until, err := s.guard.LockedUntil(ctx, email, ip)
+	if err != nil {
+		log.Printf("Failed to check login lockout of %s: %v", email, err)
+		return nil, nil
+	}
+	if !until.IsZero() {
+		return lockedResponse(until), nil
+	}
+
+	delay, err := s.guard.Delay(ctx, email, ip)
+	if err != nil {
+		log.Printf("Failed to check login failures of %s: %v", email, err)
+		return nil, nil
+	}
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		case <-timer.C:
+		}
+	}
+	return nil, nil
+}
+
+// loginFailed records a wrong password and audits the lockouts it starts,
+// returning the end of the longest one
+func (s *AuthService) loginFailed(ctx context.Context, email, ip string, user bson.M) time.Time {
+	lockouts, err := s.guard.Fail(ctx, email, ip)
+	if err != nil {
+		log.Printf("Failed to record login failure of %s: %v", email, err)
+	}
+
+	var until time.Time
+	for _, lockout := range lockouts {
+		entry := audit.Entry{
+			Action:     "account_locked",
+			EntityType: models.UsersCollection,
+			Details: bson.M{
+				"email":     email,
+				"ip":        ip,
+				"failures":  lockout.Failures,
+				"unlock_at": lockout.Until,
+			},
+		}
+		if lockout.IsIP {
+			entry.Action = "ip_locked"
+			entry.EntityType = "ip"
+			entry.EntityID = lockout.Subject
+		} else if user != nil {
+			entry.EntityID = user["_id"]
+		}
+		if err := audit.Log(ctx, s.adapter.GetDatabase(), entry); err != nil {
+			log.Printf("Failed to audit lockout of %s: %v", lockout.Subject, err)
+		}
+		if lockout.Until.After(until) {
+			until = lockout.Until
+		}
+	}
+	return until
+}
+
+// UnlockAccount lifts the lockout and clears the failures of an account, and
+// of a client IP when given
+func (s *AuthService) UnlockAccount(ctx context.Context, req *pb.UnlockAccountRequest) (*pb.UnlockAccountResponse, error) {
+	claims, message := adminClaims(ctx, "")
+	if message != "" {
+		return &pb.UnlockAccountResponse{Success: false, Message: message}, nil
+	}
+	if req.UserId == "" && req.Email == "" {
+		return &pb.UnlockAccountResponse{
+			Success: false,
+			Message: "user_id or email is required",
+		}, nil
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	var userID interface{}
+	if req.UserId != "" {
+		id, err := primitive.ObjectIDFromHex(req.UserId)
+		if err != nil {
+			return &pb.UnlockAccountResponse{Success: false, Message: fmt.Sprintf("invalid user_id: %v", err)}, nil
+		}
+		var user models.User
+		err = s.adapter.GetDatabase().Collection(models.UsersCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+		if err != nil {
+			return &pb.UnlockAccountResponse{
+				Success: false,
+				Message: fmt.Sprintf("user %s not found", req.UserId),
+			}, nil
+		}
+		email, userID = user.Email, id
+	}
+
+	if err := s.guard.UnlockAccount(ctx, email); err != nil {
+		return nil, status.Error(codes.Internal, "Failed to unlock account")
+	}
+	if req.Ip != "" {
+		if err := s.guard.UnlockIP(ctx, req.Ip); err != nil {
+			return nil, status.Error(codes.Internal, "Failed to unlock IP")
+		}
+	}
+
+	err := audit.Log(ctx, s.adapter.GetDatabase(), audit.Entry{
+		UserID:     claims.UserID,
+		Action:     "unlock_account",
+		EntityType: models.UsersCollection,
+		EntityID:   userID,
+		Details:    bson.M{"email": email, "ip": req.Ip},
+	})
+	if err != nil {
+		log.Printf("Failed to audit unlock of %s: %v", email, err)
+	}
+
+	return &pb.UnlockAccountResponse{
+		Success: true,
+		Message: "Account unlocked successfully",
+	}, nil
+}
@@ -2,13 +2,16 @@ package resolver
 
 import (
 	"context"
-	"fmt"
+	"log"
+	"strings"
 	pb "thaily/proto/auth"
-	"thaily/services/_common/helper"
+	"thaily/services/auth/models"
 	"thaily/services/auth/utils"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -21,33 +24,46 @@ func (s *AuthService) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Logi
 		}, nil
 	}
 
-	passwordHash, ok := utils.HashPassword(req.Password)
-	if ok != nil {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	ip := clientIP(ctx, s.proxies)
+	if locked, err := s.loginAllowed(ctx, email, ip); locked != nil || err != nil {
+		return locked, err
+	}
+
+	users := s.adapter.GetDatabase().Collection("users")
+	var user bson.M
+	err := users.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	if err != nil && err != mongo.ErrNoDocuments {
 		return &pb.LoginResponse{
 			Success: false,
-			Message: "Invalid password",
+			Message: "Error Login with email password",
 		}, nil
 	}
 
-	conditions := bson.M{
-		"email":    req.Email,
-		"password": passwordHash,
+	// Email không tồn tại vẫn chạy một lần verify để không lộ qua thời gian phản hồi
+	stored, _ := user["password"].(string)
+	valid, rehash := s.passwords.Verify(req.Password, stored)
+	if user == nil || !valid {
+		if until := s.loginFailed(ctx, email, ip, user); !until.IsZero() {
+			return lockedResponse(until), nil
+		}
+		return &pb.LoginResponse{
+			Success: false,
+			Message: "Invalid email or password",
+		}, nil
 	}
 
-	// push limit
-
-	data, err := s.adapter.FindOne(ctx, "users", conditions, bson.M{})
-
-	user := helper.StructToDoc(data.Entity)
+	if err := s.guard.Succeed(ctx, email); err != nil {
+		log.Printf("Failed to clear login failures of %s: %v", email, err)
+	}
 
-	if err != nil {
+	// Check if user is active
+	if user["status"] == "pending" {
 		return &pb.LoginResponse{
 			Success: false,
-			Message: "Error Login with email password",
+			Message: "Please verify your email before logging in",
 		}, nil
 	}
-
-	// Check if user is active
 	if status, ok := user["status"].(string); ok && status != "active" {
 		return &pb.LoginResponse{
 			Success: false,
@@ -55,41 +71,40 @@ func (s *AuthService) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Logi
 		}, nil
 	}
 
-	// Extract user info
-	userID := data.Id.GetValue()
-	email := user["email"].(string)
-	fullName, _ := user["fullName"].(string)
-	if fullName == "" {
-		fullName, _ = user["name"].(string)
+	if rehash {
+		id, _ := user["_id"].(primitive.ObjectID)
+		s.rehashPassword(ctx, id, req.Password, stored)
 	}
-	roles, _ := user["roles"].(string)
-	if roles == "" {
-		roles = "user"
+
+	return s.issueSession(ctx, user)
+}
+
+// issueSession starts a refresh token family for an authenticated user and
+// returns the login response with both tokens
+func (s *AuthService) issueSession(ctx context.Context, user bson.M) (*pb.LoginResponse, error) {
+	id, _ := user["_id"].(primitive.ObjectID)
+
+	userID := id.Hex()
+	code, err := s.userCode(ctx, userID, user)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to assign user code")
 	}
 
 	// Generate tokens
-	accessToken, err := s.jwtManager.GenerateAccessToken(userID, email, fullName, roles)
+	refreshToken, sessionID, err := s.refresh.Issue(ctx, userID)
 	if err != nil {
-		return nil, status.Error(codes.Internal, "Failed to generate access token")
+		return nil, status.Error(codes.Internal, "Failed to generate refresh token")
 	}
 
-	refreshToken, err := s.jwtManager.GenerateRefreshToken(userID)
+	claims, role, err := s.accessClaims(ctx, userID, user, sessionID)
 	if err != nil {
-		return nil, status.Error(codes.Internal, "Failed to generate refresh token")
+		return nil, status.Error(codes.Internal, "Failed to load user role")
+	}
+	accessToken, err := s.jwtManager.GenerateAccessToken(ctx, claims)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to generate access token")
 	}
 
-	// // Store refresh token in database (optional)
-	// refreshCollection := s.adapter.GetDatabase().Collection("refresh_tokens")
-	// refreshCollection.InsertOne(ctx, bson.M{
-	// 	"userId":    user["_id"],
-	// 	"token":     refreshToken,
-	// 	"createdAt": time.Now(),
-	// 	"expiresAt": time.Now().Add(7 * 24 * time.Hour),
-	// })
-	fmt.Println(user["createdAt"])
-	createdAt, _ := user["createdAt"].(time.Time)
-	updatedAt, _ := user["updatedAt"].(string)
-	fmt.Print(createdAt, updatedAt)
 	return &pb.LoginResponse{
 		Success:      true,
 		Message:      "Login successful",
@@ -97,26 +112,123 @@ func (s *AuthService) Login(ctx context.Context, req *pb.LoginRequest) (*pb.Logi
 		RefreshToken: refreshToken,
 		User: &pb.User{
 			Id:        userID,
-			Code:      fmt.Sprintf("USR%s", userID[:6]),
-			Email:     email,
-			FullName:  fullName,
-			Roles:     roles,
-			AvatarUrl: "",
-			CreatedAt: 0,
-			UpdatedAt: 0,
+			Code:      code,
+			Email:     claims.Email,
+			FullName:  claims.FullName,
+			Phone:     stringField(user, "phone"),
+			Role:      role.ToProto(),
+			Major:     stringField(user, "major"),
+			Status:    stringField(user, "status"),
+			AvatarUrl: stringField(user, "avatarUrl"),
+			CreatedAt: timeField(user, "createdAt"),
+			UpdatedAt: timeField(user, "updatedAt"),
 		},
 	}, nil
 }
 
-func (s *AuthService) GoogleLogin(ctx context.Context, req *pb.GoogleLoginRequest) (*pb.LoginResponse, error) {
-	// TODO: Implement Google OAuth verification
-	// 1. Verify Google ID token
-	// 2. Extract user info from token
-	// 3. Create or update user in database
-	// 4. Generate JWT tokens
+// accessClaims builds the access token claims of a user, with the permissions
+// of the user's role
+func (s *AuthService) accessClaims(ctx context.Context, userID string, user bson.M, sessionID string) (*utils.JWTClaims, *models.Role, error) {
+	role, err := s.roles.ForUser(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	email, fullName := userClaims(user)
+	claims := &utils.JWTClaims{
+		UserID:      userID,
+		Email:       email,
+		FullName:    fullName,
+		Roles:       role.Name,
+		Permissions: role.Permissions,
+		SessionID:   sessionID,
+	}
+	if !role.ID.IsZero() {
+		claims.RoleID = role.ID.Hex()
+	}
+	return claims, role, nil
+}
 
-	return &pb.LoginResponse{
-		Success: false,
-		Message: "Google login not implemented yet",
-	}, nil
+// userClaims extracts the profile fields copied into access tokens
+func userClaims(user bson.M) (email, fullName string) {
+	email, _ = user["email"].(string)
+	fullName, _ = user["fullName"].(string)
+	if fullName == "" {
+		fullName, _ = user["full_name"].(string)
+	}
+	if fullName == "" {
+		fullName, _ = user["name"].(string)
+	}
+	return email, fullName
+}
+
+func stringField(user bson.M, key string) string {
+	value, _ := user[key].(string)
+	return value
+}
+
+// timeField formats a date stored either as a BSON date or as a string
+func timeField(user bson.M, key string) string {
+	switch value := user[key].(type) {
+	case primitive.DateTime:
+		return value.Time().UTC().Format(time.RFC3339)
+	case time.Time:
+		return value.UTC().Format(time.RFC3339)
+	case string:
+		return value
+	}
+	return ""
+}
+
+// rehashPassword upgrades a legacy or weaker stored password after a
+// successful login; failures only cost the upgrade, not the login
+func (s *AuthService) rehashPassword(ctx context.Context, id primitive.ObjectID, plain, stored string) {
+	hash, err := s.passwords.Hash(plain)
+	if err != nil {
+		log.Printf("Failed to rehash password of user %s: %v", id.Hex(), err)
+		return
+	}
+	// Chỉ ghi khi mật khẩu chưa bị đổi trong lúc đăng nhập
+	_, err = s.adapter.GetDatabase().Collection("users").UpdateOne(ctx,
+		bson.M{"_id": id, "password": stored},
+		bson.M{"$set": bson.M{"password": hash}},
+	)
+	if err != nil {
+		log.Printf("Failed to rehash password of user %s: %v", id.Hex(), err)
+	}
+}
+
+// userCode returns the stored user code, assigning one from the users code
+// template for accounts created before codes existed
+func (s *AuthService) userCode(ctx context.Context, userID string, user bson.M) (string, error) {
+	if code, _ := user["code"].(string); code != "" {
+		return code, nil
+	}
+
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.sequence.AssignCodes(ctx, "users", user); err != nil {
+		return "", err
+	}
+
+	users := s.adapter.GetDatabase().Collection("users")
+	result, err := users.UpdateOne(ctx,
+		bson.M{"_id": id, "code": bson.M{"$in": bson.A{nil, ""}}},
+		bson.M{"$set": bson.M{"code": user["code"]}},
+	)
+	if err != nil {
+		return "", err
+	}
+	if result.ModifiedCount == 0 {
+		// Một lần đăng nhập khác đã gán mã trước
+		var current struct {
+			Code string `bson:"code"`
+		}
+		if err := users.FindOne(ctx, bson.M{"_id": id}).Decode(&current); err != nil {
+			return "", err
+		}
+		return current.Code, nil
+	}
+	return user["code"].(string), nil
 }
@@ -14,7 +14,7 @@ func (s *AuthService) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.Lo
 	}
 
 	// Verify token
-	_, err := s.jwtManager.VerifyToken(req.AccessToken)
+	claims, err := s.jwtManager.VerifyToken(ctx, req.AccessToken)
 	if err != nil {
 		return &pb.LogoutResponse{
 			Success: false,
@@ -22,16 +22,23 @@ func (s *AuthService) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.Lo
 		}, nil
 	}
 
-	// Remove refresh tokens for this user
-	// refreshCollection := s.adapter.GetDatabase().Collection("refresh_tokens")
-	// userObjID, _ := primitive.ObjectIDFromHex(claims.UserID)
-	// _, err = refreshCollection.DeleteMany(ctx, bson.M{"userId": userObjID})
-	// if err != nil {
-	// 	return &pb.LogoutResponse{
-	// 		Success: false,
-	// 		Message: "Failed to logout",
-	// 	}, nil
-	// }
+	// Access token bị từ chối ngay thay vì còn hiệu lực tới khi hết hạn
+	if err := s.jwtManager.Revoke(ctx, claims); err != nil {
+		return &pb.LogoutResponse{
+			Success: false,
+			Message: "Failed to logout",
+		}, nil
+	}
+
+	// Thu hồi refresh token của phiên này, các thiết bị khác vẫn đăng nhập
+	if claims.SessionID != "" {
+		if err := s.refresh.RevokeFamily(ctx, claims.SessionID, "logout"); err != nil {
+			return &pb.LogoutResponse{
+				Success: false,
+				Message: "Failed to logout",
+			}, nil
+		}
+	}
 
 	return &pb.LogoutResponse{
 		Success: true,
@@ -0,0 +1,145 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "thaily/proto/auth"
+	"thaily/services/auth/utils"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *AuthService) oidcProvider(name string) *utils.OIDCProvider {
+	for _, provider := range s.oidc {
+		if provider.Config().Name == name {
+			return provider
+		}
+	}
+	return nil
+}
+
+func (s *AuthService) ListOIDCProviders(ctx context.Context, req *pb.ListOIDCProvidersRequest) (*pb.ListOIDCProvidersResponse, error) {
+	providers := make([]*pb.OIDCProvider, len(s.oidc))
+	for i, provider := range s.oidc {
+		config := provider.Config()
+		providers[i] = &pb.OIDCProvider{Name: config.Name, DisplayName: config.DisplayName}
+	}
+	return &pb.ListOIDCProvidersResponse{
+		Success:   true,
+		Message:   "OIDC providers retrieved successfully",
+		Providers: providers,
+	}, nil
+}
+
+// GetOIDCAuthURL starts an authorization code flow. The state, nonce and PKCE
+// verifier stay on the server; the client only follows the returned URL.
+func (s *AuthService) GetOIDCAuthURL(ctx context.Context, req *pb.GetOIDCAuthURLRequest) (*pb.GetOIDCAuthURLResponse, error) {
+	provider := s.oidcProvider(req.Provider)
+	if provider == nil {
+		return &pb.GetOIDCAuthURLResponse{
+			Success: false,
+			Message: fmt.Sprintf("unknown OIDC provider %q", req.Provider),
+		}, nil
+	}
+	redirectURL, err := provider.RedirectURL(req.RedirectUri)
+	if err != nil {
+		return &pb.GetOIDCAuthURLResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	pending, err := s.oidcStates.Create(ctx, req.Provider, redirectURL)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to start login")
+	}
+	authURL, err := provider.AuthCodeURL(ctx, redirectURL, pending.State, pending.Nonce, pending.CodeVerifier)
+	if err != nil {
+		log.Printf("OIDC provider %s is unavailable: %v", req.Provider, err)
+		return &pb.GetOIDCAuthURLResponse{
+			Success: false,
+			Message: "The identity provider is unavailable",
+		}, nil
+	}
+
+	return &pb.GetOIDCAuthURLResponse{
+		Success:          true,
+		Message:          "Redirect the user to authorization_url",
+		AuthorizationUrl: authURL,
+		State:            pending.State,
+	}, nil
+}
+
+// OIDCLogin exchanges the authorization code, verifies the ID token and signs
+// the user in, provisioning the account on first login when configured
+func (s *AuthService) OIDCLogin(ctx context.Context, req *pb.OIDCLoginRequest) (*pb.LoginResponse, error) {
+	if req.Code == "" || req.State == "" {
+		return &pb.LoginResponse{
+			Success: false,
+			Message: "Code and state are required",
+		}, nil
+	}
+	provider := s.oidcProvider(req.Provider)
+	if provider == nil {
+		return &pb.LoginResponse{
+			Success: false,
+			Message: fmt.Sprintf("unknown OIDC provider %q", req.Provider),
+		}, nil
+	}
+
+	pending, err := s.oidcStates.Consume(ctx, req.Provider, req.State)
+	if err == utils.ErrInvalidState {
+		return &pb.LoginResponse{Success: false, Message: err.Error()}, nil
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to load login state")
+	}
+
+	claims, err := provider.Exchange(ctx, req.Code, pending.RedirectURL, pending.CodeVerifier, pending.Nonce)
+	if err != nil {
+		log.Printf("OIDC login with %s failed: %v", req.Provider, err)
+		return &pb.LoginResponse{
+			Success: false,
+			Message: "Single sign-on failed",
+		}, nil
+	}
+
+	config := provider.Config()
+	email := utils.ClaimString(claims, "email")
+	if email == "" {
+		return &pb.LoginResponse{
+			Success: false,
+			Message: "The identity provider did not return an email",
+		}, nil
+	}
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		return &pb.LoginResponse{
+			Success: false,
+			Message: "email is not verified",
+		}, nil
+	}
+
+	user, err := s.linkIdentity(ctx, ExternalIdentity{
+		Provider:  config.Name,
+		Subject:   utils.ClaimString(claims, "sub"),
+		Email:     email,
+		FullName:  utils.ClaimString(claims, "name"),
+		Picture:   utils.ClaimString(claims, "picture"),
+		Provision: config.AutoProvision,
+		Role:      config.MapRole(claims),
+		Fields:    config.MapFields(claims),
+	})
+	if err != nil {
+		if message := identityMessage(err); message != "" {
+			return &pb.LoginResponse{Success: false, Message: message}, nil
+		}
+		return nil, status.Error(codes.Internal, "Failed to find the account")
+	}
+
+	if status, ok := user["status"].(string); ok && status != "active" {
+		return &pb.LoginResponse{
+			Success: false,
+			Message: "Your account is not active",
+		}, nil
+	}
+	return s.issueSession(ctx, user)
+}
@@ -0,0 +1,163 @@
+package resolver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	pb "thaily/proto/auth"
+	"thaily/services/_common/authn"
+	"thaily/services/auth/utils"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// fakeProvider is an OIDC provider whose token endpoint answers with an ID
+// token for Claims, carrying the nonce of the last authorization request
+type fakeProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	nonce  string
+	Claims jwt.MapClaims
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(utils.OIDCDiscovery{
+			Issuer:                f.server.URL,
+			AuthorizationEndpoint: f.server.URL + "/authorize",
+			TokenEndpoint:         f.server.URL + "/token",
+			JWKSURI:               f.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		jwk, _ := authn.NewJWK("provider-key", "RS256", &f.key.PublicKey)
+		json.NewEncoder(w).Encode(authn.JWKS{Keys: []authn.JWK{jwk}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		claims := jwt.MapClaims{
+			"iss":   f.server.URL,
+			"aud":   "thesis-portal",
+			"nonce": f.nonce,
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(5 * time.Minute).Unix(),
+		}
+		for key, value := range f.Claims {
+			claims[key] = value
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "provider-key"
+		signed, err := token.SignedString(f.key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": signed})
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeProvider) Config() utils.OIDCProviderConfig {
+	return utils.OIDCProviderConfig{
+		Name:         "keycloak",
+		Issuer:       f.server.URL,
+		ClientID:     "thesis-portal",
+		RedirectURLs: []string{"http://localhost:3000/sso/callback"},
+	}
+}
+
+// startLogin runs GetOIDCAuthURL and remembers the nonce sent to the provider
+func (f *fakeProvider) startLogin(t *testing.T, s *AuthService) string {
+	t.Helper()
+	resp, err := s.GetOIDCAuthURL(context.Background(), &pb.GetOIDCAuthURLRequest{Provider: "keycloak"})
+	if err != nil || !resp.Success {
+		t.Fatalf("GetOIDCAuthURL() = %v, %v", resp, err)
+	}
+	authURL, err := url.Parse(resp.AuthorizationUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.nonce = authURL.Query().Get("nonce")
+	return resp.State
+}
+
+func TestOIDCLogin(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		// reuse logs in twice with the same state
+		reuse       bool
+		wantSuccess bool
+		wantMessage string
+	}{
+		{
+			name:        "verified email",
+			claims:      jwt.MapClaims{"sub": "kc-1", "email": "student@example.edu", "email_verified": true},
+			wantSuccess: true,
+		},
+		{
+			name:        "reused state",
+			claims:      jwt.MapClaims{"sub": "kc-1", "email": "student@example.edu", "email_verified": true},
+			reuse:       true,
+			wantMessage: utils.ErrInvalidState.Error(),
+		},
+		{
+			name:        "unverified email",
+			claims:      jwt.MapClaims{"sub": "kc-1", "email": "student@example.edu", "email_verified": false},
+			wantMessage: "email is not verified",
+		},
+		{
+			name:        "identity linked to another subject",
+			claims:      jwt.MapClaims{"sub": "kc-2", "email": "linked@example.edu", "email_verified": true},
+			wantMessage: errIdentityConflict.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestService(t)
+			insertUser(t, s, "student@example.edu", nil)
+			insertUser(t, s, "linked@example.edu", bson.M{"identities": bson.M{"keycloak": "kc-9"}})
+
+			f := newFakeProvider(t)
+			f.Claims = tt.claims
+			s.SetOIDCProviders([]utils.OIDCProviderConfig{f.Config()})
+
+			state := f.startLogin(t, s)
+			req := &pb.OIDCLoginRequest{Provider: "keycloak", Code: "code-1", State: state}
+			resp, err := s.OIDCLogin(context.Background(), req)
+			if tt.reuse {
+				if err != nil || !resp.Success {
+					t.Fatalf("first OIDCLogin() = %v, %v", resp, err)
+				}
+				resp, err = s.OIDCLogin(context.Background(), req)
+			}
+			if err != nil {
+				t.Fatalf("OIDCLogin() error = %v", err)
+			}
+			if resp.Success != tt.wantSuccess {
+				t.Fatalf("OIDCLogin() success = %v (%s), want %v", resp.Success, resp.Message, tt.wantSuccess)
+			}
+			if tt.wantMessage != "" && resp.Message != tt.wantMessage {
+				t.Errorf("OIDCLogin() message = %q, want %q", resp.Message, tt.wantMessage)
+			}
+		})
+	}
+}
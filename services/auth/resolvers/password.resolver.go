@@ -0,0 +1,236 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	pb "thaily/proto/auth"
+	"thaily/services/_common/authn"
+	"thaily/services/_common/password"
+	"thaily/services/auth/mailer"
+	"thaily/services/auth/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Giới hạn số yêu cầu để chặn spam email và dò mật khẩu
+const (
+	resetPerEmail  = 3
+	resetPerIP     = 10
+	resetWindow    = time.Hour
+	changePerUser  = 5
+	changeWindow   = 15 * time.Minute
+	tooManyRequest = "Too many requests, please try again later"
+)
+
+const resetMessage = "If an account exists for this email, a password reset link has been sent"
+
+func (s *AuthService) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email == "" {
+		return &pb.RequestPasswordResetResponse{
+			Success: false,
+			Message: "Email is required",
+		}, nil
+	}
+
+	ip := clientIP(ctx, s.proxies)
+	allowed, err := s.limiter.Allow(ctx, "reset:email:"+email, resetPerEmail, resetWindow)
+	if err == nil && allowed && ip != "" {
+		allowed, err = s.limiter.Allow(ctx, "reset:ip:"+ip, resetPerIP, resetWindow)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to request password reset")
+	}
+	if !allowed {
+		return &pb.RequestPasswordResetResponse{Success: false, Message: tooManyRequest}, nil
+	}
+
+	var user bson.M
+	err = s.adapter.GetDatabase().Collection("users").FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	if err == mongo.ErrNoDocuments || (err == nil && user["status"] != "active") {
+		return &pb.RequestPasswordResetResponse{Success: true, Message: resetMessage}, nil
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to request password reset")
+	}
+
+	id, _ := user["_id"].(primitive.ObjectID)
+	token, err := s.resets.Create(ctx, id.Hex(), ip, s.signup.ResetTTL)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to request password reset")
+	}
+	link := s.signup.ResetURL + "?token=" + url.QueryEscape(token)
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Reset your password",
+		Body: "We received a request to reset the password of your account. Open the link below to choose a new one:\n\n" +
+			link + "\n\n" +
+			fmt.Sprintf("The link expires in %s and can be used once. If you did not ask for it, you can ignore this email.\n", s.signup.ResetTTL),
+	})
+	if err != nil {
+		log.Printf("Failed to send password reset email to %s: %v", email, err)
+	}
+	return &pb.RequestPasswordResetResponse{Success: true, Message: resetMessage}, nil
+}
+
+// ResetPassword sets a new password from a reset link and signs the user out
+// everywhere
+func (s *AuthService) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	if req.Token == "" || req.NewPassword == "" {
+		return &pb.ResetPasswordResponse{
+			Success: false,
+			Message: "Token and new password are required",
+		}, nil
+	}
+	if err := password.ValidatePolicy(req.NewPassword); err != nil {
+		return &pb.ResetPasswordResponse{Success: false, Message: err.Error()}, nil
+	}
+	if ip := clientIP(ctx, s.proxies); ip != "" {
+		allowed, err := s.limiter.Allow(ctx, "reset-confirm:ip:"+ip, resetPerIP, resetWindow)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "Failed to reset password")
+		}
+		if !allowed {
+			return &pb.ResetPasswordResponse{Success: false, Message: tooManyRequest}, nil
+		}
+	}
+
+	userID, err := s.resets.Consume(ctx, req.Token)
+	if err == utils.ErrInvalidLink {
+		return &pb.ResetPasswordResponse{Success: false, Message: err.Error()}, nil
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to reset password")
+	}
+
+	user, err := s.setPassword(ctx, userID, req.NewPassword)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to reset password")
+	}
+	if user == nil {
+		return &pb.ResetPasswordResponse{Success: false, Message: utils.ErrInvalidLink.Error()}, nil
+	}
+
+	// Người giữ phiên cũ (có thể là kẻ tấn công) phải đăng nhập lại
+	if err := s.refresh.RevokeUser(ctx, userID, "password_reset"); err != nil {
+		log.Printf("Failed to revoke refresh tokens of user %s: %v", userID, err)
+	}
+	if _, err := s.jwtManager.RevokeAll(ctx, userID); err != nil {
+		log.Printf("Failed to revoke access tokens of user %s: %v", userID, err)
+	}
+	s.resets.InvalidateUser(ctx, userID)
+	s.notifyPasswordChanged(ctx, user)
+
+	return &pb.ResetPasswordResponse{
+		Success: true,
+		Message: "Password has been reset, please log in again",
+	}, nil
+}
+
+// ChangePassword requires the current password; other sessions of the user
+// are signed out, the calling one stays logged in
+func (s *AuthService) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
+	claims := authn.FromContext(ctx)
+	if claims == nil {
+		return &pb.ChangePasswordResponse{
+			Success: false,
+			Message: "Access token is required",
+		}, nil
+	}
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		return &pb.ChangePasswordResponse{
+			Success: false,
+			Message: "Current and new password are required",
+		}, nil
+	}
+	allowed, err := s.limiter.Allow(ctx, "change:user:"+claims.UserID, changePerUser, changeWindow)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to change password")
+	}
+	if !allowed {
+		return &pb.ChangePasswordResponse{Success: false, Message: tooManyRequest}, nil
+	}
+
+	id, err := primitive.ObjectIDFromHex(claims.UserID)
+	if err != nil {
+		return &pb.ChangePasswordResponse{Success: false, Message: "Invalid access token"}, nil
+	}
+	var user bson.M
+	if err := s.adapter.GetDatabase().Collection("users").FindOne(ctx, bson.M{"_id": id}).Decode(&user); err != nil {
+		return &pb.ChangePasswordResponse{Success: false, Message: "User not found"}, nil
+	}
+	stored, _ := user["password"].(string)
+	if ok, _ := s.passwords.Verify(req.CurrentPassword, stored); !ok {
+		return &pb.ChangePasswordResponse{
+			Success: false,
+			Message: "Current password is incorrect",
+		}, nil
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return &pb.ChangePasswordResponse{
+			Success: false,
+			Message: "New password must be different from the current one",
+		}, nil
+	}
+	if err := password.ValidatePolicy(req.NewPassword); err != nil {
+		return &pb.ChangePasswordResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	if _, err := s.setPassword(ctx, claims.UserID, req.NewPassword); err != nil {
+		return nil, status.Error(codes.Internal, "Failed to change password")
+	}
+	if err := s.refresh.RevokeOtherSessions(ctx, claims.UserID, claims.SessionID, "password_changed"); err != nil {
+		log.Printf("Failed to revoke refresh tokens of user %s: %v", claims.UserID, err)
+	}
+	s.resets.InvalidateUser(ctx, claims.UserID)
+	s.notifyPasswordChanged(ctx, user)
+
+	return &pb.ChangePasswordResponse{
+		Success: true,
+		Message: "Password changed successfully",
+	}, nil
+}
+
+// setPassword hashes and stores a new password, returning the user or nil
+// when it does not exist
+func (s *AuthService) setPassword(ctx context.Context, userID, plain string) (bson.M, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, nil
+	}
+	hash, err := s.passwords.Hash(plain)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var user bson.M
+	err = s.adapter.GetDatabase().Collection("users").FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"password": hash, "password_changed_at": now, "updatedAt": now}},
+	).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (s *AuthService) notifyPasswordChanged(ctx context.Context, user bson.M) {
+	email, _ := user["email"].(string)
+	err := s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Your password was changed",
+		Body: fmt.Sprintf("The password of your account was changed on %s.\n\n", time.Now().Format("02/01/2006 15:04 MST")) +
+			"If this was not you, reset your password right away and contact the administrator.\n",
+	})
+	if err != nil {
+		log.Printf("Failed to send password change notice to %s: %v", email, err)
+	}
+}
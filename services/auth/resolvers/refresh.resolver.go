@@ -2,88 +2,75 @@ package resolver
 
 import (
 	"context"
+	"log"
 	pb "thaily/proto/auth"
+	"thaily/services/auth/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func (s *AuthService) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.RefreshTokenResponse, error) {
-	return nil, nil
-	// if req.RefreshToken == "" {
-	// 	return &pb.RefreshTokenResponse{
-	// 		Success: false,
-	// 		Message: "Refresh token is required",
-	// 	}, nil
-	// }
-
-	// // Verify refresh token
-	// claims, err := s.jwtManager.VerifyToken(req.RefreshToken)
-	// if err != nil {
-	// 	return &pb.RefreshTokenResponse{
-	// 		Success: false,
-	// 		Message: "Invalid refresh token",
-	// 	}, nil
-	// }
-
-	// // Check if refresh token exists in database
-	// refreshCollection := s.adapter.GetDatabase().Collection("refresh_tokens")
-	// userObjID, _ := primitive.ObjectIDFromHex(claims.UserID)
-	// var tokenDoc bson.M
-	// err = refreshCollection.FindOne(ctx, bson.M{
-	// 	"userId": userObjID,
-	// 	"token":  req.RefreshToken,
-	// }).Decode(&tokenDoc)
-	// if err != nil {
-	// 	return &pb.RefreshTokenResponse{
-	// 		Success: false,
-	// 		Message: "Invalid refresh token",
-	// 	}, nil
-	// }
-
-	// // Get user info
-	// collection := s.adapter.GetDatabase().Collection("users")
-	// var user bson.M
-	// err = collection.FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user)
-	// if err != nil {
-	// 	return &pb.RefreshTokenResponse{
-	// 		Success: false,
-	// 		Message: "User not found",
-	// 	}, nil
-	// }
-
-	// // Extract user info
-	// email := user["email"].(string)
-	// fullName, _ := user["fullName"].(string)
-	// if fullName == "" {
-	// 	fullName, _ = user["name"].(string)
-	// }
-	// roles, _ := user["roles"].(string)
-	// if roles == "" {
-	// 	roles = "user"
-	// }
+	if req.RefreshToken == "" {
+		return &pb.RefreshTokenResponse{
+			Success: false,
+			Message: "Refresh token is required",
+		}, nil
+	}
 
-	// // Generate new tokens
-	// newAccessToken, err := s.jwtManager.GenerateAccessToken(claims.UserID, email, fullName, roles)
-	// if err != nil {
-	// 	return nil, status.Error(codes.Internal, "Failed to generate access token")
-	// }
+	// Token cũ bị vô hiệu ngay khi xoay vòng, mỗi token chỉ dùng được một lần
+	current, refreshToken, err := s.refresh.Rotate(ctx, req.RefreshToken)
+	if err == utils.ErrRefreshTokenReused {
+		log.Printf("Refresh token reuse detected, revoked session %s of user %s", current.FamilyID, current.UserID)
+	}
+	if err == utils.ErrInvalidRefreshToken || err == utils.ErrRefreshTokenReused {
+		return &pb.RefreshTokenResponse{
+			Success: false,
+			Message: "Invalid refresh token",
+		}, nil
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to refresh token")
+	}
 
-	// newRefreshToken, err := s.jwtManager.GenerateRefreshToken(claims.UserID)
-	// if err != nil {
-	// 	return nil, status.Error(codes.Internal, "Failed to generate refresh token")
-	// }
+	userObjID, err := primitive.ObjectIDFromHex(current.UserID)
+	if err != nil {
+		return &pb.RefreshTokenResponse{
+			Success: false,
+			Message: "Invalid refresh token",
+		}, nil
+	}
+	var user bson.M
+	err = s.adapter.GetDatabase().Collection("users").FindOne(ctx, bson.M{"_id": userObjID}).Decode(&user)
+	if err != nil {
+		return &pb.RefreshTokenResponse{
+			Success: false,
+			Message: "User not found",
+		}, nil
+	}
+	if status, ok := user["status"].(string); ok && status != "active" {
+		s.refresh.RevokeUser(ctx, current.UserID, "user_inactive")
+		return &pb.RefreshTokenResponse{
+			Success: false,
+			Message: "Your account is not active",
+		}, nil
+	}
 
-	// // Delete old refresh token and insert new one
-	// refreshCollection.DeleteOne(ctx, bson.M{"token": req.RefreshToken})
-	// refreshCollection.InsertOne(ctx, bson.M{
-	// 	"userId":    userObjID,
-	// 	"token":     newRefreshToken,
-	// 	"createdAt": time.Now(),
-	// 	"expiresAt": time.Now().Add(7 * 24 * time.Hour),
-	// })
+	claims, _, err := s.accessClaims(ctx, current.UserID, user, current.FamilyID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to load user role")
+	}
+	accessToken, err := s.jwtManager.GenerateAccessToken(ctx, claims)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to generate access token")
+	}
 
-	// return &pb.RefreshTokenResponse{
-	// 	Success:      true,
-	// 	Message:      "Token refreshed successfully",
-	// 	AccessToken:  newAccessToken,
-	// 	RefreshToken: newRefreshToken,
-	// }, nil
+	return &pb.RefreshTokenResponse{
+		Success:      true,
+		Message:      "Token refreshed successfully",
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
 }
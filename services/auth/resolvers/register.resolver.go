@@ -0,0 +1,240 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	pb "thaily/proto/auth"
+	"thaily/services/_common/password"
+	"thaily/services/auth/mailer"
+	"thaily/services/auth/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Khoảng cách tối thiểu giữa hai lần gửi email xác thực cho cùng tài khoản
+const verificationCooldown = time.Minute
+
+// Cùng một thông báo dù email đã có tài khoản hay chưa, để không lộ email
+const registerMessage = "Check your email to verify your account"
+
+func (s *AuthService) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email == "" || req.Password == "" {
+		return &pb.RegisterResponse{
+			Success: false,
+			Message: "Email and password are required",
+		}, nil
+	}
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return &pb.RegisterResponse{
+			Success: false,
+			Message: "email is invalid",
+		}, nil
+	}
+	if !s.signupAllowed(email) {
+		return &pb.RegisterResponse{
+			Success: false,
+			Message: fmt.Sprintf("Registration is restricted to %s addresses", strings.Join(s.signup.AllowedDomains, ", ")),
+		}, nil
+	}
+	if err := password.ValidatePolicy(req.Password); err != nil {
+		return &pb.RegisterResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	users := s.adapter.GetDatabase().Collection("users")
+	var existing bson.M
+	err := users.FindOne(ctx, bson.M{"email": email}).Decode(&existing)
+	if err == nil {
+		s.notifyExisting(ctx, existing)
+		return &pb.RegisterResponse{Success: true, Message: registerMessage}, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, status.Error(codes.Internal, "Failed to register")
+	}
+
+	hash, err := s.passwords.Hash(req.Password)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to register")
+	}
+	now := time.Now()
+	user := bson.M{
+		"_id":            primitive.NewObjectID(),
+		"email":          email,
+		"password":       hash,
+		"full_name":      strings.TrimSpace(req.FullName),
+		"status":         "pending",
+		"email_verified": false,
+		"createdAt":      now,
+		"updatedAt":      now,
+	}
+	if req.Phone != "" {
+		user["phone"] = strings.TrimSpace(req.Phone)
+	}
+	if req.Major != "" {
+		user["major"] = strings.TrimSpace(req.Major)
+	}
+	if err := s.applyRole(ctx, user, s.signup.DefaultRole); err != nil {
+		return nil, status.Error(codes.Internal, "Failed to register")
+	}
+	if err := s.sequence.AssignCodes(ctx, "users", user); err != nil {
+		return &pb.RegisterResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	_, err = users.InsertOne(ctx, user)
+	if mongo.IsDuplicateKeyError(err) {
+		// Một yêu cầu đồng thời vừa đăng ký cùng email
+		return &pb.RegisterResponse{Success: true, Message: registerMessage}, nil
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to register")
+	}
+
+	if err := s.sendVerification(ctx, user); err != nil {
+		// Tài khoản đã tạo, người dùng có thể yêu cầu gửi lại
+		log.Printf("Failed to send verification email to %s: %v", email, err)
+	}
+	return &pb.RegisterResponse{Success: true, Message: registerMessage}, nil
+}
+
+func (s *AuthService) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	invalid := &pb.VerifyEmailResponse{Success: false, Message: utils.ErrInvalidLink.Error()}
+
+	userID, err := s.links.UserID(req.Token)
+	if err != nil {
+		return invalid, nil
+	}
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return invalid, nil
+	}
+	users := s.adapter.GetDatabase().Collection("users")
+	var user bson.M
+	if err := users.FindOne(ctx, bson.M{"_id": id}).Decode(&user); err != nil {
+		return invalid, nil
+	}
+	email, _ := user["email"].(string)
+	if err := s.links.Verify(utils.PurposeVerifyEmail, req.Token, email); err != nil {
+		return invalid, nil
+	}
+
+	switch user["status"] {
+	case "pending":
+	case "active":
+		return &pb.VerifyEmailResponse{Success: true, Message: "Email is already verified"}, nil
+	default:
+		return &pb.VerifyEmailResponse{Success: false, Message: "Your account is not active"}, nil
+	}
+
+	now := time.Now()
+	_, err = users.UpdateOne(ctx,
+		bson.M{"_id": id, "status": "pending"},
+		bson.M{"$set": bson.M{
+			"status":            "active",
+			"email_verified":    true,
+			"email_verified_at": now,
+			"updatedAt":         now,
+		}},
+	)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to verify email")
+	}
+	return &pb.VerifyEmailResponse{Success: true, Message: "Email verified, you can now log in"}, nil
+}
+
+func (s *AuthService) ResendVerification(ctx context.Context, req *pb.ResendVerificationRequest) (*pb.ResendVerificationResponse, error) {
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if email == "" {
+		return &pb.ResendVerificationResponse{
+			Success: false,
+			Message: "Email is required",
+		}, nil
+	}
+
+	var user bson.M
+	err := s.adapter.GetDatabase().Collection("users").FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	if err == nil && user["status"] == "pending" {
+		if err := s.sendVerification(ctx, user); err != nil {
+			log.Printf("Failed to send verification email to %s: %v", email, err)
+		}
+	} else if err != nil && err != mongo.ErrNoDocuments {
+		return nil, status.Error(codes.Internal, "Failed to resend verification")
+	}
+	return &pb.ResendVerificationResponse{
+		Success: true,
+		Message: "If the account is waiting for verification, a new link has been sent",
+	}, nil
+}
+
+func (s *AuthService) signupAllowed(email string) bool {
+	if len(s.signup.AllowedDomains) == 0 {
+		return true
+	}
+	domain := email[strings.LastIndexByte(email, '@')+1:]
+	for _, allowed := range s.signup.AllowedDomains {
+		if strings.EqualFold(domain, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyExisting answers a registration for an email that already has an
+// account: pending accounts get a new link, others a notice by email
+func (s *AuthService) notifyExisting(ctx context.Context, user bson.M) {
+	email, _ := user["email"].(string)
+	var err error
+	if user["status"] == "pending" {
+		err = s.sendVerification(ctx, user)
+	} else {
+		err = s.mailer.Send(ctx, mailer.Message{
+			To:      email,
+			Subject: "You already have an account",
+			Body: "Someone tried to register a new account with this email address.\n\n" +
+				"You already have an account: log in, or reset your password if you forgot it.\n" +
+				"If this was not you, you can ignore this email.\n",
+		})
+	}
+	if err != nil {
+		log.Printf("Failed to email %s: %v", email, err)
+	}
+}
+
+// sendVerification emails a verification link, at most once per
+// verificationCooldown
+func (s *AuthService) sendVerification(ctx context.Context, user bson.M) error {
+	id, _ := user["_id"].(primitive.ObjectID)
+	email, _ := user["email"].(string)
+
+	now := time.Now()
+	result, err := s.adapter.GetDatabase().Collection("users").UpdateOne(ctx,
+		bson.M{"_id": id, "$or": bson.A{
+			bson.M{"verification_sent_at": bson.M{"$exists": false}},
+			bson.M{"verification_sent_at": bson.M{"$lt": now.Add(-verificationCooldown)}},
+		}},
+		bson.M{"$set": bson.M{"verification_sent_at": now}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return nil
+	}
+
+	token := s.links.Sign(utils.PurposeVerifyEmail, id.Hex(), email, s.signup.VerifyTTL)
+	link := s.signup.VerifyURL + "?token=" + url.QueryEscape(token)
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: "Welcome! Open the link below to activate your account:\n\n" + link + "\n\n" +
+			fmt.Sprintf("The link expires in %s. If you did not register, you can ignore this email.\n", s.signup.VerifyTTL),
+	})
+}
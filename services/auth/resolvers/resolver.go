@@ -1,20 +1,154 @@
 package resolver
 
 import (
+	"context"
+	"net"
 	pb "thaily/proto/auth"
+	"thaily/services/_common/authn"
+	"thaily/services/_common/password"
+	"thaily/services/_common/sequence"
 	"thaily/services/adapter"
+	"thaily/services/auth/mailer"
 	"thaily/services/auth/utils"
+	"time"
 )
 
 type AuthService struct {
 	pb.UnimplementedAuthServiceServer
 	adapter    *adapter.MongoDBAdapter
 	jwtManager *utils.JWTManager
+	sequence   *sequence.Generator
+	passwords  *password.Passwords
+	refresh    *utils.RefreshStore
+	google     *utils.GoogleVerifier
+	oidc       []*utils.OIDCProvider
+	oidcStates *utils.OIDCStateStore
+	mailer     mailer.Mailer
+	links      *utils.LinkSigner
+	signup     SignupConfig
+	resets     *utils.PasswordResetStore
+	limiter    *utils.RateLimiter
+	roles      *utils.RoleStore
+	guard      *utils.LoginGuard
+	// Proxies whose forwarded client address is trusted, see clientIP
+	proxies []*net.IPNet
 }
 
-func NewAuthService(adapter *adapter.MongoDBAdapter, jwtSecret string) *AuthService {
+// SignupConfig controls self-service registration and the emailed links
+type SignupConfig struct {
+	// Email domains allowed to register, empty allows any domain
+	AllowedDomains []string
+	// Role of registered accounts
+	DefaultRole string
+	// Frontend pages receiving the token as ?token=
+	VerifyURL string
+	ResetURL  string
+	// Lifetime of the emailed links
+	VerifyTTL time.Duration
+	ResetTTL  time.Duration
+}
+
+func NewAuthService(adapter *adapter.MongoDBAdapter, jwtSecret string, revocations authn.Revocations) *AuthService {
+	jwtManager := utils.NewJWTManager(jwtSecret, revocations)
 	return &AuthService{
 		adapter:    adapter,
-		jwtManager: utils.NewJWTManager(jwtSecret),
+		jwtManager: jwtManager,
+		sequence:   sequence.NewGenerator(adapter.GetDatabase()),
+		passwords:  password.Default(),
+		refresh:    utils.NewRefreshStore(adapter.GetDatabase(), jwtManager.RefreshTokenExpiry()),
+		oidcStates: utils.NewOIDCStateStore(adapter.GetDatabase()),
+		mailer:     mailer.LogMailer{},
+		links:      utils.NewLinkSigner(jwtSecret),
+		signup: SignupConfig{
+			VerifyURL: "http://localhost:3000/verify-email",
+			ResetURL:  "http://localhost:3000/reset-password",
+			VerifyTTL: 48 * time.Hour,
+			ResetTTL:  30 * time.Minute,
+		},
+		resets:  utils.NewPasswordResetStore(adapter.GetDatabase()),
+		limiter: utils.NewRateLimiter(adapter.GetDatabase()),
+		roles:   utils.NewRoleStore(adapter.GetDatabase()),
+		guard:   utils.NewLoginGuard(utils.NewMemoryAttemptStore(), utils.DefaultLockoutConfig()),
+	}
+}
+
+// SetGoogleVerifier enables GoogleLogin
+func (s *AuthService) SetGoogleVerifier(verifier *utils.GoogleVerifier) {
+	s.google = verifier
+}
+
+// SetMailer replaces the default log mailer
+func (s *AuthService) SetMailer(m mailer.Mailer) {
+	s.mailer = m
+}
+
+// SetSignupConfig configures registration, empty fields keep their defaults
+func (s *AuthService) SetSignupConfig(config SignupConfig) {
+	if config.VerifyURL == "" {
+		config.VerifyURL = s.signup.VerifyURL
+	}
+	if config.ResetURL == "" {
+		config.ResetURL = s.signup.ResetURL
+	}
+	if config.VerifyTTL == 0 {
+		config.VerifyTTL = s.signup.VerifyTTL
+	}
+	if config.ResetTTL == 0 {
+		config.ResetTTL = s.signup.ResetTTL
+	}
+	s.signup = config
+}
+
+// Verifier checks access tokens, for the auth interceptor
+func (s *AuthService) Verifier() *authn.Verifier {
+	return s.jwtManager.Verifier()
+}
+
+// SetLoginGuard replaces the in-memory login lockout, e.g. with one backed
+// by Redis
+func (s *AuthService) SetLoginGuard(guard *utils.LoginGuard) {
+	s.guard = guard
+}
+
+// SetTrustedProxies honours x-forwarded-for from the given gateways, for rate
+// limits and lockouts per client IP
+func (s *AuthService) SetTrustedProxies(proxies []*net.IPNet) {
+	s.proxies = proxies
+}
+
+// SetSigningKeys signs access tokens with the keys of ring instead of the
+// shared secret
+func (s *AuthService) SetSigningKeys(ring *utils.KeyRing) {
+	s.jwtManager.UseKeyRing(ring)
+}
+
+// JWKS is the public key set served to other services
+func (s *AuthService) JWKS() authn.JWKS {
+	return s.jwtManager.JWKS()
+}
+
+// SetOIDCProviders enables SSO through the configured OIDC providers
+func (s *AuthService) SetOIDCProviders(configs []utils.OIDCProviderConfig) {
+	s.oidc = nil
+	for _, config := range configs {
+		s.oidc = append(s.oidc, utils.NewOIDCProvider(config))
+	}
+}
+
+// EnsureIndexes creates the indexes of the token, login state, rate limit
+// and roles collections
+func (s *AuthService) EnsureIndexes(ctx context.Context) error {
+	if err := s.refresh.EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := s.oidcStates.EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := s.resets.EnsureIndexes(ctx); err != nil {
+		return err
+	}
+	if err := s.limiter.EnsureIndexes(ctx); err != nil {
+		return err
 	}
+	return s.roles.EnsureIndexes(ctx)
 }
@@ -0,0 +1,68 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	pb "thaily/proto/auth"
+	"thaily/services/_common/audit"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RevokeAllForUser bumps the token generation of a user so every access token
+// issued so far is rejected, and revokes all of their refresh tokens
+func (s *AuthService) RevokeAllForUser(ctx context.Context, req *pb.RevokeAllForUserRequest) (*pb.RevokeAllForUserResponse, error) {
+	claims, message := adminClaims(ctx, "")
+	if message != "" {
+		return &pb.RevokeAllForUserResponse{Success: false, Message: message}, nil
+	}
+
+	userObjID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return &pb.RevokeAllForUserResponse{
+			Success: false,
+			Message: fmt.Sprintf("invalid user_id: %v", err),
+		}, nil
+	}
+	db := s.adapter.GetDatabase()
+	count, err := db.Collection("users").CountDocuments(ctx, bson.M{"_id": userObjID})
+	if err != nil || count == 0 {
+		return &pb.RevokeAllForUserResponse{
+			Success: false,
+			Message: fmt.Sprintf("user %s not found", req.UserId),
+		}, nil
+	}
+
+	if err := s.refresh.RevokeUser(ctx, req.UserId, "revoked_by_admin"); err != nil {
+		return &pb.RevokeAllForUserResponse{
+			Success: false,
+			Message: fmt.Sprintf("failed to revoke refresh tokens: %v", err),
+		}, nil
+	}
+	generation, err := s.jwtManager.RevokeAll(ctx, req.UserId)
+	if err != nil {
+		return &pb.RevokeAllForUserResponse{
+			Success: false,
+			Message: fmt.Sprintf("failed to revoke access tokens: %v", err),
+		}, nil
+	}
+
+	err = audit.Log(ctx, db, audit.Entry{
+		UserID:     claims.UserID,
+		Action:     "revoke_all_tokens",
+		EntityType: "users",
+		EntityID:   userObjID,
+		Details:    bson.M{"reason": req.Reason, "generation": generation},
+	})
+	if err != nil {
+		log.Printf("Failed to audit token revocation of user %s: %v", req.UserId, err)
+	}
+
+	return &pb.RevokeAllForUserResponse{
+		Success:    true,
+		Message:    "All tokens of the user have been revoked",
+		Generation: generation,
+	}, nil
+}
@@ -0,0 +1,252 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	pb "thaily/proto/auth"
+	"thaily/services/_common/audit"
+	"thaily/services/_common/authn"
+	"thaily/services/auth/models"
+	"thaily/services/auth/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PermissionManageRoles lets a non-admin role manage roles and assignments
+const PermissionManageRoles = "manage_roles"
+
+// adminClaims returns the caller's claims when they may manage users, roles
+// and tokens, otherwise the message to answer with
+func adminClaims(ctx context.Context, permission string) (*authn.Claims, string) {
+	claims := authn.FromContext(ctx)
+	if claims == nil {
+		return nil, "Access token is required"
+	}
+	if claims.HasRole("admin") || (permission != "" && claims.HasPermission(permission)) {
+		return claims, ""
+	}
+	return nil, "Only administrators can perform this action"
+}
+
+// roleMessage is the message shown for a RoleStore error, empty for internal
+// errors
+func roleMessage(err error) string {
+	if err == utils.ErrRoleNotFound || err == utils.ErrRoleExists || err == utils.ErrRoleInUse {
+		return err.Error()
+	}
+	return ""
+}
+
+func (s *AuthService) ListRoles(ctx context.Context, req *pb.ListRolesRequest) (*pb.ListRolesResponse, error) {
+	if _, message := adminClaims(ctx, PermissionManageRoles); message != "" {
+		return &pb.ListRolesResponse{Success: false, Message: message}, nil
+	}
+	roles, err := s.roles.List(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to list roles")
+	}
+	result := make([]*pb.Roles, len(roles))
+	for i := range roles {
+		result[i] = roles[i].ToProto()
+	}
+	return &pb.ListRolesResponse{
+		Success: true,
+		Message: "Roles retrieved successfully",
+		Roles:   result,
+	}, nil
+}
+
+func (s *AuthService) GetRole(ctx context.Context, req *pb.GetRoleRequest) (*pb.RoleResponse, error) {
+	if _, message := adminClaims(ctx, PermissionManageRoles); message != "" {
+		return &pb.RoleResponse{Success: false, Message: message}, nil
+	}
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return &pb.RoleResponse{Success: false, Message: fmt.Sprintf("invalid id: %v", err)}, nil
+	}
+	role, err := s.roles.Get(ctx, id)
+	if err != nil {
+		return s.roleError(err, "Failed to get role")
+	}
+	return &pb.RoleResponse{
+		Success: true,
+		Message: "Role retrieved successfully",
+		Role:    role.ToProto(),
+	}, nil
+}
+
+func (s *AuthService) CreateRole(ctx context.Context, req *pb.CreateRoleRequest) (*pb.RoleResponse, error) {
+	claims, message := adminClaims(ctx, PermissionManageRoles)
+	if message != "" {
+		return &pb.RoleResponse{Success: false, Message: message}, nil
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return &pb.RoleResponse{Success: false, Message: "Role name is required"}, nil
+	}
+	role, err := s.roles.Create(ctx, req.Name, req.Description, req.Permission)
+	if err != nil {
+		return s.roleError(err, "Failed to create role")
+	}
+	s.auditRole(ctx, claims, "create_role", role.ID, bson.M{"name": role.Name, "permissions": role.Permissions})
+	return &pb.RoleResponse{
+		Success: true,
+		Message: "Role created successfully",
+		Role:    role.ToProto(),
+	}, nil
+}
+
+// UpdateRole replaces a role. Tokens already issued keep the old permissions
+// until they are refreshed.
+func (s *AuthService) UpdateRole(ctx context.Context, req *pb.UpdateRoleRequest) (*pb.RoleResponse, error) {
+	claims, message := adminClaims(ctx, PermissionManageRoles)
+	if message != "" {
+		return &pb.RoleResponse{Success: false, Message: message}, nil
+	}
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return &pb.RoleResponse{Success: false, Message: fmt.Sprintf("invalid id: %v", err)}, nil
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return &pb.RoleResponse{Success: false, Message: "Role name is required"}, nil
+	}
+	role, err := s.roles.Update(ctx, id, req.Name, req.Description, req.Permission)
+	if err != nil {
+		return s.roleError(err, "Failed to update role")
+	}
+	s.auditRole(ctx, claims, "update_role", role.ID, bson.M{"name": role.Name, "permissions": role.Permissions})
+	return &pb.RoleResponse{
+		Success: true,
+		Message: "Role updated successfully",
+		Role:    role.ToProto(),
+	}, nil
+}
+
+func (s *AuthService) DeleteRole(ctx context.Context, req *pb.DeleteRoleRequest) (*pb.DeleteRoleResponse, error) {
+	claims, message := adminClaims(ctx, PermissionManageRoles)
+	if message != "" {
+		return &pb.DeleteRoleResponse{Success: false, Message: message}, nil
+	}
+	id, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return &pb.DeleteRoleResponse{Success: false, Message: fmt.Sprintf("invalid id: %v", err)}, nil
+	}
+	if err := s.roles.Delete(ctx, id); err != nil {
+		if message := roleMessage(err); message != "" {
+			return &pb.DeleteRoleResponse{Success: false, Message: message}, nil
+		}
+		return nil, status.Error(codes.Internal, "Failed to delete role")
+	}
+	s.auditRole(ctx, claims, "delete_role", id, nil)
+	return &pb.DeleteRoleResponse{Success: true, Message: "Role deleted successfully"}, nil
+}
+
+// AssignRole sets the role of a user. The user's access tokens are revoked so
+// the next refresh picks up the new permissions.
+func (s *AuthService) AssignRole(ctx context.Context, req *pb.AssignRoleRequest) (*pb.AssignRoleResponse, error) {
+	claims, message := adminClaims(ctx, PermissionManageRoles)
+	if message != "" {
+		return &pb.AssignRoleResponse{Success: false, Message: message}, nil
+	}
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return &pb.AssignRoleResponse{Success: false, Message: fmt.Sprintf("invalid user_id: %v", err)}, nil
+	}
+	roleID, err := primitive.ObjectIDFromHex(req.RoleId)
+	if err != nil {
+		return &pb.AssignRoleResponse{Success: false, Message: fmt.Sprintf("invalid role_id: %v", err)}, nil
+	}
+	role, err := s.roles.Get(ctx, roleID)
+	if err != nil {
+		if message := roleMessage(err); message != "" {
+			return &pb.AssignRoleResponse{Success: false, Message: message}, nil
+		}
+		return nil, status.Error(codes.Internal, "Failed to assign role")
+	}
+
+	// Bỏ tên vai trò kiểu cũ để role_id là nguồn duy nhất
+	var user models.User
+	err = s.adapter.GetDatabase().Collection(models.UsersCollection).FindOneAndUpdate(ctx,
+		bson.M{"_id": userID},
+		bson.M{
+			"$set":   bson.M{"role_id": roleID, "updatedAt": time.Now()},
+			"$unset": bson.M{"role": "", "roles": ""},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return &pb.AssignRoleResponse{
+			Success: false,
+			Message: fmt.Sprintf("user %s not found", req.UserId),
+		}, nil
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "Failed to assign role")
+	}
+
+	if _, err := s.jwtManager.RevokeAll(ctx, req.UserId); err != nil {
+		log.Printf("Failed to revoke access tokens of user %s: %v", req.UserId, err)
+	}
+	err = audit.Log(ctx, s.adapter.GetDatabase(), audit.Entry{
+		UserID:     claims.UserID,
+		Action:     "assign_role",
+		EntityType: models.UsersCollection,
+		EntityID:   userID,
+		Details:    bson.M{"role_id": roleID, "role": role.Name},
+	})
+	if err != nil {
+		log.Printf("Failed to audit role assignment of user %s: %v", req.UserId, err)
+	}
+
+	return &pb.AssignRoleResponse{
+		Success: true,
+		Message: "Role assigned successfully",
+		User:    user.ToProto(role),
+	}, nil
+}
+
+// applyRole sets the role of a new user document by name: role_id when the
+// roles collection has it, the plain role name otherwise
+func (s *AuthService) applyRole(ctx context.Context, user bson.M, name string) error {
+	if name == "" {
+		return nil
+	}
+	role, err := s.roles.FindByName(ctx, name)
+	if err == utils.ErrRoleNotFound {
+		user["role"] = name
+		user["roles"] = name
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	user["role_id"] = role.ID
+	return nil
+}
+
+func (s *AuthService) roleError(err error, internal string) (*pb.RoleResponse, error) {
+	if message := roleMessage(err); message != "" {
+		return &pb.RoleResponse{Success: false, Message: message}, nil
+	}
+	return nil, status.Error(codes.Internal, internal)
+}
+
+func (s *AuthService) auditRole(ctx context.Context, claims *authn.Claims, action string, id primitive.ObjectID, details bson.M) {
+	err := audit.Log(ctx, s.adapter.GetDatabase(), audit.Entry{
+		UserID:     claims.UserID,
+		Action:     action,
+		EntityType: models.RolesCollection,
+		EntityID:   id,
+		Details:    details,
+	})
+	if err != nil {
+		log.Printf("Failed to audit %s of role %s: %v", action, id.Hex(), err)
+	}
+}
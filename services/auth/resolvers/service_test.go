@@ -0,0 +1,60 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"thaily/services/_common/authn"
+	"thaily/services/adapter"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newTestService is an AuthService on a throwaway database of the MongoDB at
+// MONGO_TEST_URI, the test is skipped when it is not set
+func newTestService(t *testing.T) *AuthService {
+	t.Helper()
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+	dbName := fmt.Sprintf("auth_test_%d", time.Now().UnixNano())
+	mongoAdapter, err := adapter.NewMongoDBAdapter(uri, dbName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		mongoAdapter.GetDatabase().Drop(context.Background())
+		mongoAdapter.Close()
+	})
+
+	service := NewAuthService(mongoAdapter, "test-secret", authn.NewMemoryRevocations())
+	if err := service.EnsureIndexes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return service
+}
+
+// insertUser creates an active user with email and the given extra fields
+func insertUser(t *testing.T, s *AuthService, email string, fields bson.M) primitive.ObjectID {
+	t.Helper()
+	id := primitive.NewObjectID()
+	user := bson.M{
+		"_id":       id,
+		"email":     email,
+		"status":    "active",
+		"createdAt": time.Now(),
+		"updatedAt": time.Now(),
+	}
+	for key, value := range fields {
+		user[key] = value
+	}
+	if _, err := s.adapter.GetDatabase().Collection("users").InsertOne(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
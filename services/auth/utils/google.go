@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"thaily/services/_common/authn"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const GoogleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+var googleIssuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
+// GoogleClaims are the claims of a Google ID token
+type GoogleClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+	// HostedDomain is the Google Workspace domain of the account
+	HostedDomain string `json:"hd"`
+	jwt.RegisteredClaims
+}
+
+type GoogleConfig struct {
+	// OAuth client ids the token may be issued to
+	ClientIDs []string
+	// Restricts sign-in to one Workspace domain, empty allows any account
+	HostedDomain string
+	JWKSURL      string
+	// Create an account on first sign-in instead of requiring an existing one
+	AutoProvision bool
+	// Role given to provisioned accounts
+	DefaultRole string
+}
+
+// GoogleVerifier checks Google ID tokens
+type GoogleVerifier struct {
+	config GoogleConfig
+	jwks   *authn.JWKSCache
+}
+
+func NewGoogleVerifier(config GoogleConfig) *GoogleVerifier {
+	if config.JWKSURL == "" {
+		config.JWKSURL = GoogleJWKSURL
+	}
+	return &GoogleVerifier{config: config, jwks: authn.NewJWKSCache(config.JWKSURL)}
+}
+
+func (g *GoogleVerifier) Config() GoogleConfig {
+	return g.config
+}
+
+// Verify checks the signature, aud, iss, exp and hosted domain of an ID token
+func (g *GoogleVerifier) Verify(ctx context.Context, idToken string) (*GoogleClaims, error) {
+	if len(g.config.ClientIDs) == 0 {
+		return nil, errors.New("google login is not configured")
+	}
+
+	token, err := jwt.ParseWithClaims(idToken, &GoogleClaims{},
+		func(token *jwt.Token) (interface{}, error) {
+			kid, _ := token.Header["kid"].(string)
+			return g.jwks.Key(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*GoogleClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if !slices.Contains(googleIssuers, claims.Issuer) {
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if !slices.ContainsFunc(claims.Audience, func(aud string) bool { return slices.Contains(g.config.ClientIDs, aud) }) {
+		return nil, errors.New("token was issued to another client")
+	}
+	if claims.Subject == "" || claims.Email == "" {
+		return nil, errors.New("token has no subject or email")
+	}
+	if !claims.EmailVerified {
+		return nil, errors.New("email is not verified")
+	}
+	if domain := g.config.HostedDomain; domain != "" && !strings.EqualFold(claims.HostedDomain, domain) {
+		return nil, fmt.Errorf("only %s accounts can sign in", domain)
+	}
+	return claims, nil
+}
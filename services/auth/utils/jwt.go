@@ -1,79 +1,104 @@
 package utils
 
 import (
-	"errors"
+	"context"
 	"time"
 
+	"thaily/services/_common/authn"
+
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-type JWTClaims struct {
-	UserID   string `json:"user_id"`
-	Email    string `json:"email"`
-	FullName string `json:"full_name"`
-	Roles    string `json:"roles"`
-	jwt.RegisteredClaims
-}
+// JWTClaims are shared with the other services through authn
+type JWTClaims = authn.Claims
 
+// JWTManager signs access tokens with HS256 and the shared secret, or with
+// the asymmetric keys of a KeyRing once UseKeyRing is called
 type JWTManager struct {
 	secretKey          string
+	keys               *KeyRing
 	accessTokenExpiry  time.Duration
 	refreshTokenExpiry time.Duration
+	verifier           *authn.Verifier
 }
 
-func NewJWTManager(secretKey string) *JWTManager {
+func NewJWTManager(secretKey string, revocations authn.Revocations) *JWTManager {
 	return &JWTManager{
 		secretKey:          secretKey,
 		accessTokenExpiry:  15 * time.Minute,
 		refreshTokenExpiry: 7 * 24 * time.Hour,
+		verifier:           authn.NewVerifier(secretKey, revocations),
 	}
 }
 
-func (j *JWTManager) GenerateAccessToken(userID, email, fullName, roles string) (string, error) {
-	claims := &JWTClaims{
-		UserID:   userID,
-		Email:    email,
-		FullName: fullName,
-		Roles:    roles,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.accessTokenExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+// GenerateAccessToken signs claims after setting a fresh jti, the lifetime
+// and the user's current token generation
+func (j *JWTManager) GenerateAccessToken(ctx context.Context, claims *JWTClaims) (string, error) {
+	if revocations := j.verifier.Revocations(); revocations != nil {
+		generation, err := revocations.Generation(ctx, claims.UserID)
+		if err != nil {
+			return "", err
+		}
+		claims.Generation = generation
+	}
+
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		ExpiresAt: jwt.NewNumericDate(now.Add(j.accessTokenExpiry)),
+		IssuedAt:  jwt.NewNumericDate(now),
 	}
 
+	if j.keys != nil {
+		return j.keys.Sign(ctx, claims)
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(j.secretKey))
 }
 
-func (j *JWTManager) GenerateRefreshToken(userID string) (string, error) {
-	claims := &JWTClaims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.refreshTokenExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+// UseKeyRing switches signing and verification to the keys of ring; tokens
+// signed with the shared secret are no longer accepted
+func (j *JWTManager) UseKeyRing(ring *KeyRing) {
+	j.keys = ring
+	j.verifier = authn.NewKeyVerifier(ring, j.verifier.Revocations())
+}
+
+// JWKS is the public key set of the signing keys, empty with HS256
+func (j *JWTManager) JWKS() authn.JWKS {
+	if j.keys == nil {
+		return authn.JWKS{Keys: []authn.JWK{}}
 	}
+	return j.keys.JWKS()
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.secretKey))
+// RefreshTokenExpiry is the lifetime of a refresh token, see RefreshStore
+func (j *JWTManager) RefreshTokenExpiry() time.Duration {
+	return j.refreshTokenExpiry
 }
 
-func (j *JWTManager) VerifyToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(j.secretKey), nil
-	})
+// Verifier is shared with the auth interceptor
+func (j *JWTManager) Verifier() *authn.Verifier {
+	return j.verifier
+}
 
-	if err != nil {
-		return nil, err
-	}
+// VerifyToken checks the signature, expiry and the revocation list
+func (j *JWTManager) VerifyToken(ctx context.Context, tokenString string) (*JWTClaims, error) {
+	return j.verifier.Verify(ctx, tokenString)
+}
 
-	claims, ok := token.Claims.(*JWTClaims)
-	if !ok || !token.Valid {
-		return nil, errors.New("invalid token")
+// Revoke denylists the jti of claims for the rest of its lifetime
+func (j *JWTManager) Revoke(ctx context.Context, claims *JWTClaims) error {
+	if claims.ID == "" || j.verifier.Revocations() == nil {
+		return nil
 	}
+	return j.verifier.Revocations().Revoke(ctx, claims.ID, claims.ExpiresIn())
+}
 
-	return claims, nil
+// RevokeAll rejects every token issued to the user so far
+func (j *JWTManager) RevokeAll(ctx context.Context, userID string) (int64, error) {
+	if j.verifier.Revocations() == nil {
+		return 0, nil
+	}
+	return j.verifier.Revocations().BumpGeneration(ctx, userID)
 }
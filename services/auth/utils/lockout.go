@@ -0,0 +1,310 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AttemptStore counts failed logins per key (an account or a client IP) and
+// keeps the temporary lockouts
+type AttemptStore interface {
+	// Fail records a failure of key and returns the failures counted since
+	// the first one of the current window
+	Fail(ctx context.Context, key string, window time.Duration) (int64, error)
+	Failures(ctx context.Context, key string) (int64, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	// LockedUntil is the end of the lockout of key, zero when not locked
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	// Reset clears the failures and the lockout of key
+	Reset(ctx context.Context, key string) error
+}
+
+const (
+	loginFailPrefix = "auth:login:fail:"
+	loginLockPrefix = "auth:login:lock:"
+)
+
+// RedisAttemptStore shares the counters between every service instance
+type RedisAttemptStore struct {
+	client *redis.Client
+}
+
+func NewRedisAttemptStore(redisAddr string, redisDB int) *RedisAttemptStore {
+	return &RedisAttemptStore{client: redis.NewClient(&redis.Options{Addr: redisAddr, DB: redisDB})}
+}
+
+func (r *RedisAttemptStore) Close() error {
+	return r.client.Close()
+}
+
+func (r *RedisAttemptStore) Fail(ctx context.Context, key string, window time.Duration) (int64, error) {
+	pipe := r.client.TxPipeline()
+	count := pipe.Incr(ctx, loginFailPrefix+key)
+	pipe.ExpireNX(ctx, loginFailPrefix+key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
+func (r *RedisAttemptStore) Failures(ctx context.Context, key string) (int64, error) {
+	count, err := r.client.Get(ctx, loginFailPrefix+key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+func (r *RedisAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+	return r.client.Set(ctx, loginLockPrefix+key, until.UnixMilli(), ttl).Err()
+}
+
+func (r *RedisAttemptStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	value, err := r.client.Get(ctx, loginLockPrefix+key).Result()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	millis, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(millis), nil
+}
+
+func (r *RedisAttemptStore) Reset(ctx context.Context, key string) error {
+	return r.client.Del(ctx, loginFailPrefix+key, loginLockPrefix+key).Err()
+}
+
+// MemoryAttemptStore keeps the counters in process, for development,
+// single-instance deployments without Redis and tests
+type MemoryAttemptStore struct {
+	mu       sync.Mutex
+	failures map[string]memoryFailures
+	locks    map[string]time.Time
+	// Now is the clock of the store, time.Now by default
+	Now func() time.Time
+}
+
+type memoryFailures struct {
+	count     int64
+	expiresAt time.Time
+}
+
+func NewMemoryAttemptStore() *MemoryAttemptStore {
+	return &MemoryAttemptStore{
+		failures: map[string]memoryFailures{},
+		locks:    map[string]time.Time{},
+		Now:      time.Now,
+	}
+}
+
+func (m *MemoryAttemptStore) Fail(ctx context.Context, key string, window time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.Now()
+	// Dọn các bộ đếm đã hết hạn để map không lớn dần
+	for k, failures := range m.failures {
+		if !now.Before(failures.expiresAt) {
+			delete(m.failures, k)
+		}
+	}
+	failures, ok := m.failures[key]
+	if !ok {
+		failures.expiresAt = now.Add(window)
+	}
+	failures.count++
+	m.failures[key] = failures
+	return failures.count, nil
+}
+
+func (m *MemoryAttemptStore) Failures(ctx context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	failures, ok := m.failures[key]
+	if !ok || !m.Now().Before(failures.expiresAt) {
+		return 0, nil
+	}
+	return failures.count, nil
+}
+
+func (m *MemoryAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.locks[key] = until
+	return nil
+}
+
+func (m *MemoryAttemptStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	until, ok := m.locks[key]
+	if !ok {
+		return time.Time{}, nil
+	}
+	if !m.Now().Before(until) {
+		delete(m.locks, key)
+		return time.Time{}, nil
+	}
+	return until, nil
+}
+
+func (m *MemoryAttemptStore) Reset(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.failures, key)
+	delete(m.locks, key)
+	return nil
+}
+
+// LockoutConfig sets the thresholds of LoginGuard
+type LockoutConfig struct {
+	// Failures of one account, and of one client IP, that lock it out
+	MaxAccountFailures int64
+	MaxIPFailures      int64
+	// Failures are counted over Window, a lockout lasts LockDuration
+	Window       time.Duration
+	LockDuration time.Duration
+	// From DelayAfter failures on, each attempt waits BaseDelay, doubled per
+	// further failure up to MaxDelay
+	DelayAfter int64
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+func DefaultLockoutConfig() LockoutConfig {
+	return LockoutConfig{
+		MaxAccountFailures: 5,
+		MaxIPFailures:      20,
+		Window:             15 * time.Minute,
+		LockDuration:       15 * time.Minute,
+		DelayAfter:         2,
+		BaseDelay:          500 * time.Millisecond,
+		MaxDelay:           8 * time.Second,
+	}
+}
+
+// Lockout is a lockout started by a failed attempt
+type Lockout struct {
+	// Subject is the account email or the IP that got locked
+	Subject  string
+	IsIP     bool
+	Failures int64
+	Until    time.Time
+}
+
+// LoginGuard applies the lockout policy. Accounts are keyed by normalized
+// email so unknown emails behave exactly like existing ones.
+type LoginGuard struct {
+	store  AttemptStore
+	config LockoutConfig
+	// Now is the clock lockouts start from, time.Now by default
+	Now func() time.Time
+}
+
+func NewLoginGuard(store AttemptStore, config LockoutConfig) *LoginGuard {
+	return &LoginGuard{store: store, config: config, Now: time.Now}
+}
+
+// LockedUntil returns the end of the account's or the IP's lockout, the
+// later of both, zero when neither is locked
+func (g *LoginGuard) LockedUntil(ctx context.Context, account, ip string) (time.Time, error) {
+	until, err := g.store.LockedUntil(ctx, accountKey(account))
+	if err != nil || ip == "" {
+		return until, err
+	}
+	ipUntil, err := g.store.LockedUntil(ctx, ipKey(ip))
+	if ipUntil.After(until) {
+		until = ipUntil
+	}
+	return until, err
+}
+
+// Delay is how long to wait before checking the password, growing with the
+// recent failures of the account or the IP
+func (g *LoginGuard) Delay(ctx context.Context, account, ip string) (time.Duration, error) {
+	failures, err := g.store.Failures(ctx, accountKey(account))
+	if err != nil {
+		return 0, err
+	}
+	if ip != "" {
+		ipFailures, err := g.store.Failures(ctx, ipKey(ip))
+		if err != nil {
+			return 0, err
+		}
+		// IP dùng chung (NAT) có nhiều lần sai hơn, chia theo ngưỡng của nó
+		if scaled := ipFailures * g.config.MaxAccountFailures / max(g.config.MaxIPFailures, 1); scaled > failures {
+			failures = scaled
+		}
+	}
+	if g.config.BaseDelay <= 0 || failures < g.config.DelayAfter {
+		return 0, nil
+	}
+	delay := g.config.BaseDelay
+	for i := g.config.DelayAfter; i < failures && delay < g.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, g.config.MaxDelay), nil
+}
+
+// Fail records a failed attempt and returns the lockouts it started. Failures
+// keep counting until the window ends, so a failure right after a lockout
+// shorter than the window locks again.
+func (g *LoginGuard) Fail(ctx context.Context, account, ip string) ([]Lockout, error) {
+	var lockouts []Lockout
+	record := func(key, subject string, isIP bool, limit int64) error {
+		failures, err := g.store.Fail(ctx, key, g.config.Window)
+		if err != nil || limit <= 0 || failures < limit {
+			return err
+		}
+		until := g.Now().Add(g.config.LockDuration)
+		if err := g.store.Lock(ctx, key, until); err != nil {
+			return err
+		}
+		lockouts = append(lockouts, Lockout{Subject: subject, IsIP: isIP, Failures: failures, Until: until})
+		return nil
+	}
+	if err := record(accountKey(account), account, false, g.config.MaxAccountFailures); err != nil {
+		return lockouts, err
+	}
+	if ip != "" {
+		if err := record(ipKey(ip), ip, true, g.config.MaxIPFailures); err != nil {
+			return lockouts, err
+		}
+	}
+	return lockouts, nil
+}
+
+// Succeed clears the failures of an account after a correct password. The IP
+// keeps its count, one valid account must not hide guesses on others.
+func (g *LoginGuard) Succeed(ctx context.Context, account string) error {
+	return g.store.Reset(ctx, accountKey(account))
+}
+
+func (g *LoginGuard) UnlockAccount(ctx context.Context, account string) error {
+	return g.store.Reset(ctx, accountKey(account))
+}
+
+func (g *LoginGuard) UnlockIP(ctx context.Context, ip string) error {
+	return g.store.Reset(ctx, ipKey(ip))
+}
+
+func accountKey(account string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(account))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,234 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock is the clock of both the store and the guard
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestGuard() (*LoginGuard, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)}
+	store := NewMemoryAttemptStore()
+	store.Now = clock.Now
+	guard := NewLoginGuard(store, DefaultLockoutConfig())
+	guard.Now = clock.Now
+	return guard, clock
+}
+
+func TestLoginGuardDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{1, 0},
+		{2, 500 * time.Millisecond},
+		{3, time.Second},
+		{4, 2 * time.Second},
+		{5, 4 * time.Second},
+		{6, 8 * time.Second},
+		{10, 8 * time.Second},
+	}
+
+	for _, tt := range tests {
+		guard, _ := newTestGuard()
+		ctx := context.Background()
+		for i := 0; i < tt.failures; i++ {
+			if _, err := guard.Fail(ctx, "student@example.edu", ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := guard.Delay(ctx, "student@example.edu", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Delay() after %d failures = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestLoginGuardLockout(t *testing.T) {
+	const (
+		account = "student@example.edu"
+		ip      = "203.0.113.7"
+	)
+	type step struct {
+		// fail records failures of account from ip, other actions are applied
+		// before them
+		fail    int
+		advance time.Duration
+		succeed bool
+		unlock  bool
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+		// wantLocked is the lockout of the account, as time from the start
+		wantLocked time.Duration
+		wantDelay  time.Duration
+	}{
+		{
+			name:      "below the threshold",
+			steps:     []step{{fail: 4}},
+			wantDelay: 2 * time.Second,
+		},
+		{
+			name:       "locked at the fifth failure",
+			steps:      []step{{fail: 5}},
+			wantLocked: 15 * time.Minute,
+			wantDelay:  4 * time.Second,
+		},
+		{
+			name:       "failure right after a lockout locks again",
+			steps:      []step{{fail: 5}, {advance: 14 * time.Minute, fail: 1}},
+			wantLocked: 29 * time.Minute,
+			wantDelay:  8 * time.Second,
+		},
+		{
+			name:  "lockout and window end",
+			steps: []step{{fail: 5}, {advance: 15 * time.Minute}},
+		},
+		{
+			name:      "failures of an expired window are forgotten",
+			steps:     []step{{fail: 4}, {advance: 15 * time.Minute, fail: 1}},
+			wantDelay: 0,
+		},
+		{
+			name:  "correct password resets the account",
+			steps: []step{{fail: 4}, {succeed: true}},
+		},
+		{
+			name:  "unlocked by an admin",
+			steps: []step{{fail: 5}, {unlock: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard, clock := newTestGuard()
+			start := clock.now
+			ctx := context.Background()
+
+			for _, s := range tt.steps {
+				clock.now = clock.now.Add(s.advance)
+				if s.succeed {
+					if err := guard.Succeed(ctx, account); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if s.unlock {
+					if err := guard.UnlockAccount(ctx, account); err != nil {
+						t.Fatal(err)
+					}
+				}
+				for i := 0; i < s.fail; i++ {
+					if _, err := guard.Fail(ctx, account, ip); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			until, err := guard.LockedUntil(ctx, account, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want time.Time
+			if tt.wantLocked > 0 {
+				want = start.Add(tt.wantLocked)
+			}
+			if !until.Equal(want) {
+				t.Errorf("LockedUntil() = %v, want %v", until, want)
+			}
+			delay, err := guard.Delay(ctx, account, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if delay != tt.wantDelay {
+				t.Errorf("Delay() = %v, want %v", delay, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestLoginGuardFailReturnsLockout(t *testing.T) {
+	guard, clock := newTestGuard()
+	ctx := context.Background()
+
+	for i := 1; i <= 5; i++ {
+		// Email được chuẩn hóa nên các biến thể đếm chung
+		lockouts, err := guard.Fail(ctx, " Student@Example.edu", "203.0.113.7")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i < 5 {
+			if len(lockouts) != 0 {
+				t.Fatalf("failure %d locked %v", i, lockouts)
+			}
+			continue
+		}
+		want := Lockout{Subject: " Student@Example.edu", Failures: 5, Until: clock.now.Add(15 * time.Minute)}
+		if len(lockouts) != 1 || lockouts[0] != want {
+			t.Fatalf("failure 5 locked %v, want %v", lockouts, want)
+		}
+	}
+	if until, _ := guard.LockedUntil(ctx, "student@example.edu", ""); until.IsZero() {
+		t.Error("the normalized email is not locked")
+	}
+}
+
+func TestLoginGuardIP(t *testing.T) {
+	guard, clock := newTestGuard()
+	ctx := context.Background()
+	const ip = "203.0.113.7"
+
+	// Mỗi tài khoản sai dưới ngưỡng, nhưng IP thì vượt
+	var lockouts []Lockout
+	for i := 0; i < 20; i++ {
+		account := string(rune('a'+i)) + "@example.edu"
+		got, err := guard.Fail(ctx, account, ip)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lockouts = append(lockouts, got...)
+	}
+	want := Lockout{Subject: ip, IsIP: true, Failures: 20, Until: clock.now.Add(15 * time.Minute)}
+	if len(lockouts) != 1 || lockouts[0] != want {
+		t.Fatalf("lockouts = %v, want %v", lockouts, want)
+	}
+
+	// Tài khoản mới từ IP bị khóa cũng bị chặn và bị làm chậm
+	if until, _ := guard.LockedUntil(ctx, "fresh@example.edu", ip); !until.Equal(want.Until) {
+		t.Errorf("LockedUntil() from the locked IP = %v, want %v", until, want.Until)
+	}
+	// 20 lần sai của IP tính như 5 lần sai của một tài khoản
+	if delay, _ := guard.Delay(ctx, "fresh@example.edu", ip); delay != 4*time.Second {
+		t.Errorf("Delay() from the IP = %v, want 4s", delay)
+	}
+
+	// Đăng nhập đúng không xóa bộ đếm của IP
+	if err := guard.Succeed(ctx, "a@example.edu"); err != nil {
+		t.Fatal(err)
+	}
+	if until, _ := guard.LockedUntil(ctx, "a@example.edu", ip); until.IsZero() {
+		t.Error("a correct password cleared the IP lockout")
+	}
+
+	if err := guard.UnlockIP(ctx, ip); err != nil {
+		t.Fatal(err)
+	}
+	if until, _ := guard.LockedUntil(ctx, "fresh@example.edu", ip); !until.IsZero() {
+		t.Errorf("LockedUntil() after UnlockIP = %v, want zero", until)
+	}
+	if delay, _ := guard.Delay(ctx, "fresh@example.edu", ip); delay != 0 {
+		t.Errorf("Delay() after UnlockIP = %v, want 0", delay)
+	}
+}
@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// OIDCProviderConfig describes one OpenID Connect provider, e.g. the
+// university Keycloak realm. Providers are loaded from a JSON file holding a
+// list of these objects.
+type OIDCProviderConfig struct {
+	// Name identifies the provider in RPCs and in users.identities
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	// Issuer URL, the discovery document is read from
+	// <issuer>/.well-known/openid-configuration
+	Issuer       string `json:"issuer"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	// Registered redirect URLs, the first one is the default
+	RedirectURLs []string `json:"redirect_urls"`
+	Scopes       []string `json:"scopes"`
+	// Claims maps users fields to claim paths, e.g. {"code": "preferred_username",
+	// "major": "department"}; nested claims use dots
+	Claims map[string]string `json:"claims"`
+	// RoleRules are tried in order, the first match gives the role
+	RoleRules   []RoleRule `json:"role_rules"`
+	DefaultRole string     `json:"default_role"`
+	// Create an account on first sign-in instead of requiring an existing one
+	AutoProvision bool `json:"auto_provision"`
+}
+
+// RoleRule gives Role to users whose claim equals Value, or contains it when
+// the claim is a list (e.g. Keycloak realm_access.roles)
+type RoleRule struct {
+	Claim string `json:"claim"`
+	Value string `json:"value"`
+	Role  string `json:"role"`
+}
+
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// LoadOIDCConfig reads the provider list from a JSON file
+func LoadOIDCConfig(path string) ([]OIDCProviderConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var configs []OIDCProviderConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("invalid OIDC config %s: %w", path, err)
+	}
+
+	seen := map[string]bool{}
+	for i, config := range configs {
+		if !providerNamePattern.MatchString(config.Name) {
+			return nil, fmt.Errorf("OIDC provider %d: name must match %s", i, providerNamePattern)
+		}
+		if config.Name == "google" || seen[config.Name] {
+			return nil, fmt.Errorf("OIDC provider %q is defined twice", config.Name)
+		}
+		seen[config.Name] = true
+		if config.Issuer == "" || config.ClientID == "" || len(config.RedirectURLs) == 0 {
+			return nil, fmt.Errorf("OIDC provider %q needs issuer, client_id and redirect_urls", config.Name)
+		}
+		if len(config.Scopes) == 0 {
+			configs[i].Scopes = []string{"openid", "email", "profile"}
+		}
+		if configs[i].DisplayName == "" {
+			configs[i].DisplayName = config.Name
+		}
+	}
+	return configs, nil
+}
+
+// Claim looks up a dotted claim path
+func Claim(claims map[string]interface{}, path string) interface{} {
+	var value interface{} = claims
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
+
+// ClaimString returns a claim as a string, empty when missing or not scalar
+func ClaimString(claims map[string]interface{}, path string) string {
+	switch value := Claim(claims, path).(type) {
+	case string:
+		return value
+	case float64, bool:
+		return fmt.Sprint(value)
+	}
+	return ""
+}
+
+// MapFields returns the users fields configured in Claims
+func (c OIDCProviderConfig) MapFields(claims map[string]interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for field, path := range c.Claims {
+		if value := ClaimString(claims, path); value != "" {
+			fields[field] = value
+		}
+	}
+	return fields
+}
+
+// MapRole applies the role rules, falling back to DefaultRole
+func (c OIDCProviderConfig) MapRole(claims map[string]interface{}) string {
+	for _, rule := range c.RoleRules {
+		switch value := Claim(claims, rule.Claim).(type) {
+		case string:
+			if strings.EqualFold(value, rule.Value) {
+				return rule.Role
+			}
+		case []interface{}:
+			for _, item := range value {
+				if s, ok := item.(string); ok && strings.EqualFold(s, rule.Value) {
+					return rule.Role
+				}
+			}
+		}
+	}
+	return c.DefaultRole
+}
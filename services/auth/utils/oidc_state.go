@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const OIDCStatesCollection = "oidc_states"
+
+var ErrInvalidState = errors.New("invalid or expired login state")
+
+// OIDCState is a pending authorization request, kept server-side so the
+// client only ever sees the state value
+type OIDCState struct {
+	State        string    `bson:"_id"`
+	Provider     string    `bson:"provider"`
+	Nonce        string    `bson:"nonce"`
+	CodeVerifier string    `bson:"code_verifier"`
+	RedirectURL  string    `bson:"redirect_url"`
+	CreatedAt    time.Time `bson:"createdAt"`
+	ExpiresAt    time.Time `bson:"expires_at"`
+}
+
+type OIDCStateStore struct {
+	collection *mongo.Collection
+	ttl        time.Duration
+}
+
+func NewOIDCStateStore(db *mongo.Database) *OIDCStateStore {
+	return &OIDCStateStore{collection: db.Collection(OIDCStatesCollection), ttl: 10 * time.Minute}
+}
+
+// EnsureIndexes creates the TTL index that drops abandoned logins
+func (s *OIDCStateStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// Create stores a new pending login and returns it
+func (s *OIDCStateStore) Create(ctx context.Context, provider, redirectURL string) (*OIDCState, error) {
+	var values [3]string
+	for i := range values {
+		value, err := RandomToken(32)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	now := time.Now()
+	state := &OIDCState{
+		State:        values[0],
+		Provider:     provider,
+		Nonce:        values[1],
+		CodeVerifier: values[2],
+		RedirectURL:  redirectURL,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(s.ttl),
+	}
+	if _, err := s.collection.InsertOne(ctx, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Consume returns and deletes a pending login, each state is single-use
+func (s *OIDCStateStore) Consume(ctx context.Context, provider, state string) (*OIDCState, error) {
+	var pending OIDCState
+	err := s.collection.FindOneAndDelete(ctx, bson.M{
+		"_id":        state,
+		"provider":   provider,
+		"expires_at": bson.M{"$gt": time.Now()},
+	}).Decode(&pending)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrInvalidState
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
@@ -0,0 +1,281 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"thaily/services/_common/authn"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID     = "thesis-portal"
+	testClientSecret = "client-secret"
+	testKID          = "provider-key"
+)
+
+// fakeProvider is an OIDC provider serving discovery, JWKS and a token
+// endpoint that answers with IDToken
+type fakeProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	// IDToken is returned by the token endpoint
+	IDToken string
+	// Form is the last token request
+	Form url.Values
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(OIDCDiscovery{
+			Issuer:                f.server.URL,
+			AuthorizationEndpoint: f.server.URL + "/authorize",
+			TokenEndpoint:         f.server.URL + "/token",
+			JWKSURI:               f.server.URL + "/jwks",
+			// Nhà cung cấp khai cả HS256 và none, verifier vẫn phải từ chối
+			SigningAlgs: []string{"RS256", "HS256", "none"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		jwk, err := authn.NewJWK(testKID, "RS256", &f.key.PublicKey)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(authn.JWKS{Keys: []authn.JWK{jwk}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		f.Form = r.PostForm
+		json.NewEncoder(w).Encode(map[string]string{"id_token": f.IDToken})
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeProvider) Provider() *OIDCProvider {
+	return NewOIDCProvider(OIDCProviderConfig{
+		Name:         "keycloak",
+		Issuer:       f.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURLs: []string{"http://localhost:3000/sso/callback"},
+		Scopes:       []string{"openid", "email"},
+	})
+}
+
+// Claims are valid ID token claims for nonce
+func (f *fakeProvider) Claims(nonce string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            f.server.URL,
+		"aud":            testClientID,
+		"sub":            "user-1",
+		"email":          "student@example.edu",
+		"email_verified": true,
+		"nonce":          nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	}
+}
+
+// Sign signs claims with the provider key
+func (f *fakeProvider) Sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testKID
+	signed, err := token.SignedString(f.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestOIDCExchange(t *testing.T) {
+	const nonce = "nonce-1"
+
+	tests := []struct {
+		name    string
+		idToken func(t *testing.T, f *fakeProvider) string
+		wantErr string
+	}{
+		{
+			name: "valid token",
+			idToken: func(t *testing.T, f *fakeProvider) string {
+				return f.Sign(t, f.Claims(nonce))
+			},
+		},
+		{
+			name: "nonce mismatch",
+			idToken: func(t *testing.T, f *fakeProvider) string {
+				return f.Sign(t, f.Claims("nonce-of-another-login"))
+			},
+			wantErr: "nonce does not match",
+		},
+		{
+			name: "missing nonce",
+			idToken: func(t *testing.T, f *fakeProvider) string {
+				claims := f.Claims(nonce)
+				delete(claims, "nonce")
+				return f.Sign(t, claims)
+			},
+			wantErr: "nonce does not match",
+		},
+		{
+			name: "wrong audience",
+			idToken: func(t *testing.T, f *fakeProvider) string {
+				claims := f.Claims(nonce)
+				claims["aud"] = "another-client"
+				return f.Sign(t, claims)
+			},
+			wantErr: "audience",
+		},
+		{
+			name: "wrong issuer",
+			idToken: func(t *testing.T, f *fakeProvider) string {
+				claims := f.Claims(nonce)
+				claims["iss"] = "https://evil.example.com"
+				return f.Sign(t, claims)
+			},
+			wantErr: "issuer",
+		},
+		{
+			name: "expired",
+			idToken: func(t *testing.T, f *fakeProvider) string {
+				claims := f.Claims(nonce)
+				claims["exp"] = time.Now().Add(-time.Minute).Unix()
+				return f.Sign(t, claims)
+			},
+			wantErr: "expired",
+		},
+		{
+			name: "HS256 signed with the client secret",
+			idToken: func(t *testing.T, f *fakeProvider) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, f.Claims(nonce))
+				token.Header["kid"] = testKID
+				signed, err := token.SignedString([]byte(testClientSecret))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: "signing method",
+		},
+		{
+			name: "alg none",
+			idToken: func(t *testing.T, f *fakeProvider) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodNone, f.Claims(nonce))
+				signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: "signing method",
+		},
+		{
+			name: "signed by another key",
+			idToken: func(t *testing.T, f *fakeProvider) string {
+				other, err := rsa.GenerateKey(rand.Reader, 2048)
+				if err != nil {
+					t.Fatal(err)
+				}
+				token := jwt.NewWithClaims(jwt.SigningMethodRS256, f.Claims(nonce))
+				token.Header["kid"] = testKID
+				signed, err := token.SignedString(other)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return signed
+			},
+			wantErr: "verification error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeProvider(t)
+			f.IDToken = tt.idToken(t, f)
+
+			claims, err := f.Provider().Exchange(context.Background(), "code-1", "http://localhost:3000/sso/callback", "verifier-1", nonce)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Exchange() error = %v", err)
+				}
+				if claims["sub"] != "user-1" {
+					t.Errorf("sub = %v, want user-1", claims["sub"])
+				}
+				if got := f.Form.Get("code_verifier"); got != "verifier-1" {
+					t.Errorf("code_verifier = %q, want verifier-1", got)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Exchange() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOIDCDiscoveryIssuerMismatch(t *testing.T) {
+	f := newFakeProvider(t)
+	provider := NewOIDCProvider(OIDCProviderConfig{
+		Name:         "keycloak",
+		Issuer:       f.server.URL + "/",
+		ClientID:     testClientID,
+		RedirectURLs: []string{"http://localhost:3000/sso/callback"},
+	})
+	// Discovery được đọc từ issuer đã cấu hình nhưng khai issuer khác
+	if _, err := provider.Discover(context.Background()); err == nil {
+		t.Fatal("Discover() accepted a discovery document for another issuer")
+	}
+}
+
+func TestOIDCAuthCodeURL(t *testing.T) {
+	f := newFakeProvider(t)
+	raw, err := f.Provider().AuthCodeURL(context.Background(), "http://localhost:3000/sso/callback", "state-1", "nonce-1", "verifier-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	sum := sha256.Sum256([]byte("verifier-1"))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	for key, want := range map[string]string{
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"client_id":             testClientID,
+		"code_challenge_method": "S256",
+		"code_challenge":        challenge,
+	} {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	if query.Has("code_verifier") {
+		t.Error("the PKCE verifier must not leave the server")
+	}
+}
@@ -1,19 +1,14 @@
 package utils
 
-import (
-	"golang.org/x/crypto/bcrypt"
-)
+import "thaily/services/_common/password"
 
-func HashPassword(password string) (string, error) {
-	return password, nil
-	// bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	// if err != nil {
-	// 	return "", err
-	// }
-	// return string(bytes), nil
+// HashPassword hashes with the configured default hasher
+func HashPassword(plain string) (string, error) {
+	return password.Default().Hash(plain)
 }
 
-func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+// CheckPasswordHash verifies a password against any supported stored format
+func CheckPasswordHash(plain, hash string) bool {
+	ok, _ := password.Default().Verify(plain, hash)
+	return ok
 }
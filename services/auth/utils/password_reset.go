@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const PasswordResetsCollection = "password_resets"
+
+// PasswordReset is a pending reset, only the SHA-256 of the token is stored
+type PasswordReset struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	TokenHash   string             `bson:"token_hash"`
+	UserID      string             `bson:"user_id"`
+	RequestedIP string             `bson:"requested_ip,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt"`
+	ExpiresAt   time.Time          `bson:"expires_at"`
+	UsedAt      *time.Time         `bson:"used_at,omitempty"`
+}
+
+// PasswordResetStore issues single-use reset tokens
+type PasswordResetStore struct {
+	collection *mongo.Collection
+}
+
+func NewPasswordResetStore(db *mongo.Database) *PasswordResetStore {
+	return &PasswordResetStore{collection: db.Collection(PasswordResetsCollection)}
+}
+
+func (s *PasswordResetStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+// Create issues a reset token and invalidates the earlier ones of the user,
+// so only the latest email works
+func (s *PasswordResetStore) Create(ctx context.Context, userID, ip string, ttl time.Duration) (string, error) {
+	if err := s.InvalidateUser(ctx, userID); err != nil {
+		return "", err
+	}
+	token, err := RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	_, err = s.collection.InsertOne(ctx, PasswordReset{
+		TokenHash:   hashToken(token),
+		UserID:      userID,
+		RequestedIP: ip,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Consume marks token used and returns the user it was issued to
+func (s *PasswordResetStore) Consume(ctx context.Context, token string) (string, error) {
+	now := time.Now()
+	var reset PasswordReset
+	err := s.collection.FindOneAndUpdate(ctx,
+		bson.M{
+			"token_hash": hashToken(token),
+			"used_at":    nil,
+			"expires_at": bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{"used_at": now}},
+	).Decode(&reset)
+	if err == mongo.ErrNoDocuments {
+		return "", ErrInvalidLink
+	}
+	if err != nil {
+		return "", err
+	}
+	return reset.UserID, nil
+}
+
+// InvalidateUser marks every outstanding token of a user as used
+func (s *PasswordResetStore) InvalidateUser(ctx context.Context, userID string) error {
+	_, err := s.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "used_at": nil},
+		bson.M{"$set": bson.M{"used_at": time.Now()}},
+	)
+	return err
+}
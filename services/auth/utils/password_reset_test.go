@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// testDatabase is a throwaway database on the MongoDB at MONGO_TEST_URI, the
+// test is skipped when it is not set
+func testDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := client.Database(fmt.Sprintf("auth_test_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		db.Drop(context.Background())
+		client.Disconnect(context.Background())
+	})
+	return db
+}
+
+func TestPasswordResetStore(t *testing.T) {
+	tests := []struct {
+		name string
+		// issue creates the tokens of a scenario and returns the one to consume
+		issue    func(t *testing.T, s *PasswordResetStore) string
+		wantUser string
+	}{
+		{
+			name: "fresh token",
+			issue: func(t *testing.T, s *PasswordResetStore) string {
+				return createReset(t, s, "user-1", time.Hour)
+			},
+			wantUser: "user-1",
+		},
+		{
+			name: "token used twice",
+			issue: func(t *testing.T, s *PasswordResetStore) string {
+				token := createReset(t, s, "user-1", time.Hour)
+				if _, err := s.Consume(context.Background(), token); err != nil {
+					t.Fatal(err)
+				}
+				return token
+			},
+		},
+		{
+			name: "expired token",
+			issue: func(t *testing.T, s *PasswordResetStore) string {
+				return createReset(t, s, "user-1", -time.Second)
+			},
+		},
+		{
+			name: "replaced by a newer token",
+			issue: func(t *testing.T, s *PasswordResetStore) string {
+				token := createReset(t, s, "user-1", time.Hour)
+				createReset(t, s, "user-1", time.Hour)
+				return token
+			},
+		},
+		{
+			name: "newer token of another user",
+			issue: func(t *testing.T, s *PasswordResetStore) string {
+				token := createReset(t, s, "user-1", time.Hour)
+				createReset(t, s, "user-2", time.Hour)
+				return token
+			},
+			wantUser: "user-1",
+		},
+		{
+			name: "invalidated after a password change",
+			issue: func(t *testing.T, s *PasswordResetStore) string {
+				token := createReset(t, s, "user-1", time.Hour)
+				if err := s.InvalidateUser(context.Background(), "user-1"); err != nil {
+					t.Fatal(err)
+				}
+				return token
+			},
+		},
+		{
+			name: "unknown token",
+			issue: func(t *testing.T, s *PasswordResetStore) string {
+				return "not-a-token"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewPasswordResetStore(testDatabase(t))
+			if err := s.EnsureIndexes(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			userID, err := s.Consume(context.Background(), tt.issue(t, s))
+			if tt.wantUser == "" {
+				if err != ErrInvalidLink {
+					t.Fatalf("Consume() = %q, %v, want ErrInvalidLink", userID, err)
+				}
+				return
+			}
+			if err != nil || userID != tt.wantUser {
+				t.Fatalf("Consume() = %q, %v, want %q", userID, err, tt.wantUser)
+			}
+		})
+	}
+}
+
+func createReset(t *testing.T, s *PasswordResetStore, userID string, ttl time.Duration) string {
+	t.Helper()
+	token, err := s.Create(context.Background(), userID, "203.0.113.7", ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
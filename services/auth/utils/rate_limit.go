@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const RateLimitsCollection = "auth_rate_limits"
+
+// RateLimiter counts requests per key in fixed windows stored in Mongo, so
+// limits hold across service instances
+type RateLimiter struct {
+	collection *mongo.Collection
+}
+
+func NewRateLimiter(db *mongo.Database) *RateLimiter {
+	return &RateLimiter{collection: db.Collection(RateLimitsCollection)}
+}
+
+// EnsureIndexes creates the TTL index that drops finished windows
+func (r *RateLimiter) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// Allow counts one request for key and reports whether it stays within limit
+// requests per window
+func (r *RateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	start := time.Now().Truncate(window)
+	var bucket struct {
+		Count int `bson:"count"`
+	}
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": key + "@" + strconv.FormatInt(start.Unix(), 10)},
+		bson.M{
+			"$inc":         bson.M{"count": 1},
+			"$setOnInsert": bson.M{"expires_at": start.Add(window)},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&bucket)
+	if err != nil {
+		return false, err
+	}
+	return bucket.Count <= limit, nil
+}
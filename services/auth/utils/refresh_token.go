@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const RefreshTokensCollection = "refresh_tokens"
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused means an already rotated token was presented again,
+	// its whole family has been revoked
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
+// RefreshToken is a stored refresh token. Only the SHA-256 of the token is
+// kept; every token issued from one login shares a FamilyID.
+type RefreshToken struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty"`
+	TokenHash     string              `bson:"token_hash"`
+	UserID        string              `bson:"user_id"`
+	FamilyID      string              `bson:"family_id"`
+	ParentID      *primitive.ObjectID `bson:"parent_id,omitempty"`
+	ReplacedBy    *primitive.ObjectID `bson:"replaced_by,omitempty"`
+	CreatedAt     time.Time           `bson:"createdAt"`
+	ExpiresAt     time.Time           `bson:"expires_at"`
+	UsedAt        *time.Time          `bson:"used_at,omitempty"`
+	RevokedAt     *time.Time          `bson:"revoked_at,omitempty"`
+	RevokedReason string              `bson:"revoked_reason,omitempty"`
+}
+
+// RefreshStore issues single-use refresh tokens
+type RefreshStore struct {
+	collection *mongo.Collection
+	ttl        time.Duration
+}
+
+func NewRefreshStore(db *mongo.Database, ttl time.Duration) *RefreshStore {
+	return &RefreshStore{collection: db.Collection(RefreshTokensCollection), ttl: ttl}
+}
+
+// EnsureIndexes creates the lookup indexes and the TTL index that drops
+// tokens once they expire
+func (s *RefreshStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "family_id", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+// Issue creates the first token of a new family and returns the token and
+// the family id
+func (s *RefreshStore) Issue(ctx context.Context, userID string) (token, familyID string, err error) {
+	familyID = primitive.NewObjectID().Hex()
+	token, _, err = s.insert(ctx, userID, familyID, nil)
+	return token, familyID, err
+}
+
+// Rotate consumes token and returns it with its successor in the same
+// family. On ErrRefreshTokenReused the reused token is returned.
+func (s *RefreshStore) Rotate(ctx context.Context, token string) (*RefreshToken, string, error) {
+	hash := hashToken(token)
+	now := time.Now()
+
+	var current RefreshToken
+	err := s.collection.FindOneAndUpdate(ctx,
+		bson.M{
+			"token_hash": hash,
+			"used_at":    nil,
+			"revoked_at": nil,
+			"expires_at": bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{"used_at": now}},
+	).Decode(&current)
+	if err == mongo.ErrNoDocuments {
+		reused, err := s.rejected(ctx, hash)
+		return reused, "", err
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	next, nextID, err := s.insert(ctx, current.UserID, current.FamilyID, &current.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := s.collection.UpdateByID(ctx, current.ID, bson.M{"$set": bson.M{"replaced_by": nextID}}); err != nil {
+		return nil, "", err
+	}
+	return &current, next, nil
+}
+
+// rejected explains why token could not be rotated. A token that was already
+// used means it leaked: the legitimate holder and the attacker both have a
+// copy, so the whole family is revoked.
+func (s *RefreshStore) rejected(ctx context.Context, hash string) (*RefreshToken, error) {
+	var stored RefreshToken
+	err := s.collection.FindOne(ctx, bson.M{"token_hash": hash}).Decode(&stored)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stored.UsedAt == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err := s.RevokeFamily(ctx, stored.FamilyID, "reuse_detected"); err != nil {
+		return nil, err
+	}
+	return &stored, ErrRefreshTokenReused
+}
+
+// RevokeFamily revokes every token issued from one login
+func (s *RefreshStore) RevokeFamily(ctx context.Context, familyID, reason string) error {
+	return s.revoke(ctx, bson.M{"family_id": familyID}, reason)
+}
+
+// RevokeUser revokes every refresh token of a user
+func (s *RefreshStore) RevokeUser(ctx context.Context, userID, reason string) error {
+	return s.revoke(ctx, bson.M{"user_id": userID}, reason)
+}
+
+// RevokeOtherSessions revokes the refresh tokens of a user except those of
+// the family keepFamilyID, the session making the request
+func (s *RefreshStore) RevokeOtherSessions(ctx context.Context, userID, keepFamilyID, reason string) error {
+	return s.revoke(ctx, bson.M{"user_id": userID, "family_id": bson.M{"$ne": keepFamilyID}}, reason)
+}
+
+func (s *RefreshStore) revoke(ctx context.Context, filter bson.M, reason string) error {
+	filter["revoked_at"] = nil
+	_, err := s.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+		"revoked_at":     time.Now(),
+		"revoked_reason": reason,
+	}})
+	return err
+}
+
+func (s *RefreshStore) insert(ctx context.Context, userID, familyID string, parentID *primitive.ObjectID) (string, primitive.ObjectID, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", primitive.NilObjectID, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	now := time.Now()
+	doc := RefreshToken{
+		ID:        primitive.NewObjectID(),
+		TokenHash: hashToken(token),
+		UserID:    userID,
+		FamilyID:  familyID,
+		ParentID:  parentID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	if _, err := s.collection.InsertOne(ctx, doc); err != nil {
+		return "", primitive.NilObjectID, err
+	}
+	return token, doc.ID, nil
+}
+
+// Token có entropy cao nên SHA-256 là đủ, không cần hash chậm như mật khẩu
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
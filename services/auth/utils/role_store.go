@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"thaily/services/auth/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultRoleName is the role of users that have neither role_id nor a role
+// name
+const DefaultRoleName = "user"
+
+var (
+	ErrRoleNotFound = errors.New("role not found")
+	ErrRoleExists   = errors.New("a role with this name already exists")
+	ErrRoleInUse    = errors.New("the role is still assigned to users")
+)
+
+// Tên vai trò không phân biệt hoa thường, giống schema.Unique của roles
+var roleNameCollation = &options.Collation{Locale: "en", Strength: 2}
+
+// RoleStore reads and writes the roles collection
+type RoleStore struct {
+	roles *mongo.Collection
+	users *mongo.Collection
+}
+
+func NewRoleStore(db *mongo.Database) *RoleStore {
+	return &RoleStore{
+		roles: db.Collection(models.RolesCollection),
+		users: db.Collection(models.UsersCollection),
+	}
+}
+
+func (s *RoleStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.roles.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}},
+		Options: options.Index().SetUnique(true).SetCollation(roleNameCollation),
+	})
+	return err
+}
+
+func (s *RoleStore) List(ctx context.Context) ([]models.Role, error) {
+	cursor, err := s.roles.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	roles := []models.Role{}
+	if err := cursor.All(ctx, &roles); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (s *RoleStore) Get(ctx context.Context, id primitive.ObjectID) (*models.Role, error) {
+	return s.findOne(ctx, bson.M{"_id": id})
+}
+
+func (s *RoleStore) FindByName(ctx context.Context, name string) (*models.Role, error) {
+	return s.findOne(ctx, bson.M{"name": strings.TrimSpace(name)}, options.FindOne().SetCollation(roleNameCollation))
+}
+
+func (s *RoleStore) findOne(ctx context.Context, filter bson.M, opts ...*options.FindOneOptions) (*models.Role, error) {
+	var role models.Role
+	err := s.roles.FindOne(ctx, filter, opts...).Decode(&role)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (s *RoleStore) Create(ctx context.Context, name, description string, permissions []string) (*models.Role, error) {
+	now := time.Now()
+	role := &models.Role{
+		ID:          primitive.NewObjectID(),
+		Name:        strings.TrimSpace(name),
+		Description: strings.TrimSpace(description),
+		Permissions: NormalizePermissions(permissions),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	_, err := s.roles.InsertOne(ctx, role)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrRoleExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// Update replaces the name, description and permissions of a role
+func (s *RoleStore) Update(ctx context.Context, id primitive.ObjectID, name, description string, permissions []string) (*models.Role, error) {
+	var role models.Role
+	err := s.roles.FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{
+			"name":        strings.TrimSpace(name),
+			"description": strings.TrimSpace(description),
+			"permissions": NormalizePermissions(permissions),
+			"updatedAt":   time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&role)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrRoleExists
+	}
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrRoleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// Delete removes a role no user references, see schema.Relations
+func (s *RoleStore) Delete(ctx context.Context, id primitive.ObjectID) error {
+	count, err := s.users.CountDocuments(ctx, bson.M{"role_id": id}, options.Count().SetLimit(1))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+	result, err := s.roles.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrRoleNotFound
+	}
+	return nil
+}
+
+// ForUser resolves the role of a user document: role_id first, then the role
+// name stored by older accounts. A name without a roles document yields a
+// role without permissions.
+func (s *RoleStore) ForUser(ctx context.Context, user bson.M) (*models.Role, error) {
+	if id, ok := user["role_id"].(primitive.ObjectID); ok {
+		role, err := s.Get(ctx, id)
+		if err != ErrRoleNotFound {
+			return role, err
+		}
+	}
+
+	name, _ := user["roles"].(string)
+	if name == "" {
+		name, _ = user["role"].(string)
+	}
+	if name == "" {
+		name = DefaultRoleName
+	}
+	role, err := s.FindByName(ctx, name)
+	if err == ErrRoleNotFound {
+		return &models.Role{Name: name, Permissions: []string{}}, nil
+	}
+	return role, err
+}
+
+// NormalizePermissions trims, sorts and deduplicates permission names
+func NormalizePermissions(permissions []string) []string {
+	normalized := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if permission = strings.TrimSpace(permission); permission != "" {
+			normalized = append(normalized, permission)
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
@@ -0,0 +1,428 @@
+package utils
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"thaily/services/_common/authn"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const SigningKeysCollection = "signing_keys"
+
+// Signing algorithms of access tokens
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+	AlgHS256 = "HS256"
+)
+
+// Một key mới chỉ được dùng để ký sau khoảng này, để các service khác kịp
+// thấy nó trong JWKS (JWKSCache tải lại khi gặp kid lạ tối đa mỗi phút)
+const keyActivationDelay = 2 * time.Minute
+
+// Tiền tố của private key đã mã hóa bằng key-encryption key
+const sealedKeyPrefix = "aes-gcm:"
+
+// SigningKey is a document of signing_keys. PrivateKey is sealed with the
+// key-encryption key of the ring when one is configured; a plain PEM key
+// lets anyone able to read the collection mint tokens.
+type SigningKey struct {
+	ID         string    `bson:"_id"`
+	Algorithm  string    `bson:"algorithm"`
+	PrivateKey string    `bson:"private_key"`
+	CreatedAt  time.Time `bson:"createdAt"`
+	// ActivatesAt is when the key starts signing, it is published before
+	ActivatesAt time.Time `bson:"activates_at"`
+	// ExpiresAt is set once a newer key takes over; the key stays published
+	// until then so the tokens it signed can still be verified
+	ExpiresAt *time.Time `bson:"expires_at,omitempty"`
+
+	signer crypto.Signer
+}
+
+// KeyRingConfig controls key generation and rotation
+type KeyRingConfig struct {
+	// Algorithm of new keys, RS256 or EdDSA
+	Algorithm string
+	// Age after which the active key is replaced
+	Rotation time.Duration
+	// How long a replaced key stays published, at least the access token
+	// lifetime
+	Overlap time.Duration
+	// EncryptionKey seals the private keys stored in Mongo with AES-256-GCM,
+	// 32 bytes. Empty stores them as plain PEM.
+	EncryptionKey []byte
+}
+
+// KeyRing holds the asymmetric keys access tokens are signed with. Keys live
+// in Mongo so every instance of the service signs with the same active key.
+type KeyRing struct {
+	collection *mongo.Collection
+	config     KeyRingConfig
+
+	mu       sync.RWMutex
+	keys     []*SigningKey
+	loadedAt time.Time
+}
+
+func NewKeyRing(db *mongo.Database, config KeyRingConfig) (*KeyRing, error) {
+	if config.Algorithm != AlgRS256 && config.Algorithm != AlgEdDSA {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", config.Algorithm)
+	}
+	if config.Rotation <= 0 || config.Overlap <= 0 {
+		return nil, errors.New("key rotation and overlap must be positive")
+	}
+	if len(config.EncryptionKey) != 0 && len(config.EncryptionKey) != 32 {
+		return nil, errors.New("key encryption key must be 32 bytes")
+	}
+	return &KeyRing{collection: db.Collection(SigningKeysCollection), config: config}, nil
+}
+
+// EnsureIndexes creates the TTL index that drops expired keys
+func (k *KeyRing) EnsureIndexes(ctx context.Context) error {
+	_, err := k.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
+// Load reads the keys that are still published
+func (k *KeyRing) Load(ctx context.Context) error {
+	now := time.Now()
+	cursor, err := k.collection.Find(ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"expires_at": nil},
+			bson.M{"expires_at": bson.M{"$gt": now}},
+		}},
+		options.Find().SetSort(bson.D{{Key: "activates_at", Value: -1}}),
+	)
+	if err != nil {
+		return err
+	}
+	var keys []*SigningKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return err
+	}
+	loaded := keys[:0]
+	for _, key := range keys {
+		plain, err := k.open(key)
+		if err != nil {
+			log.Printf("Skipping signing key %s: %v", key.ID, err)
+			continue
+		}
+		signer, err := parsePrivateKey(plain)
+		if err != nil {
+			log.Printf("Skipping signing key %s: %v", key.ID, err)
+			continue
+		}
+		key.signer = signer
+		if err := k.sealStored(ctx, key, plain); err != nil {
+			log.Printf("Failed to encrypt signing key %s: %v", key.ID, err)
+		}
+		loaded = append(loaded, key)
+	}
+
+	k.mu.Lock()
+	k.keys = loaded
+	k.loadedAt = now
+	k.mu.Unlock()
+	return nil
+}
+
+// Rotate adds a new key when the active one is older than the rotation
+// period, or always with force. The keys it replaces expire after the
+// overlap window.
+func (k *KeyRing) Rotate(ctx context.Context, force bool) (bool, error) {
+	if err := k.Load(ctx); err != nil {
+		return false, err
+	}
+	now := time.Now()
+	active, pending := k.current(now)
+	if !force && (pending || (active != nil && now.Sub(active.ActivatesAt) < k.config.Rotation)) {
+		return false, nil
+	}
+
+	key, err := generateSigningKey(k.config.Algorithm)
+	if err != nil {
+		return false, err
+	}
+	if key.PrivateKey, err = k.seal(key.ID, key.PrivateKey); err != nil {
+		return false, err
+	}
+	key.CreatedAt = now
+	key.ActivatesAt = now
+	if active != nil {
+		key.ActivatesAt = now.Add(keyActivationDelay)
+	}
+	if _, err := k.collection.InsertOne(ctx, key); err != nil {
+		return false, err
+	}
+	// Chỉ hết hạn các key cũ hơn, nếu hai instance cùng xoay thì key mới nhất vẫn còn
+	expires := key.ActivatesAt.Add(k.config.Overlap)
+	_, err = k.collection.UpdateMany(ctx,
+		bson.M{"createdAt": bson.M{"$lt": key.CreatedAt}, "expires_at": nil},
+		bson.M{"$set": bson.M{"expires_at": expires}},
+	)
+	if err != nil {
+		return true, err
+	}
+	return true, k.Load(ctx)
+}
+
+// Run rotates the keys when due and picks up keys rotated by other
+// instances, until ctx is done
+func (k *KeyRing) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rotated, err := k.Rotate(ctx, false)
+			if err != nil {
+				log.Printf("Failed to rotate signing keys: %v", err)
+			} else if rotated {
+				log.Printf("Rotated the access token signing key")
+			}
+		}
+	}
+}
+
+// current returns the newest active key, and whether a newer key is waiting
+// for activation
+func (k *KeyRing) current(now time.Time) (active *SigningKey, pending bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	for _, key := range k.keys {
+		if key.ActivatesAt.After(now) {
+			pending = true
+			continue
+		}
+		return key, pending
+	}
+	return nil, pending
+}
+
+// Sign signs claims with the active key, its kid in the header
+func (k *KeyRing) Sign(ctx context.Context, claims jwt.Claims) (string, error) {
+	active, _ := k.current(time.Now())
+	if active == nil {
+		if _, err := k.Rotate(ctx, false); err != nil {
+			return "", err
+		}
+		if active, _ = k.current(time.Now()); active == nil {
+			return "", errors.New("no active signing key")
+		}
+	}
+
+	var method jwt.SigningMethod = jwt.SigningMethodRS256
+	if active.Algorithm == AlgEdDSA {
+		method = jwt.SigningMethodEdDSA
+	}
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = active.ID
+	return token.SignedString(active.signer)
+}
+
+// Key returns the public key of kid, reloading the ring at most every few
+// seconds when the kid is unknown, e.g. rotated by another instance
+func (k *KeyRing) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key := k.find(kid); key != nil {
+		return key.signer.Public(), nil
+	}
+	k.mu.RLock()
+	stale := time.Since(k.loadedAt) > 5*time.Second
+	k.mu.RUnlock()
+	if stale {
+		if err := k.Load(ctx); err != nil {
+			return nil, err
+		}
+		if key := k.find(kid); key != nil {
+			return key.signer.Public(), nil
+		}
+	}
+	return nil, authn.ErrUnknownKey
+}
+
+func (k *KeyRing) find(kid string) *SigningKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	for _, key := range k.keys {
+		if key.ID == kid {
+			return key
+		}
+	}
+	return nil
+}
+
+// JWKS returns the public keys of the ring, including those not yet active
+// and those replaced but not expired
+func (k *KeyRing) JWKS() authn.JWKS {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	set := authn.JWKS{Keys: []authn.JWK{}}
+	for _, key := range k.keys {
+		jwk, err := authn.NewJWK(key.ID, key.Algorithm, key.signer.Public())
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// seal encrypts a PEM private key with the key-encryption key, bound to kid
+func (k *KeyRing) seal(kid, plain string) (string, error) {
+	if len(k.config.EncryptionKey) == 0 {
+		return plain, nil
+	}
+	aead, err := newAEAD(k.config.EncryptionKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plain), []byte(kid))
+	return sealedKeyPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// open returns the PEM private key of a stored key, decrypting it if sealed
+func (k *KeyRing) open(key *SigningKey) (string, error) {
+	if !strings.HasPrefix(key.PrivateKey, sealedKeyPrefix) {
+		return key.PrivateKey, nil
+	}
+	if len(k.config.EncryptionKey) == 0 {
+		return "", errors.New("key is encrypted but no key encryption key is configured")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(key.PrivateKey, sealedKeyPrefix))
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(k.config.EncryptionKey)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("encrypted key is too short")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(key.ID))
+	if err != nil {
+		return "", errors.New("cannot decrypt key, wrong key encryption key?")
+	}
+	return string(plain), nil
+}
+
+// sealStored encrypts a key still stored as plain PEM, once a key-encryption
+// key is configured
+func (k *KeyRing) sealStored(ctx context.Context, key *SigningKey, plain string) error {
+	if len(k.config.EncryptionKey) == 0 || strings.HasPrefix(key.PrivateKey, sealedKeyPrefix) {
+		return nil
+	}
+	sealed, err := k.seal(key.ID, plain)
+	if err != nil {
+		return err
+	}
+	_, err = k.collection.UpdateOne(ctx,
+		bson.M{"_id": key.ID, "private_key": key.PrivateKey},
+		bson.M{"$set": bson.M{"private_key": sealed}},
+	)
+	if err != nil {
+		return err
+	}
+	key.PrivateKey = sealed
+	return nil
+}
+
+func newAEAD(kek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// ParseEncryptionKey decodes a base64 key-encryption key of 32 bytes
+func ParseEncryptionKey(value string) ([]byte, error) {
+	if value == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("key encryption key must be base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key encryption key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+func generateSigningKey(algorithm string) (*SigningKey, error) {
+	var signer crypto.Signer
+	var err error
+	switch algorithm {
+	case AlgRS256:
+		signer, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgEdDSA:
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return nil, err
+	}
+	kid, err := RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+	return &SigningKey{
+		ID:         kid,
+		Algorithm:  algorithm,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		signer:     signer,
+	}, nil
+}
+
+func parsePrivateKey(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("invalid PEM")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported private key %T", key)
+}